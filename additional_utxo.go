@@ -0,0 +1,317 @@
+package blockfrost
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/ledger"
+	"github.com/blockfrost/blockfrost-go/nativescript"
+)
+
+// ErrUnresolvedScript is returned when converting a UTXO whose reference
+// script is not in the given ReferenceScripts.
+var ErrUnresolvedScript = errors.New("unresolved reference script")
+
+// ScriptLanguage is the language of a ReferenceScript.
+type ScriptLanguage string
+
+const (
+	ScriptLanguageNative   ScriptLanguage = "native"
+	ScriptLanguagePlutusV1 ScriptLanguage = "plutus:v1"
+	ScriptLanguagePlutusV2 ScriptLanguage = "plutus:v2"
+	ScriptLanguagePlutusV3 ScriptLanguage = "plutus:v3"
+)
+
+// ReferenceScript is a script attached to an output of an
+// AdditionalUtxoSet. It is marshalled to the script format of Ogmios.
+type ReferenceScript struct {
+	Language ScriptLanguage
+
+	// Native is the script of ScriptLanguageNative scripts.
+	Native *nativescript.Script
+
+	// CBOR is the hex encoded serialized script of Plutus scripts, as
+	// returned by ScriptCBOR.
+	CBOR string
+}
+
+// NativeReferenceScript returns the reference script of a native script.
+func NativeReferenceScript(s nativescript.Script) ReferenceScript {
+	return ReferenceScript{Language: ScriptLanguageNative, Native: &s}
+}
+
+// PlutusReferenceScript returns the reference script of a Plutus script of
+// language lang, given as hex encoded serialized script.
+func PlutusReferenceScript(lang ScriptLanguage, cbor string) ReferenceScript {
+	return ReferenceScript{Language: lang, CBOR: cbor}
+}
+
+// ReferenceScriptFromLedger returns the reference script of s, as held by
+// ledger.TransactionOutput.ScriptRef.
+func ReferenceScriptFromLedger(s ledger.Script) (ReferenceScript, error) {
+	switch s.Type {
+	case ledger.ScriptNative:
+		ns, err := nativescript.FromLedger(s)
+		if err != nil {
+			return ReferenceScript{}, err
+		}
+		return NativeReferenceScript(*ns), nil
+	case ledger.ScriptPlutusV1:
+		return PlutusReferenceScript(ScriptLanguagePlutusV1, hex.EncodeToString(s.Bytes)), nil
+	case ledger.ScriptPlutusV2:
+		return PlutusReferenceScript(ScriptLanguagePlutusV2, hex.EncodeToString(s.Bytes)), nil
+	case ledger.ScriptPlutusV3:
+		return PlutusReferenceScript(ScriptLanguagePlutusV3, hex.EncodeToString(s.Bytes)), nil
+	}
+	return ReferenceScript{}, fmt.Errorf("unknown script type %d", s.Type)
+}
+
+// MarshalJSON marshals s as {"native": <script>} or {"plutus:v2": <cbor>}.
+func (s ReferenceScript) MarshalJSON() ([]byte, error) {
+	switch s.Language {
+	case ScriptLanguageNative:
+		if s.Native == nil {
+			return nil, errors.New("native reference script without script")
+		}
+		native, err := ogmiosNativeScript(*s.Native)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]interface{}{string(s.Language): native})
+	case ScriptLanguagePlutusV1, ScriptLanguagePlutusV2, ScriptLanguagePlutusV3:
+		return json.Marshal(map[string]string{string(s.Language): s.CBOR})
+	}
+	return nil, fmt.Errorf("unknown script language %q", s.Language)
+}
+
+// ogmiosNativeScript returns the native script s in the JSON format of
+// Ogmios.
+func ogmiosNativeScript(s nativescript.Script) (interface{}, error) {
+	nested := func() ([]interface{}, error) {
+		out := make([]interface{}, len(s.Scripts))
+		for i, sub := range s.Scripts {
+			v, err := ogmiosNativeScript(sub)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+	switch s.Type {
+	case nativescript.TypeSig:
+		return s.KeyHash.String(), nil
+	case nativescript.TypeAll, nativescript.TypeAny:
+		scripts, err := nested()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{s.Type.String(): scripts}, nil
+	case nativescript.TypeAtLeast:
+		scripts, err := nested()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{strconv.Itoa(s.Required): scripts}, nil
+	case nativescript.TypeAfter:
+		return map[string]uint64{"startsAt": s.Slot}, nil
+	case nativescript.TypeBefore:
+		return map[string]uint64{"expiresAt": s.Slot}, nil
+	}
+	return nil, fmt.Errorf("unknown native script type %s", s.Type)
+}
+
+// ReferenceScripts maps script hashes to scripts, and resolves the
+// reference scripts of UTXOs, which API responses only hold the hash of.
+type ReferenceScripts map[string]ReferenceScript
+
+// AdditionalUtxo is an entry of an AdditionalUtxoSet.
+type AdditionalUtxo struct {
+	TxIn  AdditionalUtxoSetTxIn  `json:"txIn"`
+	TxOut AdditionalUtxoSetTxOut `json:"txOut"`
+}
+
+// additionalUtxo returns the UTXO at index of txHash. An inline datum takes
+// precedence over dataHash, which the API sets to the hash of inline
+// datums too.
+func additionalUtxo(txHash string, index int, addr string, amounts []AddressAmount, dataHash, inlineDatum, scriptHash *string, scripts ReferenceScripts) (AdditionalUtxo, error) {
+	u := AdditionalUtxo{
+		TxIn:  AdditionalUtxoSetTxIn{TxID: txHash, Index: index},
+		TxOut: AdditionalUtxoSetTxOut{Address: addr},
+	}
+	for _, a := range amounts {
+		if a.Unit == LovelaceUnit {
+			u.TxOut.Value.Coins = Quantity(a.Quantity)
+			continue
+		}
+		if u.TxOut.Value.Assets == nil {
+			u.TxOut.Value.Assets = map[string]Quantity{}
+		}
+		u.TxOut.Value.Assets[ogmiosAssetKey(a.Unit)] = Quantity(a.Quantity)
+	}
+	switch {
+	case inlineDatum != nil:
+		u.TxOut.Datum = *inlineDatum
+	case dataHash != nil:
+		h := *dataHash
+		u.TxOut.DatumHash = &h
+	}
+	if scriptHash != nil {
+		s, ok := scripts[*scriptHash]
+		if !ok {
+			return AdditionalUtxo{}, fmt.Errorf("%w: %s of %s#%d", ErrUnresolvedScript, *scriptHash, txHash, index)
+		}
+		var ts TxOutScript = s
+		u.TxOut.Script = &ts
+	}
+	return u, nil
+}
+
+// ogmiosAssetKey returns the asset key of unit in Ogmios values,
+// "<policy id>.<asset name>" or "<policy id>" for empty asset names.
+func ogmiosAssetKey(unit string) string {
+	const policyLen = 2 * policyIDSize
+	if len(unit) <= policyLen {
+		return unit
+	}
+	return unit[:policyLen] + "." + unit[policyLen:]
+}
+
+func txAmounts(amounts []TxAmount) []AddressAmount {
+	out := make([]AddressAmount, len(amounts))
+	for i, a := range amounts {
+		out[i] = AddressAmount{Unit: a.Unit, Quantity: a.Quantity}
+	}
+	return out
+}
+
+// AdditionalUtxo converts u, resolving its reference script from scripts.
+func (u AddressUTXO) AdditionalUtxo(scripts ReferenceScripts) (AdditionalUtxo, error) {
+	return additionalUtxo(u.TxHash, u.OutputIndex, u.Address, u.Amount, u.DataHash, u.InlineDatum, u.ReferenceScriptHash, scripts)
+}
+
+// AdditionalUtxo converts the UTXO spent by i, resolving its reference
+// script from scripts.
+func (i TransactionInput) AdditionalUtxo(scripts ReferenceScripts) (AdditionalUtxo, error) {
+	return additionalUtxo(i.TxHash, int(i.OutputIndex), i.Address, txAmounts(i.Amount), i.DataHash, i.InlineDatum, i.ReferenceScriptHash, scripts)
+}
+
+// AdditionalUtxo converts o, an output of the transaction txHash,
+// resolving its reference script from scripts.
+func (o TransactionOutput) AdditionalUtxo(txHash string, scripts ReferenceScripts) (AdditionalUtxo, error) {
+	return additionalUtxo(txHash, o.OutputIndex, o.Address, txAmounts(o.Amount), o.DataHash, o.InlineDatum, o.ReferenceScriptHash, scripts)
+}
+
+// AdditionalUtxo converts o, an output of the transaction txHash,
+// resolving its reference script from scripts.
+func (o MempoolTransactionOutput) AdditionalUtxo(txHash string, scripts ReferenceScripts) (AdditionalUtxo, error) {
+	return additionalUtxo(txHash, o.OutputIndex, o.Address, txAmounts(o.Amount), o.DataHash, o.InlineDatum, o.ReferenceScriptHash, scripts)
+}
+
+// AdditionalUtxoSet converts the UTXOs created by the transaction. Its
+// collateral return output, only created if the transaction failed
+// phase-2 validation, is not included.
+func (tu TransactionUTXOs) AdditionalUtxoSet(scripts ReferenceScripts) (AdditionalUtxoSet, error) {
+	set := AdditionalUtxoSet{}
+	for _, o := range tu.Outputs {
+		if o.Collateral {
+			continue
+		}
+		u, err := o.AdditionalUtxo(tu.Hash, scripts)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, u)
+	}
+	return set, nil
+}
+
+// AdditionalUtxoSet converts the UTXOs created by the mempool transaction,
+// which are not on chain yet, so that transactions spending them can be
+// evaluated.
+func (m MempoolTransactionContent) AdditionalUtxoSet(scripts ReferenceScripts) (AdditionalUtxoSet, error) {
+	set := AdditionalUtxoSet{}
+	for _, o := range m.Outputs {
+		if o.Collateral {
+			continue
+		}
+		u, err := o.AdditionalUtxo(m.Tx.Hash, scripts)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, u)
+	}
+	return set, nil
+}
+
+// AdditionalUtxoSetFromAddressUTXOs converts utxos, resolving their
+// reference scripts from scripts.
+func AdditionalUtxoSetFromAddressUTXOs(utxos []AddressUTXO, scripts ReferenceScripts) (AdditionalUtxoSet, error) {
+	set := make(AdditionalUtxoSet, 0, len(utxos))
+	for _, u := range utxos {
+		au, err := u.AdditionalUtxo(scripts)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, au)
+	}
+	return set, nil
+}
+
+// NewAdditionalUtxo converts the output at index of the transaction txHash,
+// such as an output of a locally built transaction.
+func NewAdditionalUtxo(txHash string, index int, o ledger.TransactionOutput) (AdditionalUtxo, error) {
+	addr, err := address.FromBytes(o.Address)
+	if err != nil {
+		return AdditionalUtxo{}, err
+	}
+	amounts := []AddressAmount{{Unit: LovelaceUnit, Quantity: strconv.FormatUint(o.Amount.Coin, 10)}}
+	for policy, assets := range o.Amount.Assets {
+		for name, q := range assets {
+			amounts = append(amounts, AddressAmount{
+				Unit:     policy.String() + hex.EncodeToString([]byte(name)),
+				Quantity: strconv.FormatUint(q, 10),
+			})
+		}
+	}
+
+	var dataHash, inlineDatum, scriptHash *string
+	if o.DatumHash != nil {
+		h := o.DatumHash.String()
+		dataHash = &h
+	}
+	if o.Datum != nil {
+		d := hex.EncodeToString(o.Datum)
+		inlineDatum = &d
+	}
+	var scripts ReferenceScripts
+	if o.ScriptRef != nil {
+		s, err := ReferenceScriptFromLedger(*o.ScriptRef)
+		if err != nil {
+			return AdditionalUtxo{}, err
+		}
+		h := o.ScriptRef.Hash().String()
+		scriptHash, scripts = &h, ReferenceScripts{h: s}
+	}
+	return additionalUtxo(txHash, index, addr.String(), amounts, dataHash, inlineDatum, scriptHash, scripts)
+}
+
+// AdditionalUtxoSetFromTransaction converts the outputs of tx, such as a
+// locally built transaction that is not submitted yet, so that
+// transactions chained to it can be evaluated.
+func AdditionalUtxoSetFromTransaction(tx *ledger.Transaction) (AdditionalUtxoSet, error) {
+	hash := tx.Hash().String()
+	set := make(AdditionalUtxoSet, 0, len(tx.Body.Outputs))
+	for i, o := range tx.Body.Outputs {
+		u, err := NewAdditionalUtxo(hash, i, o)
+		if err != nil {
+			return nil, fmt.Errorf("output %d: %w", i, err)
+		}
+		set = append(set, u)
+	}
+	return set, nil
+}
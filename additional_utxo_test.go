@@ -0,0 +1,162 @@
+package blockfrost_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/ledger"
+	"github.com/blockfrost/blockfrost-go/nativescript"
+)
+
+func strPtr(s string) *string { return &s }
+
+func marshalString(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestAddressUTXOAdditionalUtxo(t *testing.T) {
+	policy := strings.Repeat("ab", 28)
+	scriptHash := strings.Repeat("cd", 28)
+	u := blockfrost.AddressUTXO{
+		Address:     "addr_test1vq",
+		TxHash:      "ec6e",
+		OutputIndex: 1,
+		Amount: []blockfrost.AddressAmount{
+			{Unit: "lovelace", Quantity: "2000000"},
+			{Unit: policy + "636f696e", Quantity: "5"},
+			{Unit: policy, Quantity: "1"},
+		},
+		// The API sets the data hash of inline datums too.
+		DataHash:            strPtr("ee"),
+		InlineDatum:         strPtr("d87980"),
+		ReferenceScriptHash: strPtr(scriptHash),
+	}
+	scripts := blockfrost.ReferenceScripts{scriptHash: blockfrost.PlutusReferenceScript(blockfrost.ScriptLanguagePlutusV2, "4e4d01000033222220051200120011")}
+	got, err := u.AdditionalUtxo(scripts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"txIn":{"txId":"ec6e","index":1},"txOut":{"address":"addr_test1vq","value":{"coins":2000000,"assets":{"` + policy + `":1,"` + policy + `.636f696e":5}},"datum":"d87980","script":{"plutus:v2":"4e4d01000033222220051200120011"}}}`
+	if s := marshalString(t, got); s != want {
+		t.Fatalf("got %s\nwant %s", s, want)
+	}
+
+	u.InlineDatum, u.ReferenceScriptHash = nil, nil
+	got, err = u.AdditionalUtxo(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TxOut.DatumHash == nil || *got.TxOut.DatumHash != "ee" || got.TxOut.Datum != nil || got.TxOut.Script != nil {
+		t.Fatalf("unexpected output %+v", got.TxOut)
+	}
+
+	u.ReferenceScriptHash = strPtr(scriptHash)
+	if _, err := blockfrost.AdditionalUtxoSetFromAddressUTXOs([]blockfrost.AddressUTXO{u}, nil); !errors.Is(err, blockfrost.ErrUnresolvedScript) {
+		t.Fatalf("got %v, want ErrUnresolvedScript", err)
+	}
+}
+
+func TestTransactionUTXOsAdditionalUtxoSet(t *testing.T) {
+	tu := blockfrost.TransactionUTXOs{
+		Hash: "ec6e",
+		Outputs: []blockfrost.TransactionOutput{
+			{Address: "addr1", OutputIndex: 0, Amount: []blockfrost.TxAmount{{Unit: "lovelace", Quantity: "1"}}},
+			{Address: "addr2", OutputIndex: 1, Amount: []blockfrost.TxAmount{{Unit: "lovelace", Quantity: "2"}}, Collateral: true},
+			{Address: "addr3", OutputIndex: 2, Amount: []blockfrost.TxAmount{{Unit: "lovelace", Quantity: "3"}}, DataHash: strPtr("ee")},
+		},
+	}
+	set, err := tu.AdditionalUtxoSet(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"txIn":{"txId":"ec6e","index":0},"txOut":{"address":"addr1","value":{"coins":1}}},{"txIn":{"txId":"ec6e","index":2},"txOut":{"address":"addr3","value":{"coins":3},"datum_hash":"ee"}}]`
+	if s := marshalString(t, set); s != want {
+		t.Fatalf("got %s\nwant %s", s, want)
+	}
+}
+
+func TestNewAdditionalUtxo(t *testing.T) {
+	cred, err := address.CredentialFromHex(address.CredentialKey, strings.Repeat("01", 28))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := address.NewEnterpriseAddress(address.Testnet, cred)
+	policy, err := ledger.Hash28FromHex(strings.Repeat("ab", 28))
+	if err != nil {
+		t.Fatal(err)
+	}
+	native := nativescript.AtLeast(1, nativescript.Sig(cred.Hash), nativescript.All(nativescript.After(10), nativescript.Before(20)))
+	ref, err := native.Ledger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := ledger.NewTransactionOutput(addr, ledger.Value{Coin: 3000000, Assets: ledger.MultiAsset{policy: {"coin": 7}}})
+	out.Datum = []byte{0xd8, 0x79, 0x80}
+	out.ScriptRef = &ref
+	got, err := blockfrost.NewAdditionalUtxo("ec6e", 3, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"txIn":{"txId":"ec6e","index":3},"txOut":{"address":"` + addr.String() + `","value":{"coins":3000000,"assets":{"` + policy.String() + `.636f696e":7}},"datum":"d87980","script":{"native":{"1":["` + strings.Repeat("01", 28) + `",{"all":[{"startsAt":10},{"expiresAt":20}]}]}}}}`
+	if s := marshalString(t, got); s != want {
+		t.Fatalf("got %s\nwant %s", s, want)
+	}
+
+	tx, err := ledger.DecodeTransactionHex(string(loadTransactionCBOR(t, "tx_cbor_conway.json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := blockfrost.AdditionalUtxoSetFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != len(tx.Body.Outputs) || set[0].TxIn.TxID != tx.Hash().String() {
+		t.Fatalf("unexpected set %+v", set)
+	}
+}
+
+func TestReferenceScripts(t *testing.T) {
+	native := strings.Repeat("aa", 28)
+	plutus := strings.Repeat("bb", 28)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/scripts/" + native:
+			w.Write([]byte(`{"script_hash":"` + native + `","type":"timelock"}`))
+		case "/scripts/" + native + "/json":
+			w.Write([]byte(`{"json":{"type":"before","slot":20}}`))
+		case "/scripts/" + plutus:
+			w.Write([]byte(`{"script_hash":"` + plutus + `","type":"plutusV3","serialised_size":15}`))
+		case "/scripts/" + plutus + "/cbor":
+			w.Write([]byte(`{"cbor":"4e4d01000033222220051200120011"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status_code":404,"error":"Not Found","message":"not found"}`))
+		}
+	}))
+	defer s.Close()
+	api := blockfrost.NewAPIClient(blockfrost.APIClientOptions{Server: s.URL, Client: &http.Client{}})
+
+	scripts, err := api.ReferenceScripts(context.TODO(), native, plutus, native)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := marshalString(t, scripts), `{"`+native+`":{"native":{"expiresAt":20}},"`+plutus+`":{"plutus:v3":"4e4d01000033222220051200120011"}}`; got != want {
+		t.Fatalf("got %s\nwant %s", got, want)
+	}
+	if _, err := api.ReferenceScripts(context.TODO(), strings.Repeat("cc", 28)); err == nil {
+		t.Fatal("expected error")
+	}
+}
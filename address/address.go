@@ -0,0 +1,383 @@
+// Package address parses, validates and converts Cardano addresses offline.
+//
+// Shelley era addresses are handled in their Bech32 (CIP-5) and raw forms,
+// as described in CIP-19. Byron era addresses are handled in their Base58
+// form, including their CRC32 checksum.
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blockfrost/blockfrost-go/internal/bech32"
+)
+
+// HashSize is the size in bytes of key and script hashes used as credentials.
+const HashSize = 28
+
+// Network identifies the network an address belongs to.
+type Network byte
+
+const (
+	Testnet Network = 0
+	Mainnet Network = 1
+)
+
+// Type is the header type of an address, see CIP-19.
+type Type byte
+
+const (
+	TypeBaseKeyKey       Type = 0x0
+	TypeBaseScriptKey    Type = 0x1
+	TypeBaseKeyScript    Type = 0x2
+	TypeBaseScriptScript Type = 0x3
+	TypePointerKey       Type = 0x4
+	TypePointerScript    Type = 0x5
+	TypeEnterpriseKey    Type = 0x6
+	TypeEnterpriseScript Type = 0x7
+	TypeByron            Type = 0x8
+	TypeRewardKey        Type = 0xe
+	TypeRewardScript     Type = 0xf
+)
+
+// Kind groups address types by their structure.
+type Kind string
+
+const (
+	KindBase       Kind = "base"
+	KindPointer    Kind = "pointer"
+	KindEnterprise Kind = "enterprise"
+	KindReward     Kind = "reward"
+	KindByron      Kind = "byron"
+)
+
+// Kind returns the kind of the address type.
+func (t Type) Kind() Kind {
+	switch t {
+	case TypeBaseKeyKey, TypeBaseScriptKey, TypeBaseKeyScript, TypeBaseScriptScript:
+		return KindBase
+	case TypePointerKey, TypePointerScript:
+		return KindPointer
+	case TypeEnterpriseKey, TypeEnterpriseScript:
+		return KindEnterprise
+	case TypeRewardKey, TypeRewardScript:
+		return KindReward
+	case TypeByron:
+		return KindByron
+	}
+	return ""
+}
+
+// Bech32 human readable prefixes
+const (
+	PrefixAddr           = "addr"
+	PrefixAddrTest       = "addr_test"
+	PrefixStake          = "stake"
+	PrefixStakeTest      = "stake_test"
+	mainnetProtocolMagic = 764824073
+)
+
+var (
+	ErrInvalidChecksum = errors.New("address: invalid checksum")
+	ErrInvalidLength   = errors.New("address: invalid length")
+	ErrInvalidPrefix   = errors.New("address: invalid bech32 prefix")
+	ErrInvalidType     = errors.New("address: invalid header type")
+	ErrNetworkMismatch = errors.New("address: prefix does not match network")
+	ErrInvalidEncoding = errors.New("address: neither bech32 nor base58")
+)
+
+// CredentialType tells whether a credential is a key hash or a script hash.
+type CredentialType byte
+
+const (
+	CredentialKey    CredentialType = 0
+	CredentialScript CredentialType = 1
+)
+
+func (t CredentialType) String() string {
+	if t == CredentialScript {
+		return "script"
+	}
+	return "key"
+}
+
+// Credential is a payment or stake credential.
+type Credential struct {
+	Type CredentialType
+	Hash [HashSize]byte
+}
+
+// IsZero reports whether the credential is unset.
+func (c Credential) IsZero() bool {
+	return c == Credential{}
+}
+
+// String returns the hex encoded hash of the credential.
+func (c Credential) String() string {
+	return hex.EncodeToString(c.Hash[:])
+}
+
+// Pointer locates the stake registration certificate referenced by a
+// pointer address.
+type Pointer struct {
+	Slot      uint64
+	TxIndex   uint64
+	CertIndex uint64
+}
+
+// Address is a parsed Cardano address.
+type Address struct {
+	// Header type of the address
+	Type Type
+
+	// Network ID from the address header. For Byron addresses it is derived
+	// from the protocol magic attribute.
+	Network Network
+
+	// Payment credential, unset for reward and Byron addresses
+	Payment Credential
+
+	// Stake credential, set for base and reward addresses
+	Stake Credential
+
+	// Stake pointer, set for pointer addresses
+	Pointer Pointer
+
+	// Byron specific content, set for Byron addresses
+	Byron *ByronAttributes
+
+	raw []byte
+}
+
+// Parse parses an address in either its Bech32 or Byron Base58 form. Bech32
+// addresses may be all lowercase or all uppercase.
+func Parse(s string) (Address, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	if strings.HasPrefix(lower, PrefixAddr) || strings.HasPrefix(lower, PrefixStake) {
+		return ParseBech32(s)
+	}
+	return parseByron(s)
+}
+
+// ParseBech32 parses a Shelley era address in its Bech32 form and checks that
+// its prefix matches its header.
+func ParseBech32(s string) (Address, error) {
+	hrp, data, err := bech32.Decode(s)
+	if err != nil {
+		if errors.Is(err, bech32.ErrInvalidChecksum) {
+			return Address{}, ErrInvalidChecksum
+		}
+		return Address{}, fmt.Errorf("address: %w", err)
+	}
+	a, err := FromBytes(data)
+	if err != nil {
+		return Address{}, err
+	}
+	if a.Type == TypeByron {
+		return Address{}, ErrInvalidType
+	}
+	switch hrp {
+	case PrefixAddr, PrefixAddrTest:
+		if a.Type.Kind() == KindReward {
+			return Address{}, ErrInvalidPrefix
+		}
+	case PrefixStake, PrefixStakeTest:
+		if a.Type.Kind() != KindReward {
+			return Address{}, ErrInvalidPrefix
+		}
+	default:
+		return Address{}, ErrInvalidPrefix
+	}
+	if hrp != a.hrp() {
+		return Address{}, ErrNetworkMismatch
+	}
+	return a, nil
+}
+
+// ParseHex parses an address from the hex encoding of its raw bytes.
+func ParseHex(s string) (Address, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return Address{}, fmt.Errorf("address: %w", err)
+	}
+	return FromBytes(b)
+}
+
+// FromBytes parses an address from its raw bytes. Byron addresses are
+// expected in their CBOR form.
+func FromBytes(b []byte) (Address, error) {
+	if len(b) == 0 {
+		return Address{}, ErrInvalidLength
+	}
+	a := Address{
+		Type:    Type(b[0] >> 4),
+		Network: Network(b[0] & 0x0f),
+		raw:     append([]byte(nil), b...),
+	}
+	body := b[1:]
+	switch a.Type {
+	case TypeBaseKeyKey, TypeBaseScriptKey, TypeBaseKeyScript, TypeBaseScriptScript:
+		if len(body) != 2*HashSize {
+			return Address{}, ErrInvalidLength
+		}
+		a.Payment = credential(a.Type&0x1 != 0, body[:HashSize])
+		a.Stake = credential(a.Type&0x2 != 0, body[HashSize:])
+	case TypePointerKey, TypePointerScript:
+		if len(body) < HashSize {
+			return Address{}, ErrInvalidLength
+		}
+		a.Payment = credential(a.Type&0x1 != 0, body[:HashSize])
+		p, err := decodePointer(body[HashSize:])
+		if err != nil {
+			return Address{}, err
+		}
+		a.Pointer = p
+	case TypeEnterpriseKey, TypeEnterpriseScript:
+		if len(body) != HashSize {
+			return Address{}, ErrInvalidLength
+		}
+		a.Payment = credential(a.Type&0x1 != 0, body)
+	case TypeRewardKey, TypeRewardScript:
+		if len(body) != HashSize {
+			return Address{}, ErrInvalidLength
+		}
+		a.Stake = credential(a.Type&0x1 != 0, body)
+	case TypeByron:
+		return fromByronBytes(b)
+	default:
+		return Address{}, ErrInvalidType
+	}
+	return a, nil
+}
+
+// Validate returns an error describing why s is not a valid address.
+func Validate(s string) error {
+	_, err := Parse(s)
+	return err
+}
+
+// IsValid reports whether s is a valid Bech32 or Byron address.
+func IsValid(s string) bool {
+	return Validate(s) == nil
+}
+
+// Bech32ToHex converts a Bech32 address to the hex encoding of its raw bytes.
+func Bech32ToHex(s string) (string, error) {
+	a, err := ParseBech32(s)
+	if err != nil {
+		return "", err
+	}
+	return a.Hex(), nil
+}
+
+// HexToBech32 converts the hex encoding of a Shelley era address to its
+// Bech32 form.
+func HexToBech32(s string) (string, error) {
+	a, err := ParseHex(s)
+	if err != nil {
+		return "", err
+	}
+	if a.Type == TypeByron {
+		return "", ErrInvalidType
+	}
+	return a.String(), nil
+}
+
+// Bytes returns the raw bytes of the address.
+func (a Address) Bytes() []byte {
+	return append([]byte(nil), a.raw...)
+}
+
+// Hex returns the hex encoding of the raw bytes of the address.
+func (a Address) Hex() string {
+	return hex.EncodeToString(a.raw)
+}
+
+// String returns the Bech32 form of Shelley era addresses and the Base58
+// form of Byron addresses.
+func (a Address) String() string {
+	if a.Type == TypeByron {
+		return byronString(a.raw)
+	}
+	s, err := bech32.Encode(a.hrp(), a.raw)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// Equal reports whether both addresses have the same raw bytes.
+func (a Address) Equal(b Address) bool {
+	return bytes.Equal(a.raw, b.raw)
+}
+
+// Kind returns the kind of the address.
+func (a Address) Kind() Kind {
+	return a.Type.Kind()
+}
+
+// IsScript reports whether the payment part of the address, or the stake
+// part for reward addresses, is a script.
+func (a Address) IsScript() bool {
+	switch a.Type.Kind() {
+	case KindReward:
+		return a.Stake.Type == CredentialScript
+	case KindByron:
+		return false
+	}
+	return a.Payment.Type == CredentialScript
+}
+
+func (a Address) hrp() string {
+	if a.Type.Kind() == KindReward {
+		if a.Network == Mainnet {
+			return PrefixStake
+		}
+		return PrefixStakeTest
+	}
+	if a.Network == Mainnet {
+		return PrefixAddr
+	}
+	return PrefixAddrTest
+}
+
+func credential(script bool, hash []byte) Credential {
+	c := Credential{Type: CredentialKey}
+	if script {
+		c.Type = CredentialScript
+	}
+	copy(c.Hash[:], hash)
+	return c
+}
+
+// decodePointer decodes the three variable length natural numbers of a
+// pointer address.
+func decodePointer(b []byte) (Pointer, error) {
+	var values [3]uint64
+	for i := range values {
+		var v uint64
+		for {
+			if len(b) == 0 {
+				return Pointer{}, ErrInvalidLength
+			}
+			if v > (1<<64-1)>>7 {
+				return Pointer{}, fmt.Errorf("address: pointer value overflows")
+			}
+			c := b[0]
+			b = b[1:]
+			v = v<<7 | uint64(c&0x7f)
+			if c&0x80 == 0 {
+				break
+			}
+		}
+		values[i] = v
+	}
+	if len(b) != 0 {
+		return Pointer{}, ErrInvalidLength
+	}
+	return Pointer{Slot: values[0], TxIndex: values[1], CertIndex: values[2]}, nil
+}
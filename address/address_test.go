@@ -0,0 +1,193 @@
+package address_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go/address"
+)
+
+const (
+	paymentKeyHash = "9493315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e"
+	stakeKeyHash   = "337b62cfff6403a06a3acbc34f8c46003c69fe79a3628cefa9c47251"
+	scriptHash     = "c37b1b5dc0669f1d3c61a6fddb2e8fde96be87b881c60bce8e8d542f"
+)
+
+// Test vectors from CIP-19
+func TestParseBech32(t *testing.T) {
+	tests := []struct {
+		addr    string
+		typ     address.Type
+		network address.Network
+		payment string
+		stake   string
+	}{
+		{
+			"addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x",
+			address.TypeBaseKeyKey, address.Mainnet, paymentKeyHash, stakeKeyHash,
+		},
+		{
+			"addr1z8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gten0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgs9yc0hh",
+			address.TypeBaseScriptKey, address.Mainnet, scriptHash, stakeKeyHash,
+		},
+		{
+			"addr1yx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerkr0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shs2z78ve",
+			address.TypeBaseKeyScript, address.Mainnet, paymentKeyHash, scriptHash,
+		},
+		{
+			"addr1x8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gt7r0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shskhj42g",
+			address.TypeBaseScriptScript, address.Mainnet, scriptHash, scriptHash,
+		},
+		{
+			"addr1gx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer5pnz75xxcrzqf96k",
+			address.TypePointerKey, address.Mainnet, paymentKeyHash, "",
+		},
+		{
+			"addr128phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtupnz75xxcrtw79hu",
+			address.TypePointerScript, address.Mainnet, scriptHash, "",
+		},
+		{
+			"addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8",
+			address.TypeEnterpriseKey, address.Mainnet, paymentKeyHash, "",
+		},
+		{
+			"addr1w8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcyjy7wx",
+			address.TypeEnterpriseScript, address.Mainnet, scriptHash, "",
+		},
+		{
+			"stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw",
+			address.TypeRewardKey, address.Mainnet, "", stakeKeyHash,
+		},
+		{
+			"stake178phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcccycj5",
+			address.TypeRewardScript, address.Mainnet, "", scriptHash,
+		},
+		{
+			"addr_test1qz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgs68faae",
+			address.TypeBaseKeyKey, address.Testnet, paymentKeyHash, stakeKeyHash,
+		},
+		{
+			"stake_test1uqehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gssrtvn",
+			address.TypeRewardKey, address.Testnet, "", stakeKeyHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			got, err := address.Parse(tt.addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Type != tt.typ || got.Network != tt.network {
+				t.Fatalf("expected type %d network %d got type %d network %d", tt.typ, tt.network, got.Type, got.Network)
+			}
+			if !got.Payment.IsZero() && got.Payment.String() != tt.payment || got.Payment.IsZero() && tt.payment != "" {
+				t.Fatalf("expected payment %s got %s", tt.payment, got.Payment)
+			}
+			if !got.Stake.IsZero() && got.Stake.String() != tt.stake || got.Stake.IsZero() && tt.stake != "" {
+				t.Fatalf("expected stake %s got %s", tt.stake, got.Stake)
+			}
+			if got.String() != tt.addr {
+				t.Fatalf("expected %s got %s", tt.addr, got.String())
+			}
+			fromHex, err := address.ParseHex(got.Hex())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !fromHex.Equal(got) {
+				t.Fatalf("expected %s got %s", got.Hex(), fromHex.Hex())
+			}
+		})
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	got, err := address.Parse("addr1gx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer5pnz75xxcrzqf96k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := address.Pointer{Slot: 2498243, TxIndex: 27, CertIndex: 3}
+	if got.Pointer != want {
+		t.Fatalf("expected %v got %v", want, got.Pointer)
+	}
+}
+
+func TestParseUppercase(t *testing.T) {
+	const addr = "addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x"
+	got, err := address.Parse(strings.ToUpper(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != address.TypeBaseKeyKey || got.String() != addr {
+		t.Fatalf("expected %s got type %d %s", addr, got.Type, got.String())
+	}
+	if _, err := address.Parse(strings.ToUpper(addr[:10]) + addr[10:]); err == nil {
+		t.Fatal("expected error for mixed case address")
+	}
+}
+
+func TestParseByron(t *testing.T) {
+	tests := []struct {
+		addr    string
+		network address.Network
+		magic   uint32
+	}{
+		{"Ae2tdPwUPEZ4YjgvykNpoFeYUxoyhNj2kg8KfKWN2FizsSpLUPv68MpTVDo", address.Mainnet, 0},
+		{"DdzFFzCqrhsf6hiTYkK5gBAhVDwg3SiaHiEL9wZLYU3WqLUpx6DP5ZRJr4rtNRXbVNfk89FCHCDR365647os9AEJ8MKZNvG7UKTpythG", address.Mainnet, 0},
+		{"37btjrVyb4KDXBNC4haBVPCrro8AQPHwvCMp3RFhhSVWwfFmZ6wwzSK6JK1hY6wHNmtrpTf1kdbva8TCneM2YsiXT7mrzT21EacHnPpz5YyUdj64na", address.Testnet, 1097911063},
+	}
+	for _, tt := range tests {
+		got, err := address.Parse(tt.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Type != address.TypeByron || got.Network != tt.network {
+			t.Fatalf("expected byron address on network %d got type %d network %d", tt.network, got.Type, got.Network)
+		}
+		if tt.magic != 0 && (got.Byron.ProtocolMagic == nil || *got.Byron.ProtocolMagic != tt.magic) {
+			t.Fatalf("expected protocol magic %d got %v", tt.magic, got.Byron.ProtocolMagic)
+		}
+		if got.String() != tt.addr {
+			t.Fatalf("expected %s got %s", tt.addr, got.String())
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		addr string
+		want error
+	}{
+		// last character changed
+		{"addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl9", address.ErrInvalidChecksum},
+		// mainnet header with testnet prefix
+		{"addr_test1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspqnws9", address.ErrNetworkMismatch},
+		// reward address with payment prefix
+		{"addr1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5g3zl0gu", address.ErrInvalidPrefix},
+		// Byron address with last character changed
+		{"Ae2tdPwUPEZ4YjgvykNpoFeYUxoyhNj2kg8KfKWN2FizsSpLUPv68MpTVDp", address.ErrInvalidChecksum},
+		{"not an address", address.ErrInvalidEncoding},
+	}
+	for _, tt := range tests {
+		if err := address.Validate(tt.addr); err != tt.want {
+			t.Fatalf("%s: expected %v got %v", tt.addr, tt.want, err)
+		}
+	}
+}
+
+func TestHexConversion(t *testing.T) {
+	bech := "stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw"
+	h, err := address.Bech32ToHex(bech)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "e1" + stakeKeyHash; h != want {
+		t.Fatalf("expected %s got %s", want, h)
+	}
+	got, err := address.HexToBech32(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != bech {
+		t.Fatalf("expected %s got %s", bech, got)
+	}
+}
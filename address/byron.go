@@ -0,0 +1,129 @@
+package address
+
+import (
+	"errors"
+	"hash/crc32"
+
+	"github.com/blockfrost/blockfrost-go/internal/base58"
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+)
+
+// ByronAttributes holds the content of a Byron era address.
+type ByronAttributes struct {
+	// Hash of the address root
+	Root [HashSize]byte
+
+	// Encrypted HD derivation path, if any
+	DerivationPath []byte
+
+	// Protocol magic of the network, absent on mainnet
+	ProtocolMagic *uint32
+
+	// Address type, 0 for public key and 2 for redemption addresses
+	AddrType uint64
+}
+
+func parseByron(s string) (Address, error) {
+	raw, err := base58.Decode(s)
+	if err != nil {
+		return Address{}, ErrInvalidEncoding
+	}
+	return fromByronBytes(raw)
+}
+
+// fromByronBytes parses the CBOR form of a Byron address:
+// [#6.24(bytes .cbor [root, attributes, type]), crc32]
+func fromByronBytes(raw []byte) (Address, error) {
+	d := cbor.NewDecoder(raw)
+	var payload []byte
+	var checksum uint64
+	err := d.ReadArray(func(i int) error {
+		var err error
+		switch i {
+		case 0:
+			if tag, err := d.ReadTag(); err != nil {
+				return err
+			} else if tag != cbor.TagEncodedCBOR {
+				return errors.New("unexpected tag")
+			}
+			payload, err = d.ReadBytes()
+		case 1:
+			checksum, err = d.ReadUint()
+		default:
+			err = errors.New("unexpected item")
+		}
+		return err
+	})
+	if err != nil || d.Finish() != nil || payload == nil {
+		return Address{}, ErrInvalidEncoding
+	}
+	if uint64(crc32.ChecksumIEEE(payload)) != checksum {
+		return Address{}, ErrInvalidChecksum
+	}
+
+	attrs := &ByronAttributes{}
+	d = cbor.NewDecoder(payload)
+	err = d.ReadArray(func(i int) error {
+		switch i {
+		case 0:
+			root, err := d.ReadBytes()
+			if err != nil {
+				return err
+			}
+			if len(root) != HashSize {
+				return ErrInvalidLength
+			}
+			copy(attrs.Root[:], root)
+			return nil
+		case 1:
+			return d.ReadMap(func(int) error {
+				key, err := d.ReadUint()
+				if err != nil {
+					return err
+				}
+				value, err := d.ReadBytes()
+				if err != nil {
+					return err
+				}
+				switch key {
+				case 1:
+					attrs.DerivationPath = value
+				case 2:
+					magic, err := cbor.NewDecoder(value).ReadUint()
+					if err != nil {
+						return err
+					}
+					m := uint32(magic)
+					attrs.ProtocolMagic = &m
+				}
+				return nil
+			})
+		case 2:
+			t, err := d.ReadUint()
+			attrs.AddrType = t
+			return err
+		}
+		return errors.New("unexpected item")
+	})
+	if err != nil || d.Finish() != nil {
+		if errors.Is(err, ErrInvalidLength) {
+			return Address{}, err
+		}
+		return Address{}, ErrInvalidEncoding
+	}
+
+	network := Mainnet
+	if attrs.ProtocolMagic != nil && *attrs.ProtocolMagic != mainnetProtocolMagic {
+		network = Testnet
+	}
+	return Address{
+		Type:    TypeByron,
+		Network: network,
+		Byron:   attrs,
+		raw:     append([]byte(nil), raw...),
+	}, nil
+}
+
+func byronString(raw []byte) string {
+	return base58.Encode(raw)
+}
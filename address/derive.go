@@ -0,0 +1,134 @@
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrNoStakeCredential is returned when a stake address is requested for an
+// address that does not embed a stake credential.
+var ErrNoStakeCredential = errors.New("address: no stake credential")
+
+// NewKeyCredential returns a credential for a key hash.
+func NewKeyCredential(hash []byte) (Credential, error) {
+	return newCredential(CredentialKey, hash)
+}
+
+// NewScriptCredential returns a credential for a script hash.
+func NewScriptCredential(hash []byte) (Credential, error) {
+	return newCredential(CredentialScript, hash)
+}
+
+// CredentialFromHex returns a credential of the given type for a hex encoded
+// key or script hash, such as Script.ScriptHash.
+func CredentialFromHex(t CredentialType, hash string) (Credential, error) {
+	b, err := hex.DecodeString(hash)
+	if err != nil {
+		return Credential{}, fmt.Errorf("address: %w", err)
+	}
+	return newCredential(t, b)
+}
+
+func newCredential(t CredentialType, hash []byte) (Credential, error) {
+	if len(hash) != HashSize {
+		return Credential{}, fmt.Errorf("address: credential hash must be %d bytes, got %d", HashSize, len(hash))
+	}
+	return credential(t == CredentialScript, hash), nil
+}
+
+// NewBaseAddress builds a base address from a payment and a stake credential.
+func NewBaseAddress(network Network, payment, stake Credential) Address {
+	t := TypeBaseKeyKey | Type(payment.Type) | Type(stake.Type)<<1
+	raw := make([]byte, 0, 1+2*HashSize)
+	raw = append(raw, header(t, network))
+	raw = append(raw, payment.Hash[:]...)
+	raw = append(raw, stake.Hash[:]...)
+	return Address{Type: t, Network: network, Payment: payment, Stake: stake, raw: raw}
+}
+
+// NewPointerAddress builds a pointer address from a payment credential and a
+// pointer to a stake registration certificate.
+func NewPointerAddress(network Network, payment Credential, pointer Pointer) Address {
+	t := TypePointerKey | Type(payment.Type)
+	raw := make([]byte, 0, 1+HashSize+12)
+	raw = append(raw, header(t, network))
+	raw = append(raw, payment.Hash[:]...)
+	raw = appendVarUint(raw, pointer.Slot)
+	raw = appendVarUint(raw, pointer.TxIndex)
+	raw = appendVarUint(raw, pointer.CertIndex)
+	return Address{Type: t, Network: network, Payment: payment, Pointer: pointer, raw: raw}
+}
+
+// NewEnterpriseAddress builds an enterprise address, an address without
+// stake rights, from a payment credential.
+func NewEnterpriseAddress(network Network, payment Credential) Address {
+	t := TypeEnterpriseKey | Type(payment.Type)
+	raw := make([]byte, 0, 1+HashSize)
+	raw = append(raw, header(t, network))
+	raw = append(raw, payment.Hash[:]...)
+	return Address{Type: t, Network: network, Payment: payment, raw: raw}
+}
+
+// NewRewardAddress builds a reward (stake) address from a stake credential.
+func NewRewardAddress(network Network, stake Credential) Address {
+	t := TypeRewardKey | Type(stake.Type)
+	raw := make([]byte, 0, 1+HashSize)
+	raw = append(raw, header(t, network))
+	raw = append(raw, stake.Hash[:]...)
+	return Address{Type: t, Network: network, Stake: stake, raw: raw}
+}
+
+// NewScriptAddress builds the enterprise address of a script from its hex
+// encoded hash, as returned in Script.ScriptHash.
+func NewScriptAddress(network Network, scriptHash string) (Address, error) {
+	c, err := CredentialFromHex(CredentialScript, scriptHash)
+	if err != nil {
+		return Address{}, err
+	}
+	return NewEnterpriseAddress(network, c), nil
+}
+
+// StakeAddress returns the reward address controlling the stake of a base
+// address. Reward addresses are returned as is. Pointer addresses only
+// reference a certificate, so their stake address cannot be derived offline.
+func (a Address) StakeAddress() (Address, error) {
+	switch a.Type.Kind() {
+	case KindBase:
+		return NewRewardAddress(a.Network, a.Stake), nil
+	case KindReward:
+		return a, nil
+	}
+	return Address{}, ErrNoStakeCredential
+}
+
+// StakeAddressOf returns the Bech32 stake address of a Bech32 base address,
+// matching Address.StakeAddress returned by the API.
+func StakeAddressOf(addr string) (string, error) {
+	a, err := Parse(addr)
+	if err != nil {
+		return "", err
+	}
+	stake, err := a.StakeAddress()
+	if err != nil {
+		return "", err
+	}
+	return stake.String(), nil
+}
+
+func header(t Type, network Network) byte {
+	return byte(t)<<4 | byte(network)&0x0f
+}
+
+// appendVarUint appends v as a big endian base 128 number with the high bit
+// set on every byte but the last.
+func appendVarUint(b []byte, v uint64) []byte {
+	var buf [10]byte
+	i := len(buf) - 1
+	buf[i] = byte(v & 0x7f)
+	for v >>= 7; v > 0; v >>= 7 {
+		i--
+		buf[i] = byte(v&0x7f) | 0x80
+	}
+	return append(b, buf[i:]...)
+}
@@ -0,0 +1,122 @@
+package address_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/address"
+)
+
+const testdata = "../testdata"
+
+func TestStakeAddressMatchesAPI(t *testing.T) {
+	files := []string{
+		filepath.Join(testdata, "json", "address", "address.json"),
+		filepath.Join(testdata, "resourceaddress.golden"),
+	}
+	for _, fp := range files {
+		data, err := os.ReadFile(fp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var a blockfrost.Address
+		if err := json.Unmarshal(data, &a); err != nil {
+			t.Fatal(err)
+		}
+		got, err := address.StakeAddressOf(a.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.StakeAddress == nil || got != *a.StakeAddress {
+			t.Fatalf("expected %v got %s", a.StakeAddress, got)
+		}
+	}
+}
+
+func TestStakeAddressOfAssociatedAddresses(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(testdata, "json", "account", "account_associated_addresses.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addrs []blockfrost.AccountAssociatedAddress
+	if err := json.Unmarshal(data, &addrs); err != nil {
+		t.Fatal(err)
+	}
+	var want string
+	for _, a := range addrs {
+		got, err := address.StakeAddressOf(a.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want == "" {
+			want = got
+		}
+		if got != want {
+			t.Fatalf("expected %s got %s", want, got)
+		}
+	}
+}
+
+func TestStakeAddressWithoutStake(t *testing.T) {
+	for _, s := range []string{
+		"addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8",
+		"addr1gx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer5pnz75xxcrzqf96k",
+	} {
+		if _, err := address.StakeAddressOf(s); err != address.ErrNoStakeCredential {
+			t.Fatalf("expected %v got %v", address.ErrNoStakeCredential, err)
+		}
+	}
+}
+
+// Test vectors from CIP-19
+func TestBuildAddresses(t *testing.T) {
+	keyHash, _ := hex.DecodeString(paymentKeyHash)
+	stakeHash, _ := hex.DecodeString(stakeKeyHash)
+	payment, err := address.NewKeyCredential(keyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stake, err := address.NewKeyCredential(stakeHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := address.CredentialFromHex(address.CredentialScript, scriptHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scriptAddr, err := address.NewScriptAddress(address.Mainnet, scriptHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		got  address.Address
+		want string
+	}{
+		{address.NewBaseAddress(address.Mainnet, payment, stake), "addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x"},
+		{address.NewBaseAddress(address.Mainnet, script, stake), "addr1z8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gten0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgs9yc0hh"},
+		{address.NewBaseAddress(address.Mainnet, payment, script), "addr1yx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerkr0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shs2z78ve"},
+		{address.NewBaseAddress(address.Testnet, payment, stake), "addr_test1qz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgs68faae"},
+		{address.NewPointerAddress(address.Mainnet, payment, address.Pointer{Slot: 2498243, TxIndex: 27, CertIndex: 3}), "addr1gx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer5pnz75xxcrzqf96k"},
+		{address.NewEnterpriseAddress(address.Mainnet, payment), "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8"},
+		{scriptAddr, "addr1w8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcyjy7wx"},
+		{address.NewRewardAddress(address.Mainnet, stake), "stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw"},
+		{address.NewRewardAddress(address.Mainnet, script), "stake178phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcccycj5"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Fatalf("expected %s got %s", tt.want, got)
+		}
+		parsed, err := address.Parse(tt.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !parsed.Equal(tt.got) {
+			t.Fatalf("expected %s got %s", parsed.Hex(), tt.got.Hex())
+		}
+	}
+}
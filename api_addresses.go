@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+
+	"github.com/blockfrost/blockfrost-go/plutusdata"
 )
 
 const (
@@ -104,6 +106,15 @@ type AddressUTXO struct {
 	ReferenceScriptHash *string `json:"reference_script_hash"`
 }
 
+// DecodeInlineDatum decodes the inline datum of the UTXO. It returns nil if
+// the UTXO has no inline datum.
+func (u AddressUTXO) DecodeInlineDatum() (plutusdata.PlutusData, error) {
+	if u.InlineDatum == nil {
+		return nil, nil
+	}
+	return plutusdata.DecodeHex(*u.InlineDatum)
+}
+
 type AddressTxResult struct {
 	Res []AddressTransactions
 	Err error
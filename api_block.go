@@ -2,11 +2,14 @@ package blockfrost
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"sync"
+
+	"github.com/blockfrost/blockfrost-go/ledger"
 )
 
 const (
@@ -84,6 +87,56 @@ type BlockTransactionResult struct {
 	Err error
 }
 
+// BlockTransactionCBOR contains a transaction within a block together with
+// its raw CBOR serialization.
+type BlockTransactionCBOR struct {
+	// Hash of the transaction
+	TxHash string
+
+	// CBOR serialized transaction, hex-decoded
+	Cbor []byte
+}
+
+type blockTransactionCBORJSON struct {
+	TxHash string `json:"tx_hash"`
+	Cbor   string `json:"cbor"`
+}
+
+// MarshalJSON marshals BlockTransactionCBOR in the API format, with the CBOR
+// encoded as a hex string.
+func (b BlockTransactionCBOR) MarshalJSON() ([]byte, error) {
+	return json.Marshal(blockTransactionCBORJSON{
+		TxHash: b.TxHash,
+		Cbor:   hex.EncodeToString(b.Cbor),
+	})
+}
+
+// UnmarshalJSON unmarshals BlockTransactionCBOR from the API format,
+// decoding the hex encoded CBOR into raw bytes.
+func (b *BlockTransactionCBOR) UnmarshalJSON(data []byte) error {
+	var raw blockTransactionCBORJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	cbor, err := hex.DecodeString(raw.Cbor)
+	if err != nil {
+		return fmt.Errorf("invalid cbor for transaction %s: %w", raw.TxHash, err)
+	}
+	b.TxHash = raw.TxHash
+	b.Cbor = cbor
+	return nil
+}
+
+// Decode decodes the CBOR serialized transaction.
+func (b BlockTransactionCBOR) Decode() (*ledger.Transaction, error) {
+	return ledger.DecodeTransaction(b.Cbor)
+}
+
+type BlockTransactionCBORResult struct {
+	Res []BlockTransactionCBOR
+	Err error
+}
+
 // BlocksLatest Return the latest block available to the backends, also known as the
 // tip of the blockchain.
 func (c *apiClient) BlockLatest(ctx context.Context) (b Block, err error) {
@@ -446,3 +499,149 @@ func (c *apiClient) BlockLatestTransactionsAll(ctx context.Context) <-chan Block
 	}()
 	return ch
 }
+
+// BlockTransactionsCBOR returns the transactions within the block specified
+// by a hash or block number, together with their CBOR serialization.
+func (c *apiClient) BlockTransactionsCBOR(ctx context.Context, hashOrNumber string, query APIQueryParams) (txs []BlockTransactionCBOR, err error) {
+	requestUrl, err := url.Parse(fmt.Sprintf("%s/%s/%s/%s/%s", c.server, resourceBlock, hashOrNumber, "txs", resourceCbor))
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl.String(), nil)
+	if err != nil {
+		return
+	}
+
+	v := req.URL.Query()
+	v = formatParams(v, query)
+	req.URL.RawQuery = v.Encode()
+
+	res, err := c.handleRequest(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if err = json.NewDecoder(res.Body).Decode(&txs); err != nil {
+		return
+	}
+	return txs, nil
+}
+
+// BlockLatestTransactionsCBOR returns the transactions within the latest
+// block, together with their CBOR serialization.
+func (c *apiClient) BlockLatestTransactionsCBOR(ctx context.Context, query APIQueryParams) (txs []BlockTransactionCBOR, err error) {
+	requestUrl, err := url.Parse(fmt.Sprintf("%s/%s", c.server, resourceBlocksLatestTransactionsCBOR))
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl.String(), nil)
+	if err != nil {
+		return
+	}
+
+	v := req.URL.Query()
+	v = formatParams(v, query)
+	req.URL.RawQuery = v.Encode()
+
+	res, err := c.handleRequest(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if err = json.NewDecoder(res.Body).Decode(&txs); err != nil {
+		return
+	}
+	return txs, nil
+}
+
+// BlockTransactionsCBORAll returns all transactions within the block
+// specified by a hash or block number, together with their CBOR serialization.
+func (c *apiClient) BlockTransactionsCBORAll(ctx context.Context, hashOrNumber string) <-chan BlockTransactionCBORResult {
+	ch := make(chan BlockTransactionCBORResult, c.routines)
+	jobs := make(chan methodOptions, c.routines)
+	quit := make(chan bool, 1)
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < c.routines; i++ {
+		wg.Add(1)
+		go func(jobs chan methodOptions, ch chan BlockTransactionCBORResult, wg *sync.WaitGroup) {
+			defer wg.Done()
+			for j := range jobs {
+				txs, err := c.BlockTransactionsCBOR(j.ctx, hashOrNumber, j.query)
+				if len(txs) != j.query.Count || err != nil {
+					select {
+					case quit <- true:
+					default:
+					}
+				}
+				res := BlockTransactionCBORResult{Res: txs, Err: err}
+				ch <- res
+			}
+
+		}(jobs, ch, &wg)
+	}
+	go func() {
+		defer close(ch)
+		fetchNextPage := true
+		for i := 1; fetchNextPage; i++ {
+			select {
+			case <-quit:
+				fetchNextPage = false
+			default:
+				jobs <- methodOptions{ctx: ctx, query: APIQueryParams{Count: 100, Page: i}}
+			}
+		}
+
+		close(jobs)
+		wg.Wait()
+	}()
+	return ch
+}
+
+// BlockLatestTransactionsCBORAll returns all transactions within the latest
+// block, together with their CBOR serialization.
+func (c *apiClient) BlockLatestTransactionsCBORAll(ctx context.Context) <-chan BlockTransactionCBORResult {
+	ch := make(chan BlockTransactionCBORResult, c.routines)
+	jobs := make(chan methodOptions, c.routines)
+	quit := make(chan bool, 1)
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < c.routines; i++ {
+		wg.Add(1)
+		go func(jobs chan methodOptions, ch chan BlockTransactionCBORResult, wg *sync.WaitGroup) {
+			defer wg.Done()
+			for j := range jobs {
+				txs, err := c.BlockLatestTransactionsCBOR(j.ctx, j.query)
+				if len(txs) != j.query.Count || err != nil {
+					select {
+					case quit <- true:
+					default:
+					}
+				}
+				res := BlockTransactionCBORResult{Res: txs, Err: err}
+				ch <- res
+			}
+
+		}(jobs, ch, &wg)
+	}
+	go func() {
+		defer close(ch)
+		fetchNextPage := true
+		for i := 1; fetchNextPage; i++ {
+			select {
+			case <-quit:
+				fetchNextPage = false
+			default:
+				jobs <- methodOptions{ctx: ctx, query: APIQueryParams{Count: 100, Page: i}}
+			}
+		}
+
+		close(jobs)
+		wg.Wait()
+	}()
+	return ch
+}
@@ -164,3 +164,53 @@ func TestBlocksAddressesIntegration(t *testing.T) {
 
 	testIntUtil(t, fp, &got, &want)
 }
+
+func TestBlockTransactionCBORUnmarshal(t *testing.T) {
+	want := []blockfrost.BlockTransactionCBOR{
+		{
+			TxHash: "d569fa92b83dd95878b615b5116b39a25fa7a1ad6fe2fdffe469da67238ee4a4",
+			Cbor: []byte{
+				0x84, 0xa3, 0x00, 0x81, 0x82, 0x58, 0x20, 0x0c, 0x1c, 0x1b, 0x1b, 0x0e, 0x1c, 0x6f, 0x6d, 0x2d,
+				0x5b, 0x3e, 0xd8, 0xe6, 0xa1, 0xf2, 0xe0, 0xc2, 0xd7, 0xf1, 0xc0, 0xa1, 0xa2, 0xa3, 0xa4, 0xa5,
+				0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xab, 0xac, 0x00, 0x01, 0x81, 0x82, 0x58, 0x1d, 0x61, 0xbb, 0x40,
+				0xf1, 0xa6, 0x47, 0xbc, 0x88, 0xc1, 0xbd, 0x6b, 0x73, 0x8d, 0xb8, 0xeb, 0x66, 0x35, 0x7d, 0x92,
+				0x64, 0x74, 0xea, 0x5f, 0xfd, 0x6b, 0xaa, 0x76, 0xc9, 0xfb, 0x1a, 0x00, 0x1e, 0x84, 0x80, 0x02,
+				0x1a, 0x00, 0x02, 0x91, 0x51, 0xa0, 0xf5, 0xf6,
+			},
+		},
+	}
+	fp := filepath.Join(testdata, "json", "block", "block_txs_cbor.json")
+	got := []blockfrost.BlockTransactionCBOR{}
+	testStructGotWant(t, fp, &got, &want)
+}
+
+func TestBlockTransactionsCBORAllIntegration(t *testing.T) {
+	block := "7474d633ece405cec979714186a72b5a1221cebf356831dbe568aaa1f6c3b077"
+	api := blockfrost.NewAPIClient(
+		blockfrost.APIClientOptions{},
+	)
+
+	var allTxs []blockfrost.BlockTransactionCBOR
+	for result := range api.BlockTransactionsCBORAll(context.TODO(), block) {
+		if result.Err != nil {
+			t.Fatal(result.Err)
+		}
+		allTxs = append(allTxs, result.Res...)
+	}
+	if len(allTxs) == 0 {
+		t.Fatal("got empty block transactions")
+	}
+	for _, tx := range allTxs {
+		if len(tx.Cbor) == 0 {
+			t.Fatalf("got empty cbor for transaction %s", tx.TxHash)
+		}
+	}
+}
+
+func TestBlockLatestTransactionsCBORIntegration(t *testing.T) {
+	api := blockfrost.NewAPIClient(
+		blockfrost.APIClientOptions{},
+	)
+	_, err := api.BlockLatestTransactionsCBOR(context.TODO(), blockfrost.APIQueryParams{})
+	testErrorHelper(t, err)
+}
@@ -187,6 +187,318 @@ type EpochParameters struct {
 	DrepDeposit                *string  `json:"drep_deposit"`
 	DrepActivity               *string  `json:"drep_activity"`
 	MinFeeRefScriptCostPerByte *float64 `json:"min_fee_ref_script_cost_per_byte"`
+
+	// Rational holds the exact values of the fractional fields, decoded
+	// from the same JSON as the float fields. It is not set for values
+	// built in code.
+	Rational EpochParametersRational `json:"-"`
+}
+
+// UnmarshalJSON decodes p, along with the exact values of its fractional
+// fields.
+func (p *EpochParameters) UnmarshalJSON(data []byte) error {
+	type epochParameters EpochParameters
+	if err := json.Unmarshal(data, (*epochParameters)(p)); err != nil {
+		return err
+	}
+	r, err := DecodeEpochParametersRational(data)
+	if err != nil {
+		return err
+	}
+	p.Rational = r
+	return nil
+}
+
+// A0Rational returns Rational.A0, the exact value of A0, or the shortest
+// decimal rounding to A0 if Rational.A0 is not set.
+func (p EpochParameters) A0Rational() Rational {
+	if p.Rational.A0 != nil {
+		return *p.Rational.A0
+	}
+	return rationalFromFloat(float64(p.A0), 32)
+}
+
+// DecentralisationParamRational returns Rational.DecentralisationParam, the
+// exact value of DecentralisationParam, or the shortest decimal rounding to
+// DecentralisationParam if Rational.DecentralisationParam is not set.
+func (p EpochParameters) DecentralisationParamRational() Rational {
+	if p.Rational.DecentralisationParam != nil {
+		return *p.Rational.DecentralisationParam
+	}
+	return rationalFromFloat(float64(p.DecentralisationParam), 32)
+}
+
+// RhoRational returns Rational.Rho, the exact value of Rho, or the shortest
+// decimal rounding to Rho if Rational.Rho is not set.
+func (p EpochParameters) RhoRational() Rational {
+	if p.Rational.Rho != nil {
+		return *p.Rational.Rho
+	}
+	return rationalFromFloat(float64(p.Rho), 32)
+}
+
+// TauRational returns Rational.Tau, the exact value of Tau, or the shortest
+// decimal rounding to Tau if Rational.Tau is not set.
+func (p EpochParameters) TauRational() Rational {
+	if p.Rational.Tau != nil {
+		return *p.Rational.Tau
+	}
+	return rationalFromFloat(float64(p.Tau), 32)
+}
+
+// PriceMemRational returns Rational.PriceMem, the exact value of PriceMem,
+// or the shortest decimal rounding to PriceMem if Rational.PriceMem is not
+// set. It returns nil if PriceMem is not set.
+func (p EpochParameters) PriceMemRational() *Rational {
+	if p.Rational.PriceMem != nil {
+		r := *p.Rational.PriceMem
+		return &r
+	}
+	return rationalFromFloat32(p.PriceMem)
+}
+
+// PriceStepRational returns Rational.PriceStep, the exact value of
+// PriceStep, or the shortest decimal rounding to PriceStep if
+// Rational.PriceStep is not set. It returns nil if PriceStep is not set.
+func (p EpochParameters) PriceStepRational() *Rational {
+	if p.Rational.PriceStep != nil {
+		r := *p.Rational.PriceStep
+		return &r
+	}
+	return rationalFromFloat32(p.PriceStep)
+}
+
+// MinFeeRefScriptCostPerByteRational returns
+// Rational.MinFeeRefScriptCostPerByte, the exact value of
+// MinFeeRefScriptCostPerByte, or the shortest decimal rounding to
+// MinFeeRefScriptCostPerByte if Rational.MinFeeRefScriptCostPerByte is not
+// set. It returns nil if MinFeeRefScriptCostPerByte is not set.
+func (p EpochParameters) MinFeeRefScriptCostPerByteRational() *Rational {
+	if p.Rational.MinFeeRefScriptCostPerByte != nil {
+		r := *p.Rational.MinFeeRefScriptCostPerByte
+		return &r
+	}
+	return rationalFromFloat64(p.MinFeeRefScriptCostPerByte)
+}
+
+// PvtMotionNoConfidenceRational returns Rational.PvtMotionNoConfidence, the
+// exact value of PvtMotionNoConfidence, or the shortest decimal rounding to
+// PvtMotionNoConfidence if Rational.PvtMotionNoConfidence is not set. It
+// returns nil if PvtMotionNoConfidence is not set.
+func (p EpochParameters) PvtMotionNoConfidenceRational() *Rational {
+	if p.Rational.PvtMotionNoConfidence != nil {
+		r := *p.Rational.PvtMotionNoConfidence
+		return &r
+	}
+	return rationalFromFloat64(p.PvtMotionNoConfidence)
+}
+
+// PvtCommitteeNormalRational returns Rational.PvtCommitteeNormal, the exact
+// value of PvtCommitteeNormal, or the shortest decimal rounding to
+// PvtCommitteeNormal if Rational.PvtCommitteeNormal is not set. It returns
+// nil if PvtCommitteeNormal is not set.
+func (p EpochParameters) PvtCommitteeNormalRational() *Rational {
+	if p.Rational.PvtCommitteeNormal != nil {
+		r := *p.Rational.PvtCommitteeNormal
+		return &r
+	}
+	return rationalFromFloat64(p.PvtCommitteeNormal)
+}
+
+// PvtCommitteeNoConfidenceRational returns
+// Rational.PvtCommitteeNoConfidence, the exact value of
+// PvtCommitteeNoConfidence, or the shortest decimal rounding to
+// PvtCommitteeNoConfidence if Rational.PvtCommitteeNoConfidence is not set.
+// It returns nil if PvtCommitteeNoConfidence is not set.
+func (p EpochParameters) PvtCommitteeNoConfidenceRational() *Rational {
+	if p.Rational.PvtCommitteeNoConfidence != nil {
+		r := *p.Rational.PvtCommitteeNoConfidence
+		return &r
+	}
+	return rationalFromFloat64(p.PvtCommitteeNoConfidence)
+}
+
+// PvtHardForkInitiationRational returns Rational.PvtHardForkInitiation, the
+// exact value of PvtHardForkInitiation, or the shortest decimal rounding to
+// PvtHardForkInitiation if Rational.PvtHardForkInitiation is not set. It
+// returns nil if PvtHardForkInitiation is not set.
+func (p EpochParameters) PvtHardForkInitiationRational() *Rational {
+	if p.Rational.PvtHardForkInitiation != nil {
+		r := *p.Rational.PvtHardForkInitiation
+		return &r
+	}
+	return rationalFromFloat64(p.PvtHardForkInitiation)
+}
+
+// PvtPPSecurityGroupRational returns Rational.PvtPPSecurityGroup, the exact
+// value of PvtPPSecurityGroup, or the shortest decimal rounding to
+// PvtPPSecurityGroup if Rational.PvtPPSecurityGroup is not set. It returns
+// nil if PvtPPSecurityGroup is not set.
+func (p EpochParameters) PvtPPSecurityGroupRational() *Rational {
+	if p.Rational.PvtPPSecurityGroup != nil {
+		r := *p.Rational.PvtPPSecurityGroup
+		return &r
+	}
+	return rationalFromFloat64(p.PvtPPSecurityGroup)
+}
+
+// DvtMotionNoConfidenceRational returns Rational.DvtMotionNoConfidence, the
+// exact value of DvtMotionNoConfidence, or the shortest decimal rounding to
+// DvtMotionNoConfidence if Rational.DvtMotionNoConfidence is not set. It
+// returns nil if DvtMotionNoConfidence is not set.
+func (p EpochParameters) DvtMotionNoConfidenceRational() *Rational {
+	if p.Rational.DvtMotionNoConfidence != nil {
+		r := *p.Rational.DvtMotionNoConfidence
+		return &r
+	}
+	return rationalFromFloat64(p.DvtMotionNoConfidence)
+}
+
+// DvtCommitteeNormalRational returns Rational.DvtCommitteeNormal, the exact
+// value of DvtCommitteeNormal, or the shortest decimal rounding to
+// DvtCommitteeNormal if Rational.DvtCommitteeNormal is not set. It returns
+// nil if DvtCommitteeNormal is not set.
+func (p EpochParameters) DvtCommitteeNormalRational() *Rational {
+	if p.Rational.DvtCommitteeNormal != nil {
+		r := *p.Rational.DvtCommitteeNormal
+		return &r
+	}
+	return rationalFromFloat64(p.DvtCommitteeNormal)
+}
+
+// DvtCommitteeNoConfidenceRational returns
+// Rational.DvtCommitteeNoConfidence, the exact value of
+// DvtCommitteeNoConfidence, or the shortest decimal rounding to
+// DvtCommitteeNoConfidence if Rational.DvtCommitteeNoConfidence is not set.
+// It returns nil if DvtCommitteeNoConfidence is not set.
+func (p EpochParameters) DvtCommitteeNoConfidenceRational() *Rational {
+	if p.Rational.DvtCommitteeNoConfidence != nil {
+		r := *p.Rational.DvtCommitteeNoConfidence
+		return &r
+	}
+	return rationalFromFloat64(p.DvtCommitteeNoConfidence)
+}
+
+// DvtUpdateToConstitutionRational returns Rational.DvtUpdateToConstitution,
+// the exact value of DvtUpdateToConstitution, or the shortest decimal
+// rounding to DvtUpdateToConstitution if Rational.DvtUpdateToConstitution is
+// not set. It returns nil if DvtUpdateToConstitution is not set.
+func (p EpochParameters) DvtUpdateToConstitutionRational() *Rational {
+	if p.Rational.DvtUpdateToConstitution != nil {
+		r := *p.Rational.DvtUpdateToConstitution
+		return &r
+	}
+	return rationalFromFloat64(p.DvtUpdateToConstitution)
+}
+
+// DvtHardForkInitiationRational returns Rational.DvtHardForkInitiation, the
+// exact value of DvtHardForkInitiation, or the shortest decimal rounding to
+// DvtHardForkInitiation if Rational.DvtHardForkInitiation is not set. It
+// returns nil if DvtHardForkInitiation is not set.
+func (p EpochParameters) DvtHardForkInitiationRational() *Rational {
+	if p.Rational.DvtHardForkInitiation != nil {
+		r := *p.Rational.DvtHardForkInitiation
+		return &r
+	}
+	return rationalFromFloat64(p.DvtHardForkInitiation)
+}
+
+// DvtPPNetworkGroupRational returns Rational.DvtPPNetworkGroup, the exact
+// value of DvtPPNetworkGroup, or the shortest decimal rounding to
+// DvtPPNetworkGroup if Rational.DvtPPNetworkGroup is not set. It returns nil
+// if DvtPPNetworkGroup is not set.
+func (p EpochParameters) DvtPPNetworkGroupRational() *Rational {
+	if p.Rational.DvtPPNetworkGroup != nil {
+		r := *p.Rational.DvtPPNetworkGroup
+		return &r
+	}
+	return rationalFromFloat64(p.DvtPPNetworkGroup)
+}
+
+// DvtPPEconomicGroupRational returns Rational.DvtPPEconomicGroup, the exact
+// value of DvtPPEconomicGroup, or the shortest decimal rounding to
+// DvtPPEconomicGroup if Rational.DvtPPEconomicGroup is not set. It returns
+// nil if DvtPPEconomicGroup is not set.
+func (p EpochParameters) DvtPPEconomicGroupRational() *Rational {
+	if p.Rational.DvtPPEconomicGroup != nil {
+		r := *p.Rational.DvtPPEconomicGroup
+		return &r
+	}
+	return rationalFromFloat64(p.DvtPPEconomicGroup)
+}
+
+// DvtPPTechnicalGroupRational returns Rational.DvtPPTechnicalGroup, the
+// exact value of DvtPPTechnicalGroup, or the shortest decimal rounding to
+// DvtPPTechnicalGroup if Rational.DvtPPTechnicalGroup is not set. It returns
+// nil if DvtPPTechnicalGroup is not set.
+func (p EpochParameters) DvtPPTechnicalGroupRational() *Rational {
+	if p.Rational.DvtPPTechnicalGroup != nil {
+		r := *p.Rational.DvtPPTechnicalGroup
+		return &r
+	}
+	return rationalFromFloat64(p.DvtPPTechnicalGroup)
+}
+
+// DvtPPGovGroupRational returns Rational.DvtPPGovGroup, the exact value of
+// DvtPPGovGroup, or the shortest decimal rounding to DvtPPGovGroup if
+// Rational.DvtPPGovGroup is not set. It returns nil if DvtPPGovGroup is not
+// set.
+func (p EpochParameters) DvtPPGovGroupRational() *Rational {
+	if p.Rational.DvtPPGovGroup != nil {
+		r := *p.Rational.DvtPPGovGroup
+		return &r
+	}
+	return rationalFromFloat64(p.DvtPPGovGroup)
+}
+
+// DvtTreasuryWithdrawalRational returns Rational.DvtTreasuryWithdrawal, the
+// exact value of DvtTreasuryWithdrawal, or the shortest decimal rounding to
+// DvtTreasuryWithdrawal if Rational.DvtTreasuryWithdrawal is not set. It
+// returns nil if DvtTreasuryWithdrawal is not set.
+func (p EpochParameters) DvtTreasuryWithdrawalRational() *Rational {
+	if p.Rational.DvtTreasuryWithdrawal != nil {
+		r := *p.Rational.DvtTreasuryWithdrawal
+		return &r
+	}
+	return rationalFromFloat64(p.DvtTreasuryWithdrawal)
+}
+
+// EpochParametersRational holds the fractional parameters of an epoch
+// parameters response, decoded exactly. Parameters that are not set are nil.
+type EpochParametersRational struct {
+	A0                         *Rational `json:"a0"`
+	DecentralisationParam      *Rational `json:"decentralisation_param"`
+	Rho                        *Rational `json:"rho"`
+	Tau                        *Rational `json:"tau"`
+	PriceMem                   *Rational `json:"price_mem"`
+	PriceStep                  *Rational `json:"price_step"`
+	MinFeeRefScriptCostPerByte *Rational `json:"min_fee_ref_script_cost_per_byte"`
+	PvtMotionNoConfidence      *Rational `json:"pvt_motion_no_confidence"`
+	PvtCommitteeNormal         *Rational `json:"pvt_committee_normal"`
+	PvtCommitteeNoConfidence   *Rational `json:"pvt_committee_no_confidence"`
+	PvtHardForkInitiation      *Rational `json:"pvt_hard_fork_initiation"`
+	PvtPPSecurityGroup         *Rational `json:"pvt_p_p_security_group"`
+	DvtMotionNoConfidence      *Rational `json:"dvt_motion_no_confidence"`
+	DvtCommitteeNormal         *Rational `json:"dvt_committee_normal"`
+	DvtCommitteeNoConfidence   *Rational `json:"dvt_committee_no_confidence"`
+	DvtUpdateToConstitution    *Rational `json:"dvt_update_to_constitution"`
+	DvtHardForkInitiation      *Rational `json:"dvt_hard_fork_initiation"`
+	DvtPPNetworkGroup          *Rational `json:"dvt_p_p_network_group"`
+	DvtPPEconomicGroup         *Rational `json:"dvt_p_p_economic_group"`
+	DvtPPTechnicalGroup        *Rational `json:"dvt_p_p_technical_group"`
+	DvtPPGovGroup              *Rational `json:"dvt_p_p_gov_group"`
+	DvtTreasuryWithdrawal      *Rational `json:"dvt_treasury_withdrawal"`
+}
+
+// DecodeEpochParametersRational decodes the fractional parameters of raw,
+// the JSON body of an epoch parameters response, without going through
+// floating point.
+func DecodeEpochParametersRational(raw []byte) (EpochParametersRational, error) {
+	var epr EpochParametersRational
+	if err := json.Unmarshal(raw, &epr); err != nil {
+		return EpochParametersRational{}, err
+	}
+	return epr, nil
 }
 
 type EpochResult struct {
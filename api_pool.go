@@ -59,6 +59,46 @@ type Pool struct {
 	Registration   []string    `json:"registration"`
 	Retirement     []string    `json:"retirement"`
 	CalidusKey     *CalidusKey `json:"calidus_key"`
+
+	// Rational holds the exact values of the fractional fields, decoded
+	// from the same JSON as the float fields. It is not set for values
+	// built in code.
+	Rational PoolRational `json:"-"`
+}
+
+// UnmarshalJSON decodes p, along with the exact values of its fractional
+// fields.
+func (p *Pool) UnmarshalJSON(data []byte) error {
+	type pool Pool
+	if err := json.Unmarshal(data, (*pool)(p)); err != nil {
+		return err
+	}
+	r, err := DecodePoolRational(data)
+	if err != nil {
+		return err
+	}
+	p.Rational = r
+	return nil
+}
+
+// ActiveSizeRational returns Rational.ActiveSize, the exact value of
+// ActiveSize, or the shortest decimal rounding to ActiveSize if
+// Rational.ActiveSize is not set.
+func (p Pool) ActiveSizeRational() Rational {
+	if p.Rational.ActiveSize != nil {
+		return *p.Rational.ActiveSize
+	}
+	return rationalFromFloat(p.ActiveSize, 64)
+}
+
+// MarginCostRational returns Rational.MarginCost, the exact value of
+// MarginCost, or the shortest decimal rounding to MarginCost if
+// Rational.MarginCost is not set.
+func (p Pool) MarginCostRational() Rational {
+	if p.Rational.MarginCost != nil {
+		return *p.Rational.MarginCost
+	}
+	return rationalFromFloat(p.MarginCost, 64)
 }
 
 type CalidusKey struct {
@@ -81,6 +121,36 @@ type PoolHistory struct {
 	DelegatorsCount int     `json:"delegators_count"`
 	Rewards         string  `json:"rewards"`
 	Fees            string  `json:"fees"`
+
+	// Rational holds the exact values of the fractional fields, decoded
+	// from the same JSON as the float fields. It is not set for values
+	// built in code.
+	Rational PoolRational `json:"-"`
+}
+
+// UnmarshalJSON decodes ph, along with the exact values of its fractional
+// fields.
+func (ph *PoolHistory) UnmarshalJSON(data []byte) error {
+	type poolHistory PoolHistory
+	if err := json.Unmarshal(data, (*poolHistory)(ph)); err != nil {
+		return err
+	}
+	r, err := DecodePoolRational(data)
+	if err != nil {
+		return err
+	}
+	ph.Rational = r
+	return nil
+}
+
+// ActiveSizeRational returns Rational.ActiveSize, the exact value of
+// ActiveSize, or the shortest decimal rounding to ActiveSize if
+// Rational.ActiveSize is not set.
+func (ph PoolHistory) ActiveSizeRational() Rational {
+	if ph.Rational.ActiveSize != nil {
+		return *ph.Rational.ActiveSize
+	}
+	return rationalFromFloat(ph.ActiveSize, 64)
 }
 
 // PoolMetadata return Stake pool metadata
@@ -187,6 +257,55 @@ type PoolExtended struct {
 	FixedCost      string                `json:"fixed_cost"`
 	DeclaredPledge string                `json:"declared_pledge"`
 	Metadata       *PoolExtendedMetadata `json:"metadata"`
+
+	// Rational holds the exact values of the fractional fields, decoded
+	// from the same JSON as the float fields. It is not set for values
+	// built in code.
+	Rational PoolRational `json:"-"`
+}
+
+// UnmarshalJSON decodes pe, along with the exact values of its fractional
+// fields.
+func (pe *PoolExtended) UnmarshalJSON(data []byte) error {
+	type poolExtended PoolExtended
+	if err := json.Unmarshal(data, (*poolExtended)(pe)); err != nil {
+		return err
+	}
+	r, err := DecodePoolRational(data)
+	if err != nil {
+		return err
+	}
+	pe.Rational = r
+	return nil
+}
+
+// MarginCostRational returns Rational.MarginCost, the exact value of
+// MarginCost, or the shortest decimal rounding to MarginCost if
+// Rational.MarginCost is not set.
+func (pe PoolExtended) MarginCostRational() Rational {
+	if pe.Rational.MarginCost != nil {
+		return *pe.Rational.MarginCost
+	}
+	return rationalFromFloat(pe.MarginCost, 64)
+}
+
+// PoolRational holds the fractional fields of a pool, pool history or
+// extended pool response, decoded exactly. Fields that are not part of the
+// response are nil.
+type PoolRational struct {
+	ActiveSize *Rational `json:"active_size"`
+	MarginCost *Rational `json:"margin_cost"`
+}
+
+// DecodePoolRational decodes the fractional fields of raw, the JSON body of
+// a single pool, pool history or extended pool entry, without going through
+// floating point.
+func DecodePoolRational(raw []byte) (PoolRational, error) {
+	var pr PoolRational
+	if err := json.Unmarshal(raw, &pr); err != nil {
+		return PoolRational{}, err
+	}
+	return pr, nil
 }
 
 type PoolsExtendedResult struct {
@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
+
+	"github.com/blockfrost/blockfrost-go/nativescript"
+	"github.com/blockfrost/blockfrost-go/plutusdata"
 )
 
 const (
@@ -73,6 +77,11 @@ type ScriptJSON struct {
 	JSON interface{} `json:"json"`
 }
 
+// NativeScript decodes the JSON of a timelock script.
+func (sj ScriptJSON) NativeScript() (*nativescript.Script, error) {
+	return nativescript.FromJSONValue(sj.JSON)
+}
+
 type ScriptCBOR struct {
 	CBOR *string `json:"cbor"`
 }
@@ -85,6 +94,18 @@ type ScriptDatumCBOR struct {
 	CBOR string `json:"cbor"`
 }
 
+// Decode decodes the datum from its detailed schema JSON. Integers beyond
+// 2^53 lose precision in JSONValue; use ScriptDatumCBOR to decode them
+// exactly.
+func (sd ScriptDatum) Decode() (plutusdata.PlutusData, error) {
+	return plutusdata.FromJSONValue(sd.JSONValue)
+}
+
+// Decode decodes the CBOR serialized datum.
+func (sdc ScriptDatumCBOR) Decode() (plutusdata.PlutusData, error) {
+	return plutusdata.DecodeHex(sdc.CBOR)
+}
+
 // Scripts returns a paginated list of scripts.
 func (c *apiClient) Scripts(ctx context.Context, query APIQueryParams) (scripts []Script, err error) {
 	requestUrl, err := url.Parse(fmt.Sprintf("%s/%s", c.server, resourceScripts))
@@ -325,3 +346,49 @@ func (c *apiClient) ScriptDatumCBOR(ctx context.Context, datumHash string) (sdc
 	}
 	return sdc, nil
 }
+
+// ReferenceScripts fetches the scripts of hashes, to resolve the reference
+// scripts of UTXOs.
+func (c *apiClient) ReferenceScripts(ctx context.Context, hashes ...string) (ReferenceScripts, error) {
+	scripts := ReferenceScripts{}
+	for _, hash := range hashes {
+		if _, ok := scripts[hash]; ok {
+			continue
+		}
+		info, err := c.Script(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		var lang ScriptLanguage
+		switch strings.ToLower(info.Type) {
+		case "timelock":
+			sj, err := c.ScriptJSON(ctx, hash)
+			if err != nil {
+				return nil, err
+			}
+			ns, err := sj.NativeScript()
+			if err != nil {
+				return nil, err
+			}
+			scripts[hash] = NativeReferenceScript(*ns)
+			continue
+		case "plutusv1":
+			lang = ScriptLanguagePlutusV1
+		case "plutusv2":
+			lang = ScriptLanguagePlutusV2
+		case "plutusv3":
+			lang = ScriptLanguagePlutusV3
+		default:
+			return nil, fmt.Errorf("script %s: unknown type %q", hash, info.Type)
+		}
+		sc, err := c.ScriptCBOR(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if sc.CBOR == nil {
+			return nil, fmt.Errorf("script %s: no CBOR", hash)
+		}
+		scripts[hash] = PlutusReferenceScript(lang, *sc.CBOR)
+	}
+	return scripts, nil
+}
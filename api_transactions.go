@@ -3,10 +3,15 @@ package blockfrost
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+
+	"github.com/blockfrost/blockfrost-go/ledger"
 )
 
 const (
@@ -106,6 +111,42 @@ type TransactionCBOR struct {
 	Cbor string `json:"cbor"`
 }
 
+// Decode decodes the CBOR serialized transaction.
+func (t TransactionCBOR) Decode() (*ledger.Transaction, error) {
+	return ledger.DecodeTransactionHex(t.Cbor)
+}
+
+// TransactionHash returns the ID of a transaction, the hash of its body,
+// from its CBOR encoding. The encoding may also be hex encoded, as accepted
+// by TransactionSubmit.
+func TransactionHash(cbor []byte) (string, error) {
+	tx, err := ledger.DecodeTransaction(cbor)
+	if err != nil {
+		data, herr := hex.DecodeString(strings.TrimSpace(string(cbor)))
+		if herr != nil {
+			return "", err
+		}
+		if tx, err = ledger.DecodeTransaction(data); err != nil {
+			return "", err
+		}
+	}
+	return tx.Hash().String(), nil
+}
+
+// SubmitResult is the result of TransactionSubmitIdempotent.
+type SubmitResult struct {
+	// Hash of the transaction, computed locally from its body
+	Hash string
+
+	// Accepted is set when the transaction was accepted for submission.
+	Accepted bool
+
+	// AlreadyKnown is set when the transaction was rejected because it is
+	// already in the mempool or in the ledger, for instance when it is
+	// submitted again after a timeout.
+	AlreadyKnown bool
+}
+
 type TxAmount struct {
 	// The quantity of the unit
 	Quantity string `json:"quantity"`
@@ -365,10 +406,7 @@ type AdditionalUtxoSetTxOut struct {
 }
 
 // AdditionalUtxoSet represents a slice of tuples (TxIn, TxOut)
-type AdditionalUtxoSet []struct {
-	TxIn  AdditionalUtxoSetTxIn  `json:"txIn"`
-	TxOut AdditionalUtxoSetTxOut `json:"txOut"`
-}
+type AdditionalUtxoSet []AdditionalUtxo
 
 type OgmiosResponse struct {
 	Type        string `json:"type"`
@@ -379,6 +417,14 @@ type OgmiosResponse struct {
 		Id string `json:"id"`
 	} `json:"reflection"`
 	Result json.RawMessage `json:"result"`
+
+	// Fields of the JSON-RPC responses of Ogmios v6
+	JSONRPC string          `json:"jsonrpc,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+
+	// Fault of the JSON-WSP responses of Ogmios v5
+	Fault json.RawMessage `json:"fault,omitempty"`
 }
 
 func (c *apiClient) Transaction(ctx context.Context, hash string) (tc TransactionContent, err error) {
@@ -661,7 +707,11 @@ func (c *apiClient) TransactionRequiredSigners(ctx context.Context, hash string)
 	return tm, nil
 }
 
+// TransactionSubmit submits a serialized transaction. The hash of the
+// transaction is computed locally, so that it is also returned when the
+// request fails.
 func (c *apiClient) TransactionSubmit(ctx context.Context, cbor []byte) (hash string, err error) {
+	hash, _ = TransactionHash(cbor)
 	requestUrl, err := url.Parse(fmt.Sprintf("%s/%s/%s", c.server, resourceTx, resourceTxSubmit))
 	if err != nil {
 		return
@@ -682,6 +732,81 @@ func (c *apiClient) TransactionSubmit(ctx context.Context, cbor []byte) (hash st
 	return hash, nil
 }
 
+// spentInputsRejections are the ledger and Ogmios failures rejecting
+// transactions whose inputs are already spent, as are the inputs of
+// transactions already in the mempool or in the ledger.
+var spentInputsRejections = []string{
+	"BadInputsUTxO",
+	"unknown UTxO references",
+	"All inputs are spent",
+}
+
+// TransactionSubmitIdempotent submits a serialized transaction, and can be
+// retried safely when the outcome of a submission is unknown.
+//
+// The hash of the transaction is computed before submitting it, and is
+// returned along with transport errors. When the transaction is rejected
+// because its inputs are spent, and it is found in the mempool or in the
+// ledger, the submission is reported as successful with AlreadyKnown set.
+// If looking the transaction up fails, the error of the lookup is returned.
+func (c *apiClient) TransactionSubmitIdempotent(ctx context.Context, cbor []byte) (res SubmitResult, err error) {
+	if res.Hash, err = TransactionHash(cbor); err != nil {
+		return
+	}
+	if _, err = c.TransactionSubmit(ctx, cbor); err == nil {
+		res.Accepted = true
+		return res, nil
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return
+	}
+	if _, ok := apiErr.Response.(BadRequest); !ok {
+		return
+	}
+	msg := fmt.Sprintf("%+v", apiErr.Response)
+	spent := false
+	for _, rejection := range spentInputsRejections {
+		if strings.Contains(msg, rejection) {
+			spent = true
+			break
+		}
+	}
+	if !spent {
+		return
+	}
+	known, lookupErr := c.transactionKnown(ctx, res.Hash)
+	if lookupErr != nil {
+		return res, lookupErr
+	}
+	if known {
+		res.AlreadyKnown = true
+		return res, nil
+	}
+	return
+}
+
+// transactionKnown reports whether the transaction hash is in the ledger or
+// in the mempool. Errors other than 404 are returned.
+func (c *apiClient) transactionKnown(ctx context.Context, hash string) (bool, error) {
+	_, err := c.Transaction(ctx, hash)
+	if err == nil {
+		return true, nil
+	}
+	if !isNotFound(err) {
+		return false, fmt.Errorf("look up transaction %s: %w", hash, err)
+	}
+	_, err = c.MempoolTx(ctx, hash)
+	if err == nil {
+		return true, nil
+	}
+	if !isNotFound(err) {
+		return false, fmt.Errorf("look up mempool transaction %s: %w", hash, err)
+	}
+	return false, nil
+}
+
 func (c *apiClient) TransactionEvaluate(ctx context.Context, cbor []byte) (jsonResponse OgmiosResponse, err error) {
 	requestUrl, err := url.Parse(fmt.Sprintf("%s/%s", c.server, resourceTxEvaluate))
 	if err != nil {
@@ -2,6 +2,12 @@ package blockfrost_test
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -340,3 +346,153 @@ func TestTransactionEvaluateUTXOsIntegration(t *testing.T) {
 
 	testIntUtil(t, fp, &got, &want)
 }
+
+func loadTransactionCBOR(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(testdata, "json", "transactions", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := blockfrost.TransactionCBOR{}
+	if err := json.Unmarshal(data, &tc); err != nil {
+		t.Fatal(err)
+	}
+	return []byte(tc.Cbor)
+}
+
+func TestTransactionHash(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join(testdata, "transactioncborintegration.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	onChain := blockfrost.TransactionCBOR{}
+	if err := json.Unmarshal(golden, &onChain); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		cbor []byte
+		hash string
+	}{
+		// Fetched from mainnet by TestTransactionCBORIntegration.
+		{"on chain", []byte(onChain.Cbor), "6e5f825c82c1c6d6b77f2a14092f3b78c8f1b66db6f4cf8caec1555b6f967b3b"},
+		// Synthetic, see ledger.TestDecodeConwayTransaction.
+		{"conway", loadTransactionCBOR(t, "tx_cbor_conway.json"), "a75d043e184ec03f1f0342ebfe1a895b8942d507c380b462bec91e3a292f2f4a"},
+	}
+	for _, tt := range tests {
+		raw, err := hex.DecodeString(string(tt.cbor))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, in := range [][]byte{tt.cbor, raw} {
+			hash, err := blockfrost.TransactionHash(in)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if hash != tt.hash {
+				t.Fatalf("%s: got hash %s", tt.name, hash)
+			}
+		}
+	}
+	if _, err := blockfrost.TransactionHash([]byte("84a5")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestTransactionSubmitIdempotent(t *testing.T) {
+	cbor := loadTransactionCBOR(t, "tx_cbor_conway.json")
+	hash := "a75d043e184ec03f1f0342ebfe1a895b8942d507c380b462bec91e3a292f2f4a"
+	badInputs := `{"error":"Bad Request","message":"{\"contents\":{\"contents\":{\"contents\":{\"era\":\"ShelleyBasedEraConway\",\"error\":[\"ConwayUtxowFailure (UtxoFailure (BadInputsUTxO (fromList [])))\"]}}}}","status_code":400}`
+	notConserved := `{"error":"Bad Request","message":"ValueNotConservedUTxO","status_code":400}`
+	already := `{"error":"Bad Request","message":"ValueNotConservedUTxO: output already below minimum","status_code":400}`
+	allSpent := `{"error":"Bad Request","message":"ConwayMempoolFailure \"All inputs are spent. Transaction has probably already been included\"","status_code":400}`
+	notFound := `{"error":"Not Found","message":"The requested component has not been found.","status_code":404}`
+
+	tests := []struct {
+		name      string
+		submit    string
+		inLedger  bool
+		inMempool bool
+		lookupErr bool
+		want      blockfrost.SubmitResult
+		wantErr   bool
+	}{
+		{"accepted", "", false, false, false, blockfrost.SubmitResult{Hash: hash, Accepted: true}, false},
+		{"in ledger", badInputs, true, false, false, blockfrost.SubmitResult{Hash: hash, AlreadyKnown: true}, false},
+		{"in mempool", badInputs, false, true, false, blockfrost.SubmitResult{Hash: hash, AlreadyKnown: true}, false},
+		{"all inputs spent", allSpent, false, true, false, blockfrost.SubmitResult{Hash: hash, AlreadyKnown: true}, false},
+		{"inputs spent elsewhere", badInputs, false, false, false, blockfrost.SubmitResult{Hash: hash}, true},
+		{"lookup failed", badInputs, false, true, true, blockfrost.SubmitResult{Hash: hash}, true},
+		{"rejected", notConserved, true, true, false, blockfrost.SubmitResult{Hash: hash}, true},
+		{"rejected mentioning already", already, true, true, false, blockfrost.SubmitResult{Hash: hash}, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			lookups := 0
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/tx/submit":
+					if tt.submit != "" {
+						w.WriteHeader(http.StatusBadRequest)
+						w.Write([]byte(tt.submit))
+						return
+					}
+					w.Write([]byte(`"` + hash + `"`))
+				case "/txs/" + hash, "/mempool/" + hash:
+					lookups++
+					if tt.lookupErr {
+						w.WriteHeader(http.StatusInternalServerError)
+						w.Write([]byte(`{"error":"Internal Server Error","message":"","status_code":500}`))
+						return
+					}
+					if strings.HasPrefix(r.URL.Path, "/txs/") && tt.inLedger || strings.HasPrefix(r.URL.Path, "/mempool/") && tt.inMempool {
+						w.Write([]byte(`{"hash":"` + hash + `"}`))
+						return
+					}
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(notFound))
+				default:
+					t.Errorf("unexpected request %s", r.URL.Path)
+				}
+			}))
+			defer s.Close()
+
+			api := blockfrost.NewAPIClient(blockfrost.APIClientOptions{Server: s.URL, Client: &http.Client{}})
+			got, err := api.TransactionSubmitIdempotent(context.TODO(), cbor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			if (tt.submit == notConserved || tt.submit == already) && lookups > 0 {
+				t.Fatal("unexpected lookup of a rejected transaction")
+			}
+			if lookups > 2 {
+				t.Fatalf("got %d lookups", lookups)
+			}
+			if tt.lookupErr {
+				var apiErr *blockfrost.APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("got %v, want the error of the lookup", err)
+				}
+				if _, ok := apiErr.Response.(blockfrost.InternalServerError); !ok {
+					t.Fatalf("got %v, want the error of the lookup", err)
+				}
+			}
+		})
+	}
+
+	// The hash is known when the request fails.
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	s.Close()
+	api := blockfrost.NewAPIClient(blockfrost.APIClientOptions{Server: s.URL, Client: &http.Client{}})
+	got, err := api.TransactionSubmitIdempotent(context.TODO(), cbor)
+	if err == nil || got != (blockfrost.SubmitResult{Hash: hash}) {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+	if got, err := api.TransactionSubmit(context.TODO(), cbor); err == nil || got != hash {
+		t.Fatalf("got %s, %v", got, err)
+	}
+}
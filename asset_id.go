@@ -0,0 +1,244 @@
+package blockfrost
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blockfrost/blockfrost-go/internal/bech32"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// LovelaceUnit is the unit of ADA amounts in API responses.
+	LovelaceUnit = "lovelace"
+
+	policyIDSize     = 28
+	maxAssetNameSize = 32
+)
+
+// CIP67Label is an asset name label as defined by CIP-67.
+type CIP67Label uint16
+
+// Asset name labels defined by CIP-68
+const (
+	LabelReferenceNFT CIP67Label = 100
+	LabelNFT          CIP67Label = 222
+	LabelFT           CIP67Label = 333
+	LabelRFT          CIP67Label = 444
+)
+
+// ErrInvalidAssetUnit is returned when parsing a unit that is neither
+// "lovelace" nor a policy ID followed by an asset name.
+var ErrInvalidAssetUnit = errors.New("invalid asset unit")
+
+// AssetID identifies a native asset, or lovelace for the zero value. It is
+// comparable and can be used as a map key.
+type AssetID struct {
+	policyID string
+	name     string
+}
+
+// Lovelace is the AssetID of ADA amounts.
+var Lovelace = AssetID{}
+
+// ParseAssetID parses a unit, as found in the Unit field of amounts and the
+// Asset field of assets, into an AssetID.
+func ParseAssetID(unit string) (AssetID, error) {
+	if unit == LovelaceUnit {
+		return Lovelace, nil
+	}
+	raw, err := hex.DecodeString(unit)
+	if err != nil || len(raw) < policyIDSize || len(raw) > policyIDSize+maxAssetNameSize {
+		return AssetID{}, fmt.Errorf("%w: %q", ErrInvalidAssetUnit, unit)
+	}
+	return AssetID{
+		policyID: string(raw[:policyIDSize]),
+		name:     string(raw[policyIDSize:]),
+	}, nil
+}
+
+// NewAssetID returns the AssetID of an asset from its hex encoded policy ID
+// and asset name.
+func NewAssetID(policyID, assetName string) (AssetID, error) {
+	if len(policyID) != 2*policyIDSize {
+		return AssetID{}, fmt.Errorf("%w: invalid policy ID %q", ErrInvalidAssetUnit, policyID)
+	}
+	return ParseAssetID(policyID + assetName)
+}
+
+// NewAssetIDFromBytes returns the AssetID of an asset from its raw policy ID
+// and asset name.
+func NewAssetIDFromBytes(policyID, assetName []byte) (AssetID, error) {
+	if len(policyID) != policyIDSize || len(assetName) > maxAssetNameSize {
+		return AssetID{}, ErrInvalidAssetUnit
+	}
+	return AssetID{policyID: string(policyID), name: string(assetName)}, nil
+}
+
+// IsLovelace reports whether the AssetID refers to ADA.
+func (a AssetID) IsLovelace() bool {
+	return a.policyID == ""
+}
+
+// Unit returns the unit as used by the API, the concatenation of the hex
+// encoded policy ID and asset name, or "lovelace".
+func (a AssetID) Unit() string {
+	if a.IsLovelace() {
+		return LovelaceUnit
+	}
+	return hex.EncodeToString([]byte(a.policyID + a.name))
+}
+
+func (a AssetID) String() string {
+	return a.Unit()
+}
+
+// PolicyID returns the hex encoded policy ID, empty for lovelace.
+func (a AssetID) PolicyID() string {
+	return hex.EncodeToString([]byte(a.policyID))
+}
+
+// PolicyIDBytes returns the raw policy ID, empty for lovelace.
+func (a AssetID) PolicyIDBytes() []byte {
+	return []byte(a.policyID)
+}
+
+// NameHex returns the hex encoded asset name, including its CIP-67 label.
+func (a AssetID) NameHex() string {
+	return hex.EncodeToString([]byte(a.name))
+}
+
+// NameBytes returns the raw asset name, including its CIP-67 label.
+func (a AssetID) NameBytes() []byte {
+	return []byte(a.name)
+}
+
+// Name returns the asset name as UTF-8 text, without its CIP-67 label. It
+// returns an empty string if the name is not valid UTF-8.
+func (a AssetID) Name() string {
+	name := a.name
+	if _, ok := a.Label(); ok {
+		name = name[4:]
+	}
+	if !utf8.ValidString(name) {
+		return ""
+	}
+	return name
+}
+
+// Fingerprint returns the CIP-14 fingerprint of the asset, the Bech32
+// encoding of the blake2b-160 hash of its policy ID and asset name.
+func (a AssetID) Fingerprint() string {
+	if a.IsLovelace() {
+		return ""
+	}
+	h, _ := blake2b.New(20, nil)
+	h.Write([]byte(a.policyID))
+	h.Write([]byte(a.name))
+	fp, err := bech32.Encode("asset", h.Sum(nil))
+	if err != nil {
+		return ""
+	}
+	return fp
+}
+
+// Label returns the CIP-67 label prefixing the asset name, if the name
+// starts with a label with a valid checksum.
+func (a AssetID) Label() (CIP67Label, bool) {
+	if len(a.name) < 4 {
+		return 0, false
+	}
+	b := []byte(a.name[:4])
+	if b[0]&0xf0 != 0 || b[3]&0x0f != 0 {
+		return 0, false
+	}
+	label := uint16(b[0])<<12 | uint16(b[1])<<4 | uint16(b[2])>>4
+	if cip67Prefix(CIP67Label(label)) != binary.BigEndian.Uint32(b) {
+		return 0, false
+	}
+	return CIP67Label(label), true
+}
+
+// WithLabel returns the AssetID with the same policy ID and name but with
+// the given CIP-67 label, for instance to find the reference NFT
+// (LabelReferenceNFT) holding the metadata of a CIP-68 token.
+func (a AssetID) WithLabel(label CIP67Label) AssetID {
+	name := a.name
+	if _, ok := a.Label(); ok {
+		name = name[4:]
+	}
+	var prefix [4]byte
+	binary.BigEndian.PutUint32(prefix[:], cip67Prefix(label))
+	return AssetID{policyID: a.policyID, name: string(prefix[:]) + name}
+}
+
+// cip67Prefix returns the 4 byte asset name prefix for a label:
+// 0000 | 16 bits label | 8 bits CRC-8 of the label | 0000
+func cip67Prefix(label CIP67Label) uint32 {
+	return uint32(label)<<12 | uint32(crc8([]byte{byte(label >> 8), byte(label)}))<<4
+}
+
+// crc8 computes the CRC-8 checksum with polynomial 0x07 used by CIP-67.
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// MarshalText marshals the AssetID as its unit.
+func (a AssetID) MarshalText() ([]byte, error) {
+	return []byte(a.Unit()), nil
+}
+
+// UnmarshalText unmarshals an AssetID from its unit.
+func (a *AssetID) UnmarshalText(text []byte) error {
+	id, err := ParseAssetID(strings.TrimSpace(string(text)))
+	if err != nil {
+		return err
+	}
+	*a = id
+	return nil
+}
+
+// AssetID returns the AssetID of the amount unit.
+func (a AddressAmount) AssetID() (AssetID, error) {
+	return ParseAssetID(a.Unit)
+}
+
+// AssetID returns the AssetID of the amount unit.
+func (a AddressAmountExtended) AssetID() (AssetID, error) {
+	return ParseAssetID(a.Unit)
+}
+
+// AssetID returns the AssetID of the amount unit.
+func (a TxAmount) AssetID() (AssetID, error) {
+	return ParseAssetID(a.Unit)
+}
+
+// AssetID returns the AssetID of the asset unit.
+func (a AccountAssociatedAsset) AssetID() (AssetID, error) {
+	return ParseAssetID(a.Unit)
+}
+
+// AssetID returns the AssetID of the asset.
+func (a Asset) AssetID() (AssetID, error) {
+	return ParseAssetID(a.Asset)
+}
+
+// AssetID returns the AssetID of the asset.
+func (a AssetByPolicy) AssetID() (AssetID, error) {
+	return ParseAssetID(a.Asset)
+}
@@ -0,0 +1,154 @@
+package blockfrost_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+// Test vectors from CIP-14
+func TestAssetIDFingerprint(t *testing.T) {
+	tests := []struct {
+		policyID  string
+		assetName string
+		want      string
+	}{
+		{"7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc373", "", "asset1rjklcrnsdzqp65wjgrg55sy9723kw09mlgvlc3"},
+		{"7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc37e", "", "asset1nl0puwxmhas8fawxp8nx4e2q3wekg969n2auw3"},
+		{"1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209", "", "asset1uyuxku60yqe57nusqzjx38aan3f2wq6s93f6ea"},
+		{"7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc373", "504154415445", "asset13n25uv0yaf5kus35fm2k86cqy60z58d9xmde92"},
+		{"1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209", "504154415445", "asset1hv4p5tv2a837mzqrst04d0dcptdjmluqvdx9k3"},
+		{"1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209", "7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc373", "asset1aqrdypg669jgazruv5ah07nuyqe0wxjhe2el6f"},
+		{"7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc373", "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209", "asset17jd78wukhtrnmjh3fngzasxm8rck0l2r4hhyyt"},
+		{"7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc373", "0000000000000000000000000000000000000000000000000000000000000000", "asset1pkpwyknlvul7az0xx8czhl60pyel45rpje4z8w"},
+	}
+	for _, tt := range tests {
+		id, err := blockfrost.NewAssetID(tt.policyID, tt.assetName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := id.Fingerprint(); got != tt.want {
+			t.Fatalf("expected %s got %s", tt.want, got)
+		}
+		if id.PolicyID() != tt.policyID || id.NameHex() != tt.assetName {
+			t.Fatalf("expected %s %s got %s %s", tt.policyID, tt.assetName, id.PolicyID(), id.NameHex())
+		}
+	}
+}
+
+func TestAssetIDMatchesAsset(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(testdata, "resourceassetintegration.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	asset := blockfrost.Asset{}
+	if err := json.Unmarshal(data, &asset); err != nil {
+		t.Fatal(err)
+	}
+	id, err := asset.AssetID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Fingerprint() != asset.Fingerprint {
+		t.Fatalf("expected %s got %s", asset.Fingerprint, id.Fingerprint())
+	}
+	if id.PolicyID() != asset.PolicyId || id.NameHex() != asset.AssetName {
+		t.Fatalf("expected %s %s got %s %s", asset.PolicyId, asset.AssetName, id.PolicyID(), id.NameHex())
+	}
+	if id.Name() != "Firstcoin" {
+		t.Fatalf("expected Firstcoin got %s", id.Name())
+	}
+	if id.Unit() != asset.Asset {
+		t.Fatalf("expected %s got %s", asset.Asset, id.Unit())
+	}
+}
+
+func TestParseAssetID(t *testing.T) {
+	tests := []struct {
+		unit    string
+		wantErr bool
+	}{
+		{"lovelace", false},
+		{"b0d07d45fe9514f80213f4020e5a61241458be626841cde717cb38a7", false},
+		{"b0d07d45fe9514f80213f4020e5a61241458be626841cde717cb38a76e7574636f696e", false},
+		{"b0d07d45fe9514f80213f4020e5a61241458be626841cde717cb38", true},
+		{"b0d07d45fe9514f80213f4020e5a61241458be626841cde717cb38a76e7574636f696", true},
+		{"ada", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		id, err := blockfrost.TxAmount{Unit: tt.unit, Quantity: "1"}.AssetID()
+		if tt.wantErr {
+			if !errors.Is(err, blockfrost.ErrInvalidAssetUnit) {
+				t.Fatalf("%s: expected %v got %v", tt.unit, blockfrost.ErrInvalidAssetUnit, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.Unit() != tt.unit {
+			t.Fatalf("expected %s got %s", tt.unit, id.Unit())
+		}
+	}
+	if !blockfrost.Lovelace.IsLovelace() {
+		t.Fatal("expected lovelace")
+	}
+}
+
+// Test vectors from CIP-67
+func TestAssetIDLabel(t *testing.T) {
+	policyID := "b0d07d45fe9514f80213f4020e5a61241458be626841cde717cb38a7"
+	tests := []struct {
+		name  string
+		label blockfrost.CIP67Label
+	}{
+		{"000643b0", blockfrost.LabelReferenceNFT},
+		{"000de140", blockfrost.LabelNFT},
+		{"0014df10", blockfrost.LabelFT},
+		{"001bc280", blockfrost.LabelRFT},
+	}
+	for _, tt := range tests {
+		id, err := blockfrost.NewAssetID(policyID, tt.name+"4e7574")
+		if err != nil {
+			t.Fatal(err)
+		}
+		label, ok := id.Label()
+		if !ok || label != tt.label {
+			t.Fatalf("expected label %d got %d", tt.label, label)
+		}
+		if id.Name() != "Nut" {
+			t.Fatalf("expected Nut got %s", id.Name())
+		}
+		ref := id.WithLabel(blockfrost.LabelReferenceNFT)
+		if want := "000643b04e7574"; ref.NameHex() != want {
+			t.Fatalf("expected %s got %s", want, ref.NameHex())
+		}
+	}
+
+	// Invalid checksum
+	id, _ := blockfrost.NewAssetID(policyID, "000de1504e7574")
+	if _, ok := id.Label(); ok {
+		t.Fatal("expected no label")
+	}
+}
+
+func TestAssetIDJSON(t *testing.T) {
+	id, _ := blockfrost.ParseAssetID("b0d07d45fe9514f80213f4020e5a61241458be626841cde717cb38a76e7574636f696e")
+	balances := map[blockfrost.AssetID]string{id: "1", blockfrost.Lovelace: "2"}
+	data, err := json.Marshal(balances)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[blockfrost.AssetID]string{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got[id] != "1" || got[blockfrost.Lovelace] != "2" {
+		t.Fatalf("expected %v got %v", balances, got)
+	}
+}
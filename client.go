@@ -76,6 +76,10 @@ type APIClient interface {
 	BlockLatestTransactionsAll(ctx context.Context) <-chan BlockTransactionResult
 	BlockTransactions(ctx context.Context, hashOrNumber string, query APIQueryParams) ([]Transaction, error)
 	BlockTransactionsAll(ctx context.Context, hashOrNumber string) <-chan BlockTransactionResult
+	BlockLatestTransactionsCBOR(ctx context.Context, query APIQueryParams) ([]BlockTransactionCBOR, error)
+	BlockLatestTransactionsCBORAll(ctx context.Context) <-chan BlockTransactionCBORResult
+	BlockTransactionsCBOR(ctx context.Context, hashOrNumber string, query APIQueryParams) ([]BlockTransactionCBOR, error)
+	BlockTransactionsCBORAll(ctx context.Context, hashOrNumber string) <-chan BlockTransactionCBORResult
 	BlocksNext(ctx context.Context, hashOrNumber string) ([]Block, error)
 	BlocksPrevious(ctx context.Context, hashOrNumber string) ([]Block, error)
 	BlockBySlot(ctx context.Context, slotNumber int) (Block, error)
@@ -168,6 +172,7 @@ type APIClient interface {
 	ScriptCBOR(ctx context.Context, scriptHash string) (ScriptCBOR, error)
 	ScriptDatum(ctx context.Context, datumHash string) (ScriptDatum, error)
 	ScriptDatumCBOR(ctx context.Context, datumHash string) (ScriptDatumCBOR, error)
+	ReferenceScripts(ctx context.Context, hashes ...string) (ReferenceScripts, error)
 	Pool(ctx context.Context, poolID string) (Pool, error)
 	Pools(ctx context.Context, query APIQueryParams) (Pools, error)
 	PoolsAll(ctx context.Context) <-chan PoolsResult
@@ -227,6 +232,7 @@ type APIClient interface {
 	TransactionPoolUpdateCerts(ctx context.Context, hash string) ([]TransactionPoolCert, error)
 	TransactionPoolRetirementCerts(ctx context.Context, hash string) ([]TransactionPoolRetires, error)
 	TransactionSubmit(ctx context.Context, cbor []byte) (string, error)
+	TransactionSubmitIdempotent(ctx context.Context, cbor []byte) (SubmitResult, error)
 	TransactionEvaluate(ctx context.Context, cbor []byte) (OgmiosResponse, error)
 	TransactionEvaluateUTXOs(ctx context.Context, cbor []byte, additionalUtxoSet AdditionalUtxoSet) (OgmiosResponse, error)
 }
@@ -0,0 +1,228 @@
+// Package coinselection selects UTXOs paying for a set of outputs, using the
+// Largest-First and Random-Improve algorithms of CIP-2 extended to native
+// assets, and selects collateral for transactions running scripts.
+//
+// Selections only decide which UTXOs to spend: fees, minimum output values
+// and change outputs are left to the caller, which can include the fee in
+// the requested outputs.
+package coinselection
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/value"
+)
+
+var (
+	// ErrInsufficientFunds is returned when the UTXOs do not cover the
+	// requested outputs.
+	ErrInsufficientFunds = errors.New("coinselection: insufficient funds")
+
+	// ErrTooManyInputs is returned when covering the requested outputs needs
+	// more inputs than fit in a transaction.
+	ErrTooManyInputs = errors.New("coinselection: too many inputs")
+
+	// ErrInsufficientCollateral is returned when no set of at most
+	// MaxCollateralInputs UTXOs covers the required collateral.
+	ErrInsufficientCollateral = errors.New("coinselection: insufficient collateral")
+)
+
+// inputSize is an upper bound of the size in bytes added to a transaction
+// by an input spending a UTXO locked by a key: the input itself and the
+// witness of the key.
+const inputSize = 139
+
+// Params limit the inputs of a selection.
+type Params struct {
+	// MaxTxSize is the maximum size of a transaction in bytes. Zero means no
+	// limit.
+	MaxTxSize int
+
+	// ReservedSize is the size of the transaction apart from its inputs and
+	// their witnesses, such as its outputs and metadata.
+	ReservedSize int
+
+	// MaxCollateralInputs is the maximum number of collateral inputs. Zero
+	// means no limit.
+	MaxCollateralInputs int
+}
+
+// FromEpochParameters returns the limits set by protocol parameters, as
+// returned by LatestEpochParameters.
+func FromEpochParameters(ep blockfrost.EpochParameters) Params {
+	p := Params{MaxTxSize: ep.MaxTxSize}
+	if ep.MaxCollateralInputs != nil {
+		p.MaxCollateralInputs = *ep.MaxCollateralInputs
+	}
+	return p
+}
+
+// MaxInputs returns the maximum number of inputs of a selection, or 0 if
+// it is not limited.
+func (p Params) MaxInputs() int {
+	if p.MaxTxSize <= 0 {
+		return 0
+	}
+	n := (p.MaxTxSize - p.ReservedSize) / inputSize
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// Selection is the result of a coin selection.
+type Selection struct {
+	// Selected UTXOs, in the order they were selected
+	Inputs []blockfrost.AddressUTXO
+
+	// UTXOs that were not selected, in their original order
+	Remaining []blockfrost.AddressUTXO
+
+	// Total value of the selected UTXOs
+	Total value.Value
+
+	// Value of the selected UTXOs in excess of the requested outputs
+	Change value.Value
+}
+
+type entry struct {
+	utxo  blockfrost.AddressUTXO
+	value value.Value
+	index int
+}
+
+func entries(utxos []blockfrost.AddressUTXO) ([]entry, error) {
+	out := make([]entry, len(utxos))
+	for i, u := range utxos {
+		v, err := value.FromAddressAmounts(u.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("coinselection: utxo %s#%d: %w", u.TxHash, u.OutputIndex, err)
+		}
+		out[i] = entry{utxo: u, value: v, index: i}
+	}
+	return out, nil
+}
+
+func sum(outputs []value.Value) value.Value {
+	var total value.Value
+	for _, o := range outputs {
+		total = total.Add(o)
+	}
+	return total
+}
+
+// assetOrder returns the assets of v, lovelace last.
+func assetOrder(v value.Value) []blockfrost.AssetID {
+	return append(v.AssetIDs(), blockfrost.Lovelace)
+}
+
+// newSelection returns the selection of selected among all, covering
+// requested.
+func newSelection(all []entry, selected []entry, requested value.Value) *Selection {
+	s := &Selection{}
+	picked := make(map[int]bool, len(selected))
+	for _, e := range selected {
+		picked[e.index] = true
+		s.Inputs = append(s.Inputs, e.utxo)
+		s.Total = s.Total.Add(e.value)
+	}
+	for _, e := range all {
+		if !picked[e.index] {
+			s.Remaining = append(s.Remaining, e.utxo)
+		}
+	}
+	s.Change = s.Total.Sub(requested)
+	return s
+}
+
+func insufficient(missing value.Value, id blockfrost.AssetID) error {
+	return fmt.Errorf("%w: missing %s %s", ErrInsufficientFunds, missing.Quantity(id), id.Unit())
+}
+
+func tooMany(max int, p Params) error {
+	return fmt.Errorf("%w: more than %d inputs for a maximum transaction size of %d bytes", ErrTooManyInputs, max, p.MaxTxSize)
+}
+
+// LargestFirst selects UTXOs covering outputs. For each native asset of
+// the outputs, then for lovelace, it selects the UTXOs holding the largest
+// quantity of the asset until it is covered. UTXOs holding the same quantity
+// are selected in their original order, so the selection is deterministic.
+func LargestFirst(utxos []blockfrost.AddressUTXO, outputs []value.Value, p Params) (*Selection, error) {
+	all, err := entries(utxos)
+	if err != nil {
+		return nil, err
+	}
+	requested := sum(outputs)
+	max := p.MaxInputs()
+
+	remaining := append([]entry{}, all...)
+	var selected []entry
+	var total value.Value
+	for _, id := range assetOrder(requested) {
+		sort.SliceStable(remaining, func(i, j int) bool {
+			return remaining[i].value.Quantity(id).Cmp(remaining[j].value.Quantity(id)) > 0
+		})
+		for total.Quantity(id).Cmp(requested.Quantity(id)) < 0 {
+			if len(remaining) == 0 || remaining[0].value.Quantity(id).Sign() <= 0 {
+				return nil, insufficient(requested.Sub(total), id)
+			}
+			if max > 0 && len(selected) == max {
+				return nil, tooMany(max, p)
+			}
+			total = total.Add(remaining[0].value)
+			selected = append(selected, remaining[0])
+			remaining = remaining[1:]
+		}
+	}
+	return newSelection(all, selected, requested), nil
+}
+
+// SelectCollateral selects UTXOs holding at least amount lovelace and no
+// native assets, locked by keys, as collateral. The smallest single UTXO
+// covering amount is preferred. Otherwise, the largest UTXOs are selected,
+// up to MaxCollateralInputs.
+func SelectCollateral(utxos []blockfrost.AddressUTXO, amount uint64, p Params) ([]blockfrost.AddressUTXO, error) {
+	all, err := entries(utxos)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []entry
+	for _, e := range all {
+		if len(e.value.Assets) > 0 {
+			continue
+		}
+		addr, err := address.Parse(e.utxo.Address)
+		if err != nil || addr.Byron != nil || addr.Payment.IsZero() || addr.Payment.Type != address.CredentialKey {
+			continue
+		}
+		candidates = append(candidates, e)
+	}
+
+	want := new(big.Int).SetUint64(amount)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].value.Coin().Cmp(candidates[j].value.Coin()) < 0
+	})
+	for _, e := range candidates {
+		if e.value.Coin().Cmp(want) >= 0 {
+			return []blockfrost.AddressUTXO{e.utxo}, nil
+		}
+	}
+
+	var out []blockfrost.AddressUTXO
+	total := new(big.Int)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if p.MaxCollateralInputs > 0 && len(out) == p.MaxCollateralInputs {
+			break
+		}
+		out = append(out, candidates[i].utxo)
+		if total.Add(total, candidates[i].value.Coin()).Cmp(want) >= 0 {
+			return out, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s of %d lovelace in %d inputs", ErrInsufficientCollateral, total, amount, len(out))
+}
@@ -0,0 +1,259 @@
+package coinselection_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/coinselection"
+	"github.com/blockfrost/blockfrost-go/value"
+)
+
+const testdata = "../testdata"
+
+var (
+	keyAddress    = newAddress(address.CredentialKey)
+	scriptAddress = newAddress(address.CredentialScript)
+	coin          = mustAssetID(strings.Repeat("ab", 28) + hex.EncodeToString([]byte("coin")))
+)
+
+func newAddress(t address.CredentialType) string {
+	cred, err := address.CredentialFromHex(t, strings.Repeat("01", 28))
+	if err != nil {
+		panic(err)
+	}
+	return address.NewEnterpriseAddress(address.Testnet, cred).String()
+}
+
+func mustAssetID(unit string) blockfrost.AssetID {
+	id, err := blockfrost.ParseAssetID(unit)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// utxo returns a UTXO at index i of a key address, holding ada and coins.
+func utxo(i int, ada int64, coins int64) blockfrost.AddressUTXO {
+	u := blockfrost.AddressUTXO{
+		Address:     keyAddress,
+		TxHash:      strings.Repeat("00", 32),
+		OutputIndex: i,
+		Amount:      []blockfrost.AddressAmount{{Unit: "lovelace", Quantity: strconv.FormatInt(ada*1000000, 10)}},
+	}
+	if coins > 0 {
+		u.Amount = append(u.Amount, blockfrost.AddressAmount{Unit: coin.Unit(), Quantity: strconv.FormatInt(coins, 10)})
+	}
+	return u
+}
+
+func indexes(utxos []blockfrost.AddressUTXO) []int {
+	out := []int{}
+	for _, u := range utxos {
+		out = append(out, u.OutputIndex)
+	}
+	return out
+}
+
+func ada(n int64) value.Value {
+	return value.New(n * 1000000)
+}
+
+func TestFromEpochParameters(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(testdata, "epochparametersintegration.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ep := blockfrost.EpochParameters{}
+	if err := json.Unmarshal(data, &ep); err != nil {
+		t.Fatal(err)
+	}
+	p := coinselection.FromEpochParameters(ep)
+	if p.MaxTxSize != 16384 || p.MaxCollateralInputs != 3 {
+		t.Fatalf("unexpected params %+v", p)
+	}
+	if got := p.MaxInputs(); got != 117 {
+		t.Fatalf("got %d max inputs", got)
+	}
+	if got := (coinselection.Params{}).MaxInputs(); got != 0 {
+		t.Fatalf("got %d max inputs, want no limit", got)
+	}
+}
+
+func TestLargestFirst(t *testing.T) {
+	utxos := []blockfrost.AddressUTXO{utxo(0, 1, 0), utxo(1, 5, 0), utxo(2, 3, 0), utxo(3, 10, 0), utxo(4, 5, 0)}
+	sel, err := coinselection.LargestFirst(utxos, []value.Value{ada(7), ada(6)}, coinselection.Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := indexes(sel.Inputs); !reflect.DeepEqual(got, []int{3, 1}) {
+		t.Fatalf("got inputs %v", got)
+	}
+	if got := indexes(sel.Remaining); !reflect.DeepEqual(got, []int{0, 2, 4}) {
+		t.Fatalf("got remaining %v", got)
+	}
+	if !sel.Total.Equal(ada(15)) || !sel.Change.Equal(ada(2)) {
+		t.Fatalf("got total %s and change %s", sel.Total, sel.Change)
+	}
+}
+
+func TestLargestFirstMultiAsset(t *testing.T) {
+	utxos := []blockfrost.AddressUTXO{utxo(0, 50, 0), utxo(1, 2, 3), utxo(2, 2, 10), utxo(3, 1, 1)}
+	out := ada(3).WithAsset(coin, big.NewInt(12))
+	sel, err := coinselection.LargestFirst(utxos, []value.Value{out}, coinselection.Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Coins are covered first, by the UTXOs holding the most, which also
+	// cover the lovelace.
+	if got := indexes(sel.Inputs); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Fatalf("got inputs %v", got)
+	}
+	if want := ada(1).WithAsset(coin, big.NewInt(1)); !sel.Change.Equal(want) {
+		t.Fatalf("got change %s, want %s", sel.Change, want)
+	}
+}
+
+func TestSelectionErrors(t *testing.T) {
+	utxos := []blockfrost.AddressUTXO{utxo(0, 1, 0), utxo(1, 1, 0), utxo(2, 1, 5)}
+	limited := coinselection.Params{MaxTxSize: 2*139 + 100, ReservedSize: 100}
+	rnd := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		name   string
+		out    value.Value
+		params coinselection.Params
+		want   error
+	}{
+		{"lovelace", ada(4), coinselection.Params{}, coinselection.ErrInsufficientFunds},
+		{"asset", value.Value{}.WithAsset(coin, big.NewInt(6)), coinselection.Params{}, coinselection.ErrInsufficientFunds},
+		{"inputs", ada(3), limited, coinselection.ErrTooManyInputs},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := coinselection.LargestFirst(utxos, []value.Value{test.out}, test.params); !errors.Is(err, test.want) {
+				t.Fatalf("largest first: got %v, want %v", err, test.want)
+			}
+			if _, err := coinselection.RandomImprove(utxos, []value.Value{test.out}, test.params, rnd); !errors.Is(err, test.want) {
+				t.Fatalf("random improve: got %v, want %v", err, test.want)
+			}
+		})
+	}
+
+	bad := []blockfrost.AddressUTXO{{Amount: []blockfrost.AddressAmount{{Unit: "lovelace", Quantity: "x"}}}}
+	if _, err := coinselection.LargestFirst(bad, []value.Value{ada(1)}, coinselection.Params{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRandomImprove(t *testing.T) {
+	var utxos []blockfrost.AddressUTXO
+	for i := 0; i < 40; i++ {
+		utxos = append(utxos, utxo(i, 1+int64(i%4), 0))
+	}
+	outputs := []value.Value{ada(10), ada(4)}
+
+	sel, err := coinselection.RandomImprove(utxos, outputs, coinselection.Params{}, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sel.Total.Covers(ada(14)) || sel.Change.IsNegative() {
+		t.Fatalf("got total %s and change %s", sel.Total, sel.Change)
+	}
+	if len(sel.Inputs)+len(sel.Remaining) != len(utxos) {
+		t.Fatal("selected and remaining UTXOs do not partition the UTXOs")
+	}
+	// The improvement phase adds change close to the amount of each output,
+	// without exceeding three times that amount.
+	if sel.Total.Coin().Cmp(ada(20).Coin()) < 0 || sel.Total.Coin().Cmp(ada(42).Coin()) > 0 {
+		t.Fatalf("got total %s", sel.Total)
+	}
+
+	// The selection is reproducible from the seed.
+	again, err := coinselection.RandomImprove(utxos, outputs, coinselection.Params{}, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(indexes(sel.Inputs), indexes(again.Inputs)) {
+		t.Fatalf("got %v and %v", indexes(sel.Inputs), indexes(again.Inputs))
+	}
+
+	// The number of inputs is limited.
+	limited := coinselection.Params{MaxTxSize: 8 * 139}
+	sel, err = coinselection.RandomImprove(utxos, outputs, limited, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sel.Inputs) > 8 {
+		t.Fatalf("got %d inputs", len(sel.Inputs))
+	}
+}
+
+func TestRandomImproveMultiAsset(t *testing.T) {
+	utxos := []blockfrost.AddressUTXO{utxo(0, 100, 10), utxo(1, 3, 0), utxo(2, 4, 0)}
+	outputs := []value.Value{
+		ada(2).WithAsset(coin, big.NewInt(5)),
+		ada(2).WithAsset(coin, big.NewInt(5)),
+	}
+	// The only UTXO holding coins is selected for the first output, and the
+	// selection falls back to largest first for the second one.
+	sel, err := coinselection.RandomImprove(utxos, outputs, coinselection.Params{}, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := indexes(sel.Inputs); !reflect.DeepEqual(got, []int{0}) {
+		t.Fatalf("got inputs %v", got)
+	}
+	if !sel.Change.Equal(ada(96)) {
+		t.Fatalf("got change %s", sel.Change)
+	}
+}
+
+func TestSelectCollateral(t *testing.T) {
+	script := utxo(9, 50, 0)
+	script.Address = scriptAddress
+	utxos := []blockfrost.AddressUTXO{utxo(0, 3, 0), utxo(1, 10, 0), utxo(2, 20, 1), utxo(3, 4, 0), script}
+
+	tests := []struct {
+		amount int64
+		max    int
+		want   []int
+	}{
+		{2, 3, []int{0}},
+		{5, 3, []int{1}},
+		{12, 3, []int{1, 3}},
+		{17, 3, []int{1, 3, 0}},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.amount), func(t *testing.T) {
+			got, err := coinselection.SelectCollateral(utxos, uint64(test.amount*1000000), coinselection.Params{MaxCollateralInputs: test.max})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(indexes(got), test.want) {
+				t.Fatalf("got %v, want %v", indexes(got), test.want)
+			}
+		})
+	}
+
+	for _, test := range []struct {
+		amount int64
+		max    int
+	}{{12, 1}, {18, 3}} {
+		_, err := coinselection.SelectCollateral(utxos, uint64(test.amount*1000000), coinselection.Params{MaxCollateralInputs: test.max})
+		if !errors.Is(err, coinselection.ErrInsufficientCollateral) {
+			t.Fatalf("%d in %d inputs: got %v, want ErrInsufficientCollateral", test.amount, test.max, err)
+		}
+	}
+}
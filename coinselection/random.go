@@ -0,0 +1,121 @@
+package coinselection
+
+import (
+	"math/big"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/value"
+)
+
+// RandomImprove selects UTXOs covering outputs with the Random-Improve
+// algorithm, which tends to create change outputs of the size of the
+// payments and so keeps the UTXO set of a wallet suited to future payments.
+//
+// Outputs are processed by decreasing amount of lovelace. For each output,
+// UTXOs holding each of its native assets, then lovelace, are selected at
+// random until the output is covered. Then, by increasing amount of
+// lovelace, UTXOs without native assets are added at random to the
+// selection of each output while they bring its lovelace closer to twice
+// the amount of the output, without exceeding three times that amount.
+//
+// When the UTXOs left do not cover an output, which may happen with UTXOs
+// selected for an output holding assets needed by another, the selection
+// falls back to LargestFirst.
+//
+// rnd provides the randomness, so that selections can be reproduced from a
+// seed. If rnd is nil, a source seeded with the current time is used.
+func RandomImprove(utxos []blockfrost.AddressUTXO, outputs []value.Value, p Params, rnd *rand.Rand) (*Selection, error) {
+	all, err := entries(utxos)
+	if err != nil {
+		return nil, err
+	}
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	requested := sum(outputs)
+	max := p.MaxInputs()
+
+	order := make([]int, len(outputs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return outputs[order[i]].Coin().Cmp(outputs[order[j]].Coin()) > 0
+	})
+
+	remaining := append([]entry{}, all...)
+	count := 0
+	take := func(i int) entry {
+		e := remaining[i]
+		remaining = append(remaining[:i:i], remaining[i+1:]...)
+		count++
+		return e
+	}
+
+	// Random selection phase
+	selections := make([][]entry, len(outputs))
+	totals := make([]value.Value, len(outputs))
+	for _, o := range order {
+		out := outputs[o]
+		for _, id := range assetOrder(out) {
+			for totals[o].Quantity(id).Cmp(out.Quantity(id)) < 0 {
+				var holding []int
+				for i, e := range remaining {
+					if e.value.Quantity(id).Sign() > 0 {
+						holding = append(holding, i)
+					}
+				}
+				if len(holding) == 0 {
+					return LargestFirst(utxos, outputs, p)
+				}
+				if max > 0 && count == max {
+					return nil, tooMany(max, p)
+				}
+				e := take(holding[rnd.Intn(len(holding))])
+				selections[o] = append(selections[o], e)
+				totals[o] = totals[o].Add(e.value)
+			}
+		}
+	}
+
+	// Improvement phase
+	for i := len(order) - 1; i >= 0; i-- {
+		o := order[i]
+		target := outputs[o].Coin()
+		ideal := new(big.Int).Mul(target, big.NewInt(2))
+		limit := new(big.Int).Mul(target, big.NewInt(3))
+		for max == 0 || count < max {
+			var pure []int
+			for j, e := range remaining {
+				if len(e.value.Assets) == 0 {
+					pure = append(pure, j)
+				}
+			}
+			if len(pure) == 0 {
+				break
+			}
+			j := pure[rnd.Intn(len(pure))]
+			cur := totals[o].Coin()
+			next := new(big.Int).Add(cur, remaining[j].value.Coin())
+			if next.Cmp(limit) > 0 || distance(ideal, next).Cmp(distance(ideal, cur)) >= 0 {
+				break
+			}
+			e := take(j)
+			selections[o] = append(selections[o], e)
+			totals[o] = totals[o].Add(e.value)
+		}
+	}
+
+	var selected []entry
+	for _, o := range order {
+		selected = append(selected, selections[o]...)
+	}
+	return newSelection(all, selected, requested), nil
+}
+
+func distance(a, b *big.Int) *big.Int {
+	return new(big.Int).Abs(new(big.Int).Sub(a, b))
+}
@@ -0,0 +1,229 @@
+// Package epoch notifies of epoch transitions.
+//
+// A Notifier sleeps until the end of the current epoch, confirms through
+// the API that the next epoch started, and calls its handler with the
+// closed epoch, the parameters of the new epoch and what changed:
+//
+//	n := epoch.NewNotifier(client, epoch.Options{Pools: []string{poolID}})
+//	err := n.Run(ctx, func(ctx context.Context, tr epoch.Transition) error {
+//		return distributeRewards(tr.Closed, tr.Stake[poolID])
+//	})
+package epoch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/internal/apierr"
+	"github.com/blockfrost/blockfrost-go/params"
+	"github.com/blockfrost/blockfrost-go/timeline"
+)
+
+// Transition is the transition from an epoch to the next.
+type Transition struct {
+	// Closed is the epoch that ended.
+	Closed blockfrost.Epoch
+
+	// Current is the epoch that started.
+	Current blockfrost.Epoch
+
+	// Parameters are the protocol parameters of Current, and Changes what
+	// changed since Closed.
+	Parameters blockfrost.EpochParameters
+	Changes    []params.Change
+
+	// Stake is the stake distribution of Current, by pool, of the pools
+	// in Options.Pools.
+	Stake map[string][]blockfrost.EpochStakeByPool
+}
+
+// Handler is called with each epoch transition. Returning an error stops
+// the Notifier.
+type Handler func(ctx context.Context, tr Transition) error
+
+// Options configures a Notifier.
+type Options struct {
+	// Timeline computes the end of epochs. Default: the EndTime returned
+	// by the API.
+	Timeline *timeline.Timeline
+
+	// Delay after the end of an epoch before confirming the transition,
+	// leaving time for the API to index the first block of the next epoch.
+	Delay time.Duration
+
+	// Retry is the interval between attempts to confirm a transition, and
+	// between retries of failed requests. Default: 30s.
+	Retry time.Duration
+
+	// Pools are the pools whose stake distribution is captured for each
+	// new epoch.
+	Pools []string
+
+	// OnError is called with the errors of requests, which are retried
+	// after Retry.
+	OnError func(error)
+}
+
+// Notifier notifies of epoch transitions.
+type Notifier struct {
+	client blockfrost.APIClient
+	opts   Options
+}
+
+// NewNotifier returns a Notifier of the transitions of the chain served by
+// client.
+func NewNotifier(client blockfrost.APIClient, opts Options) *Notifier {
+	if opts.Retry <= 0 {
+		opts.Retry = 30 * time.Second
+	}
+	return &Notifier{client: client, opts: opts}
+}
+
+// Run calls h with each epoch transition, from the end of the current
+// epoch, until ctx is done or h returns an error, which Run returns. If
+// several epochs passed between two confirmations, h is called for each
+// of them, in order.
+func (n *Notifier) Run(ctx context.Context, h Handler) error {
+	cur, err := n.latest(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := sleep(ctx, time.Until(n.end(cur).Add(n.opts.Delay))); err != nil {
+			return err
+		}
+
+		latest, err := n.confirm(ctx, cur.Epoch)
+		if err != nil {
+			return err
+		}
+
+		for cur.Epoch < latest.Epoch {
+			var tr Transition
+			if err := n.retry(ctx, func() (err error) {
+				tr, err = n.transition(ctx, cur.Epoch, latest)
+				return err
+			}); err != nil {
+				return err
+			}
+			if err := h(ctx, tr); err != nil {
+				return err
+			}
+			cur = tr.Current
+		}
+	}
+}
+
+func (n *Notifier) latest(ctx context.Context) (e blockfrost.Epoch, err error) {
+	err = n.retry(ctx, func() (err error) {
+		if e, err = n.client.EpochLatest(ctx); err != nil {
+			err = fmt.Errorf("epoch: latest epoch: %w", err)
+		}
+		return err
+	})
+	return e, err
+}
+
+// confirm waits until the latest epoch is after the epoch closed, and
+// returns it.
+func (n *Notifier) confirm(ctx context.Context, closed int) (blockfrost.Epoch, error) {
+	for {
+		latest, err := n.latest(ctx)
+		if err != nil || latest.Epoch > closed {
+			return latest, err
+		}
+		if err := sleep(ctx, n.opts.Retry); err != nil {
+			return latest, err
+		}
+	}
+}
+
+// end returns the end of the epoch e.
+func (n *Notifier) end(e blockfrost.Epoch) time.Time {
+	if n.opts.Timeline != nil {
+		if t, err := n.opts.Timeline.EpochEnd(e.Epoch); err == nil {
+			return t
+		}
+	}
+	return time.Unix(int64(e.EndTime), 0)
+}
+
+// transition returns the transition from the epoch closed to the next,
+// reusing latest if it is the next one.
+func (n *Notifier) transition(ctx context.Context, closed int, latest blockfrost.Epoch) (Transition, error) {
+	var (
+		tr  Transition
+		err error
+	)
+	if tr.Closed, err = n.client.Epoch(ctx, closed); err != nil {
+		return tr, fmt.Errorf("epoch: epoch %d: %w", closed, err)
+	}
+	tr.Current = latest
+	if latest.Epoch != closed+1 {
+		if tr.Current, err = n.client.Epoch(ctx, closed+1); err != nil {
+			return tr, fmt.Errorf("epoch: epoch %d: %w", closed+1, err)
+		}
+	}
+	prev, err := n.client.EpochParameters(ctx, closed)
+	if err != nil {
+		return tr, fmt.Errorf("epoch: parameters of epoch %d: %w", closed, err)
+	}
+	if tr.Parameters, err = n.client.EpochParameters(ctx, closed+1); err != nil {
+		return tr, fmt.Errorf("epoch: parameters of epoch %d: %w", closed+1, err)
+	}
+	tr.Changes = params.Diff(prev, tr.Parameters)
+
+	if len(n.opts.Pools) > 0 {
+		tr.Stake = make(map[string][]blockfrost.EpochStakeByPool, len(n.opts.Pools))
+	}
+	for _, pool := range n.opts.Pools {
+		stake := []blockfrost.EpochStakeByPool{}
+		for res := range n.client.EpochStakeDistributionByPoolAll(ctx, closed+1, pool) {
+			if res.Err != nil && !apierr.IsNotFound(res.Err) && err == nil {
+				err = fmt.Errorf("epoch: stake of %s in epoch %d: %w", pool, closed+1, res.Err)
+			}
+			stake = append(stake, res.Res...)
+		}
+		if err != nil {
+			return tr, err
+		}
+		tr.Stake[pool] = stake
+	}
+	return tr, nil
+}
+
+// retry calls fn until it succeeds, every Retry, or until ctx is done.
+func (n *Notifier) retry(ctx context.Context, fn func() error) error {
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if n.opts.OnError != nil {
+			n.opts.OnError(err)
+		}
+		if err := sleep(ctx, n.opts.Retry); err != nil {
+			return err
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
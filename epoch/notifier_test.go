@@ -0,0 +1,145 @@
+package epoch_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/epoch"
+	"github.com/blockfrost/blockfrost-go/params"
+)
+
+var errNotFound = &blockfrost.APIError{Response: blockfrost.NotFound{StatusCode: 404, Error: "Not Found"}}
+
+// chain is a fake client serving epochs, each with a min fee of 100 times
+// its number. Epochs before the current one have ended.
+type chain struct {
+	blockfrost.APIClient
+
+	mu      sync.Mutex
+	current int
+	fails   int
+}
+
+func (c *chain) epoch(n int) blockfrost.Epoch {
+	e := blockfrost.Epoch{Epoch: n, BlockCount: 10 * n}
+	if n == c.current {
+		e.EndTime = int(time.Now().Unix())
+	}
+	return e
+}
+
+func (c *chain) advance(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current += n
+}
+
+func (c *chain) EpochLatest(ctx context.Context) (blockfrost.Epoch, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fails > 0 {
+		c.fails--
+		return blockfrost.Epoch{}, errors.New("unavailable")
+	}
+	return c.epoch(c.current), nil
+}
+
+func (c *chain) Epoch(ctx context.Context, n int) (blockfrost.Epoch, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n > c.current {
+		return blockfrost.Epoch{}, errNotFound
+	}
+	return c.epoch(n), nil
+}
+
+func (c *chain) EpochParameters(ctx context.Context, n int) (blockfrost.EpochParameters, error) {
+	return blockfrost.EpochParameters{Epoch: n, MinFeeA: 100 * n, MaxTxSize: 16384}, nil
+}
+
+func (c *chain) EpochStakeDistributionByPoolAll(ctx context.Context, n int, pool string) <-chan blockfrost.EpochStakeByPoolResult {
+	ch := make(chan blockfrost.EpochStakeByPoolResult, 1)
+	if pool == "pool1" {
+		ch <- blockfrost.EpochStakeByPoolResult{Res: []blockfrost.EpochStakeByPool{{StakeAddress: "stake1", Amount: "100"}}}
+	} else {
+		ch <- blockfrost.EpochStakeByPoolResult{Err: errNotFound}
+	}
+	close(ch)
+	return ch
+}
+
+func TestNotifier(t *testing.T) {
+	c := &chain{current: 100, fails: 1}
+	var errs []error
+	n := epoch.NewNotifier(c, epoch.Options{
+		Retry:   time.Millisecond,
+		Pools:   []string{"pool1", "pool2"},
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transitions := make(chan epoch.Transition)
+	done := make(chan error, 1)
+	go func() {
+		done <- n.Run(ctx, func(ctx context.Context, tr epoch.Transition) error {
+			transitions <- tr
+			return nil
+		})
+	}()
+	next := func() epoch.Transition {
+		t.Helper()
+		select {
+		case tr := <-transitions:
+			return tr
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out")
+		}
+		return epoch.Transition{}
+	}
+
+	// Epoch 100 ended, but the transition is only notified once the API
+	// serves epoch 101.
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case tr := <-transitions:
+		t.Fatalf("unexpected transition %+v", tr)
+	default:
+	}
+	c.advance(1)
+	tr := next()
+	if tr.Closed.Epoch != 100 || tr.Closed.BlockCount != 1000 || tr.Current.Epoch != 101 || tr.Parameters.Epoch != 101 {
+		t.Fatalf("got %+v", tr)
+	}
+	if want := []params.Change{{Parameter: "min_fee_a", Old: 10000, New: 10100}}; !reflect.DeepEqual(tr.Changes, want) {
+		t.Fatalf("got changes %+v, want %+v", tr.Changes, want)
+	}
+	wantStake := map[string][]blockfrost.EpochStakeByPool{
+		"pool1": {{StakeAddress: "stake1", Amount: "100"}},
+		"pool2": {},
+	}
+	if !reflect.DeepEqual(tr.Stake, wantStake) {
+		t.Fatalf("got stake %+v", tr.Stake)
+	}
+
+	// Transitions missed are notified in order.
+	c.advance(2)
+	for _, want := range []int{102, 103} {
+		if tr := next(); tr.Current.Epoch != want || tr.Closed.Epoch != want-1 {
+			t.Fatalf("got transition %d -> %d, want %d -> %d", tr.Closed.Epoch, tr.Current.Epoch, want-1, want)
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v", err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("got errors %v, want 1", errs)
+	}
+}
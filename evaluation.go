@@ -0,0 +1,468 @@
+package blockfrost
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidEvaluation is returned when an OgmiosResponse is not the
+// response of a transaction evaluation.
+var ErrInvalidEvaluation = errors.New("invalid evaluation response")
+
+// Redeemer purposes, as named by Ogmios v6. The names of Ogmios v5,
+// "certificate" and "withdrawal", are mapped to PurposePublish and
+// PurposeWithdraw.
+const (
+	PurposeSpend    = "spend"
+	PurposeMint     = "mint"
+	PurposePublish  = "publish"
+	PurposeWithdraw = "withdraw"
+	PurposeVote     = "vote"
+	PurposePropose  = "propose"
+)
+
+var purposeOrder = map[string]int{
+	PurposeSpend:    0,
+	PurposeMint:     1,
+	PurposePublish:  2,
+	PurposeWithdraw: 3,
+	PurposeVote:     4,
+	PurposePropose:  5,
+}
+
+// RedeemerEvaluation is the execution budget of a redeemer, as evaluated
+// by TransactionEvaluate.
+type RedeemerEvaluation struct {
+	// Purpose of the redeemer, one of the Purpose constants
+	Purpose string
+	// Index of the input, policy, certificate, withdrawal, vote or proposal
+	// the redeemer is for
+	Index int
+	// Memory units
+	Memory uint64
+	// CPU steps
+	Steps uint64
+}
+
+// EvaluationErrorKind is the kind of an EvaluationError.
+type EvaluationErrorKind string
+
+const (
+	// EvaluationScriptFailure is a script that failed validation.
+	EvaluationScriptFailure EvaluationErrorKind = "script failure"
+	// EvaluationOverBudget is a script that exceeded its execution budget.
+	EvaluationOverBudget EvaluationErrorKind = "over budget"
+	// EvaluationUnknownInputs is an input, or reference input, that is not
+	// in the UTXO set nor in the additional UTXO set.
+	EvaluationUnknownInputs EvaluationErrorKind = "unknown inputs"
+	// EvaluationMissingDatums is a datum required by a script and not
+	// provided.
+	EvaluationMissingDatums EvaluationErrorKind = "missing datums"
+	// EvaluationMissingScripts is a script that is not provided.
+	EvaluationMissingScripts EvaluationErrorKind = "missing scripts"
+	// EvaluationExtraRedeemers is a redeemer that is not required.
+	EvaluationExtraRedeemers EvaluationErrorKind = "extra redeemers"
+	// EvaluationIncompatibleEra is a transaction of an era that cannot be
+	// evaluated.
+	EvaluationIncompatibleEra EvaluationErrorKind = "incompatible era"
+	// EvaluationOther is any other error.
+	EvaluationOther EvaluationErrorKind = "other"
+)
+
+// RedeemerFailure is the failure of a redeemer.
+type RedeemerFailure struct {
+	Purpose string
+	Index   int
+	Kind    EvaluationErrorKind
+	Message string
+	// Traces logged by the script
+	Traces []string
+	// Inputs that are unknown, as "<tx hash>#<index>"
+	Inputs []string
+	// Hashes of the missing datums or scripts
+	Hashes []string
+}
+
+// EvaluationError is the failure of a transaction evaluation.
+type EvaluationError struct {
+	// Kind of the error. For errors of individual redeemers, it is the kind
+	// of the first of Failures.
+	Kind EvaluationErrorKind
+	// Code of the error, for Ogmios v6
+	Code    int
+	Message string
+	// Failures of individual redeemers, sorted by purpose and index
+	Failures []RedeemerFailure
+	// Inputs that are unknown, as "<tx hash>#<index>"
+	Inputs []string
+	// Raw is the error as returned by Ogmios.
+	Raw json.RawMessage
+}
+
+func (e *EvaluationError) Error() string {
+	msg := "evaluation failed: " + string(e.Kind)
+	if len(e.Failures) > 0 {
+		parts := make([]string, len(e.Failures))
+		for i, f := range e.Failures {
+			parts[i] = fmt.Sprintf("%s:%d: %s", f.Purpose, f.Index, f.Kind)
+			if f.Message != "" {
+				parts[i] += ": " + f.Message
+			}
+		}
+		return msg + ": " + strings.Join(parts, "; ")
+	}
+	if len(e.Inputs) > 0 {
+		return msg + ": " + strings.Join(e.Inputs, ", ")
+	}
+	if e.Message != "" {
+		return msg + ": " + e.Message
+	}
+	return msg
+}
+
+// Evaluation decodes the response of TransactionEvaluate or
+// TransactionEvaluateUTXOs, from Ogmios v5 or v6. It returns the execution
+// budget of each redeemer, sorted by purpose and index, or an
+// *EvaluationError if the evaluation failed.
+func (r OgmiosResponse) Evaluation() ([]RedeemerEvaluation, error) {
+	switch {
+	case len(r.Error) > 0:
+		var oe ogmiosError
+		if err := json.Unmarshal(r.Error, &oe); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidEvaluation, err)
+		}
+		return nil, oe.evaluationError(r.Error)
+	case len(r.Fault) > 0:
+		var fault struct {
+			Code   string `json:"code"`
+			String string `json:"string"`
+		}
+		if err := json.Unmarshal(r.Fault, &fault); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidEvaluation, err)
+		}
+		return nil, &EvaluationError{Kind: EvaluationOther, Message: fault.String, Raw: r.Fault}
+	case r.JSONRPC != "" || bytes.HasPrefix(bytes.TrimSpace(r.Result), []byte("[")):
+		return evaluationV6(r.Result)
+	}
+	return evaluationV5(r.Result)
+}
+
+// validator is a redeemer pointer of Ogmios v6, either
+// {"purpose": "spend", "index": 0} or, in early versions, "spend:0".
+type validator struct {
+	Purpose string `json:"purpose"`
+	Index   int    `json:"index"`
+}
+
+func (v *validator) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v.Purpose, v.Index, err = parseRedeemerPointer(s)
+		return err
+	}
+	type plain validator
+	if err := json.Unmarshal(data, (*plain)(v)); err != nil {
+		return err
+	}
+	v.Purpose = normalizePurpose(v.Purpose)
+	return nil
+}
+
+// parseRedeemerPointer parses the "purpose:index" keys of Ogmios v5.
+func parseRedeemerPointer(s string) (string, int, error) {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return "", 0, fmt.Errorf("%w: redeemer pointer %q", ErrInvalidEvaluation, s)
+	}
+	index, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: redeemer pointer %q", ErrInvalidEvaluation, s)
+	}
+	return normalizePurpose(s[:i]), index, nil
+}
+
+func normalizePurpose(p string) string {
+	switch p {
+	case "certificate":
+		return PurposePublish
+	case "withdrawal":
+		return PurposeWithdraw
+	}
+	return p
+}
+
+func lessPointer(p1 string, i1 int, p2 string, i2 int) bool {
+	if p1 != p2 {
+		o1, ok1 := purposeOrder[p1]
+		o2, ok2 := purposeOrder[p2]
+		if ok1 && ok2 {
+			return o1 < o2
+		}
+		return p1 < p2
+	}
+	return i1 < i2
+}
+
+func sortEvaluations(evs []RedeemerEvaluation) {
+	sort.Slice(evs, func(i, j int) bool {
+		return lessPointer(evs[i].Purpose, evs[i].Index, evs[j].Purpose, evs[j].Index)
+	})
+}
+
+func sortFailures(e *EvaluationError) {
+	sort.SliceStable(e.Failures, func(i, j int) bool {
+		a, b := e.Failures[i], e.Failures[j]
+		if a.Purpose == b.Purpose && a.Index == b.Index {
+			return a.Message < b.Message
+		}
+		return lessPointer(a.Purpose, a.Index, b.Purpose, b.Index)
+	})
+	if len(e.Failures) > 0 {
+		e.Kind = e.Failures[0].Kind
+	}
+}
+
+// scriptFailureKind returns the kind of a script that failed with message.
+func scriptFailureKind(message string) EvaluationErrorKind {
+	m := strings.ToLower(message)
+	if strings.Contains(m, "overspending the budget") || strings.Contains(m, "out of budget") ||
+		(strings.Contains(m, "exceeded") && strings.Contains(m, "budget")) {
+		return EvaluationOverBudget
+	}
+	return EvaluationScriptFailure
+}
+
+func evaluationV6(result json.RawMessage) ([]RedeemerEvaluation, error) {
+	var res []struct {
+		Validator validator `json:"validator"`
+		Budget    struct {
+			Memory uint64 `json:"memory"`
+			CPU    uint64 `json:"cpu"`
+		} `json:"budget"`
+	}
+	if err := json.Unmarshal(result, &res); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvaluation, err)
+	}
+	evs := make([]RedeemerEvaluation, len(res))
+	for i, r := range res {
+		evs[i] = RedeemerEvaluation{
+			Purpose: r.Validator.Purpose,
+			Index:   r.Validator.Index,
+			Memory:  r.Budget.Memory,
+			Steps:   r.Budget.CPU,
+		}
+	}
+	sortEvaluations(evs)
+	return evs, nil
+}
+
+// ogmiosError is an error of Ogmios v6.
+type ogmiosError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// outputReference is an output reference of Ogmios v6.
+type outputReference struct {
+	Transaction struct {
+		ID string `json:"id"`
+	} `json:"transaction"`
+	Index int `json:"index"`
+}
+
+func (o outputReference) String() string {
+	return fmt.Sprintf("%s#%d", o.Transaction.ID, o.Index)
+}
+
+func (oe ogmiosError) unknownInputs() []string {
+	var data struct {
+		References []outputReference `json:"unknownOutputReferences"`
+	}
+	_ = json.Unmarshal(oe.Data, &data)
+	inputs := make([]string, len(data.References))
+	for i, ref := range data.References {
+		inputs[i] = ref.String()
+	}
+	return inputs
+}
+
+func (oe ogmiosError) evaluationError(raw json.RawMessage) error {
+	e := &EvaluationError{Kind: EvaluationOther, Code: oe.Code, Message: oe.Message, Raw: raw}
+	switch oe.Code {
+	case 3000, 3001:
+		e.Kind = EvaluationIncompatibleEra
+	case 3117:
+		e.Kind = EvaluationUnknownInputs
+		e.Inputs = oe.unknownInputs()
+	case 3010:
+		var failures []struct {
+			Validator validator   `json:"validator"`
+			Error     ogmiosError `json:"error"`
+		}
+		if err := json.Unmarshal(oe.Data, &failures); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvaluation, err)
+		}
+		for _, f := range failures {
+			e.Failures = append(e.Failures, f.Error.redeemerFailure(f.Validator))
+		}
+		sortFailures(e)
+	}
+	return e
+}
+
+func (oe ogmiosError) redeemerFailure(v validator) RedeemerFailure {
+	f := RedeemerFailure{Purpose: v.Purpose, Index: v.Index, Kind: EvaluationOther, Message: oe.Message}
+	switch oe.Code {
+	case 3012:
+		var data struct {
+			ValidationError string   `json:"validationError"`
+			Traces          []string `json:"traces"`
+		}
+		_ = json.Unmarshal(oe.Data, &data)
+		if data.ValidationError != "" {
+			f.Message = data.ValidationError
+		}
+		f.Traces = data.Traces
+		f.Kind = scriptFailureKind(f.Message)
+	case 3102:
+		var data struct {
+			MissingScripts []string `json:"missingScripts"`
+		}
+		_ = json.Unmarshal(oe.Data, &data)
+		f.Kind, f.Hashes = EvaluationMissingScripts, data.MissingScripts
+	case 3110:
+		f.Kind = EvaluationExtraRedeemers
+	case 3111:
+		var data struct {
+			MissingDatums []string `json:"missingDatums"`
+		}
+		_ = json.Unmarshal(oe.Data, &data)
+		f.Kind, f.Hashes = EvaluationMissingDatums, data.MissingDatums
+	case 3117:
+		f.Kind, f.Inputs = EvaluationUnknownInputs, oe.unknownInputs()
+	}
+	return f
+}
+
+func evaluationV5(result json.RawMessage) ([]RedeemerEvaluation, error) {
+	var res struct {
+		EvaluationResult map[string]struct {
+			Memory uint64 `json:"memory"`
+			Steps  uint64 `json:"steps"`
+		} `json:"EvaluationResult"`
+		EvaluationFailure json.RawMessage `json:"EvaluationFailure"`
+	}
+	if err := json.Unmarshal(result, &res); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvaluation, err)
+	}
+	if len(res.EvaluationFailure) > 0 {
+		return nil, evaluationFailureV5(res.EvaluationFailure)
+	}
+	if res.EvaluationResult == nil {
+		return nil, fmt.Errorf("%w: no evaluation result", ErrInvalidEvaluation)
+	}
+	evs := make([]RedeemerEvaluation, 0, len(res.EvaluationResult))
+	for ptr, budget := range res.EvaluationResult {
+		purpose, index, err := parseRedeemerPointer(ptr)
+		if err != nil {
+			return nil, err
+		}
+		evs = append(evs, RedeemerEvaluation{Purpose: purpose, Index: index, Memory: budget.Memory, Steps: budget.Steps})
+	}
+	sortEvaluations(evs)
+	return evs, nil
+}
+
+// txInV5 is an output reference of Ogmios v5.
+type txInV5 struct {
+	TxID  string `json:"txId"`
+	Index int    `json:"index"`
+}
+
+func (in txInV5) String() string {
+	return fmt.Sprintf("%s#%d", in.TxID, in.Index)
+}
+
+func evaluationFailureV5(raw json.RawMessage) error {
+	var failure map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &failure); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvaluation, err)
+	}
+	e := &EvaluationError{Kind: EvaluationOther, Raw: raw}
+	for name, data := range failure {
+		e.Message = name
+		switch name {
+		case "IncompatibleEra":
+			e.Kind = EvaluationIncompatibleEra
+		case "UnknownInputs":
+			var inputs []txInV5
+			_ = json.Unmarshal(data, &inputs)
+			e.Kind = EvaluationUnknownInputs
+			for _, in := range inputs {
+				e.Inputs = append(e.Inputs, in.String())
+			}
+		case "ScriptFailures":
+			var scripts map[string][]map[string]json.RawMessage
+			if err := json.Unmarshal(data, &scripts); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidEvaluation, err)
+			}
+			for ptr, errs := range scripts {
+				purpose, index, err := parseRedeemerPointer(ptr)
+				if err != nil {
+					return err
+				}
+				for _, fe := range errs {
+					for name, data := range fe {
+						e.Failures = append(e.Failures, redeemerFailureV5(purpose, index, name, data))
+					}
+				}
+			}
+			sortFailures(e)
+		}
+	}
+	return e
+}
+
+func redeemerFailureV5(purpose string, index int, name string, data json.RawMessage) RedeemerFailure {
+	f := RedeemerFailure{Purpose: purpose, Index: index, Kind: EvaluationOther, Message: name}
+	switch name {
+	case "validatorFailed":
+		var v struct {
+			Error  string   `json:"error"`
+			Traces []string `json:"traces"`
+		}
+		_ = json.Unmarshal(data, &v)
+		f.Message, f.Traces = v.Error, v.Traces
+		f.Kind = scriptFailureKind(v.Error)
+	case "missingRequiredDatums":
+		var v struct {
+			Missing []string `json:"missing"`
+		}
+		_ = json.Unmarshal(data, &v)
+		f.Kind, f.Hashes = EvaluationMissingDatums, v.Missing
+	case "missingRequiredScripts":
+		// Missing scripts are keyed by the redeemer requiring them.
+		var v struct {
+			Missing map[string]string `json:"missing"`
+		}
+		_ = json.Unmarshal(data, &v)
+		f.Kind = EvaluationMissingScripts
+		for _, h := range v.Missing {
+			f.Hashes = append(f.Hashes, h)
+		}
+		sort.Strings(f.Hashes)
+	case "extraRedeemers":
+		f.Kind = EvaluationExtraRedeemers
+	case "unknownInputReferencedByRedeemer":
+		var in txInV5
+		_ = json.Unmarshal(data, &in)
+		f.Kind, f.Inputs = EvaluationUnknownInputs, []string{in.String()}
+	}
+	return f
+}
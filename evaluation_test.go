@@ -0,0 +1,177 @@
+package blockfrost_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+func decodeOgmiosResponse(t *testing.T, data string) blockfrost.OgmiosResponse {
+	t.Helper()
+	r := blockfrost.OgmiosResponse{}
+	if err := json.Unmarshal([]byte(data), &r); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestEvaluation(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join(testdata, "transactionevaluateintegration.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		data string
+		want []blockfrost.RedeemerEvaluation
+	}{
+		{
+			"v5",
+			string(golden),
+			[]blockfrost.RedeemerEvaluation{{Purpose: "spend", Index: 0, Memory: 1765011, Steps: 384791603}},
+		},
+		{
+			"v5 purposes",
+			`{"type":"jsonwsp/response","result":{"EvaluationResult":{"withdrawal:0":{"memory":3,"steps":4},"mint:1":{"memory":1,"steps":2},"spend:10":{"memory":5,"steps":6},"spend:2":{"memory":7,"steps":8}}}}`,
+			[]blockfrost.RedeemerEvaluation{
+				{Purpose: "spend", Index: 2, Memory: 7, Steps: 8},
+				{Purpose: "spend", Index: 10, Memory: 5, Steps: 6},
+				{Purpose: "mint", Index: 1, Memory: 1, Steps: 2},
+				{Purpose: "withdraw", Index: 0, Memory: 3, Steps: 4},
+			},
+		},
+		{
+			"v6",
+			`{"jsonrpc":"2.0","method":"evaluateTransaction","result":[{"validator":{"purpose":"mint","index":0},"budget":{"memory":1,"cpu":2}},{"validator":{"purpose":"spend","index":1},"budget":{"memory":1765011,"cpu":384791603}}],"id":null}`,
+			[]blockfrost.RedeemerEvaluation{
+				{Purpose: "spend", Index: 1, Memory: 1765011, Steps: 384791603},
+				{Purpose: "mint", Index: 0, Memory: 1, Steps: 2},
+			},
+		},
+		{
+			"v6 string validator",
+			`{"jsonrpc":"2.0","result":[{"validator":"spend:0","budget":{"memory":10,"cpu":20}}]}`,
+			[]blockfrost.RedeemerEvaluation{{Purpose: "spend", Index: 0, Memory: 10, Steps: 20}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := decodeOgmiosResponse(t, test.data).Evaluation()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluationError(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want blockfrost.EvaluationError
+	}{
+		{
+			"v5 script failures",
+			`{"type":"jsonwsp/response","result":{"EvaluationFailure":{"ScriptFailures":{
+				"spend:1":[{"validatorFailed":{"error":"An error has occurred: The machine terminated part way through evaluation due to overspending the budget.","traces":[]}}],
+				"spend:0":[{"validatorFailed":{"error":"An error has occurred: User error","traces":["deadline passed"]}}],
+				"certificate:0":[{"missingRequiredDatums":{"provided":[],"missing":["aa"]}}]}}}}`,
+			blockfrost.EvaluationError{
+				Kind:    blockfrost.EvaluationScriptFailure,
+				Message: "ScriptFailures",
+				Failures: []blockfrost.RedeemerFailure{
+					{Purpose: "spend", Index: 0, Kind: blockfrost.EvaluationScriptFailure, Message: "An error has occurred: User error", Traces: []string{"deadline passed"}},
+					{Purpose: "spend", Index: 1, Kind: blockfrost.EvaluationOverBudget, Message: "An error has occurred: The machine terminated part way through evaluation due to overspending the budget.", Traces: []string{}},
+					{Purpose: "publish", Index: 0, Kind: blockfrost.EvaluationMissingDatums, Message: "missingRequiredDatums", Hashes: []string{"aa"}},
+				},
+			},
+		},
+		{
+			"v5 unknown inputs",
+			`{"type":"jsonwsp/response","result":{"EvaluationFailure":{"UnknownInputs":[{"txId":"ec6e","index":1}]}}}`,
+			blockfrost.EvaluationError{Kind: blockfrost.EvaluationUnknownInputs, Message: "UnknownInputs", Inputs: []string{"ec6e#1"}},
+		},
+		{
+			"v5 fault",
+			`{"type":"jsonwsp/fault","fault":{"code":"client","string":"Invalid request: failed to decode payload"}}`,
+			blockfrost.EvaluationError{Kind: blockfrost.EvaluationOther, Message: "Invalid request: failed to decode payload"},
+		},
+		{
+			"v6 script execution failure",
+			`{"jsonrpc":"2.0","method":"evaluateTransaction","error":{"code":3010,"message":"Some scripts of the transactions terminated with error(s).","data":[
+				{"validator":{"purpose":"mint","index":0},"error":{"code":3111,"message":"Missing datums","data":{"missingDatums":["bb"]}}},
+				{"validator":{"purpose":"spend","index":0},"error":{"code":3012,"message":"Some of the scripts failed to evaluate to a positive outcome.","data":{"validationError":"An error has occurred: User error","traces":["Lc"]}}}]},"id":null}`,
+			blockfrost.EvaluationError{
+				Kind:    blockfrost.EvaluationScriptFailure,
+				Code:    3010,
+				Message: "Some scripts of the transactions terminated with error(s).",
+				Failures: []blockfrost.RedeemerFailure{
+					{Purpose: "spend", Index: 0, Kind: blockfrost.EvaluationScriptFailure, Message: "An error has occurred: User error", Traces: []string{"Lc"}},
+					{Purpose: "mint", Index: 0, Kind: blockfrost.EvaluationMissingDatums, Message: "Missing datums", Hashes: []string{"bb"}},
+				},
+			},
+		},
+		{
+			"v6 unknown inputs",
+			`{"jsonrpc":"2.0","method":"evaluateTransaction","error":{"code":3117,"message":"Unknown output references","data":{"unknownOutputReferences":[{"transaction":{"id":"ec6e"},"index":0}]}},"id":null}`,
+			blockfrost.EvaluationError{Kind: blockfrost.EvaluationUnknownInputs, Code: 3117, Message: "Unknown output references", Inputs: []string{"ec6e#0"}},
+		},
+		{
+			"v6 incompatible era",
+			`{"jsonrpc":"2.0","method":"evaluateTransaction","error":{"code":3000,"message":"Incompatible era","data":{"incompatibleEra":"mary"}},"id":null}`,
+			blockfrost.EvaluationError{Kind: blockfrost.EvaluationIncompatibleEra, Code: 3000, Message: "Incompatible era"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodeOgmiosResponse(t, test.data).Evaluation()
+			var got *blockfrost.EvaluationError
+			if !errors.As(err, &got) {
+				t.Fatalf("got %v, want an EvaluationError", err)
+			}
+			if len(got.Raw) == 0 {
+				t.Fatal("raw error is missing")
+			}
+			got.Raw = nil
+			if !reflect.DeepEqual(*got, test.want) {
+				t.Fatalf("got %+v, want %+v", *got, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluationErrorMessage(t *testing.T) {
+	err := &blockfrost.EvaluationError{
+		Kind: blockfrost.EvaluationScriptFailure,
+		Failures: []blockfrost.RedeemerFailure{
+			{Purpose: "spend", Index: 0, Kind: blockfrost.EvaluationScriptFailure, Message: "User error"},
+			{Purpose: "mint", Index: 1, Kind: blockfrost.EvaluationOverBudget},
+		},
+	}
+	want := "evaluation failed: script failure: spend:0: script failure: User error; mint:1: over budget"
+	if got := err.Error(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestEvaluationInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"result":{"SomethingElse":{}}}`,
+		`{"result":{"EvaluationResult":{"spend":{"memory":1,"steps":2}}}}`,
+		`{"jsonrpc":"2.0","result":{"budget":1}}`,
+	} {
+		_, err := decodeOgmiosResponse(t, data).Evaluation()
+		if !errors.Is(err, blockfrost.ErrInvalidEvaluation) {
+			t.Fatalf("%s: got %v, want ErrInvalidEvaluation", strings.TrimSpace(data), err)
+		}
+	}
+}
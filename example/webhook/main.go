@@ -4,9 +4,9 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 
@@ -16,71 +16,31 @@ import (
 const SECRET_AUTH_TOKEN string = "dc8f4b23-6c44-405f-8940-5d451222458e"
 
 func main() {
-	http.HandleFunc("/webhook", func(w http.ResponseWriter, req *http.Request) {
-		const MaxBodyBytes = int64(524288)
-		req.Body = http.MaxBytesReader(w, req.Body, MaxBodyBytes)
-		payload, err := io.ReadAll(req.Body)
-
-		fmt.Printf("Received webhook request.\n")
-
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading request body: %v\n", err)
-			w.WriteHeader(http.StatusServiceUnavailable)
-			return
-		}
-
-		event, err := blockfrost.VerifyWebhookSignature(payload, req.Header.Get("Blockfrost-Signature"), SECRET_AUTH_TOKEN)
-
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error verifying webhook signature: %v\n", err)
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
-
-		// Unmarshal the event data into an appropriate struct depending on its Type
-		switch event.Type {
-		case "block":
-			var blockEvent blockfrost.WebhookEventBlock
-			err := json.Unmarshal(payload, &blockEvent)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing webhook JSON: %v\n", err)
-				w.WriteHeader(http.StatusBadRequest)
-				return
-			}
-			fmt.Printf("Received block event: %v\n", blockEvent)
-		case "transaction":
-			var transactionEvent blockfrost.WebhookEventTransaction
-			err := json.Unmarshal(payload, &transactionEvent)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing webhook JSON: %v\n", err)
-				w.WriteHeader(http.StatusBadRequest)
-				return
-			}
-			fmt.Printf("Received tx event: %v\n", transactionEvent)
-		case "epoch":
-			var epochEvent blockfrost.WebhookEventEpoch
-			err := json.Unmarshal(payload, &epochEvent)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing webhook JSON: %v\n", err)
-				w.WriteHeader(http.StatusBadRequest)
-				return
-			}
-			fmt.Printf("Received epoch event: %v\n", epochEvent)
-		case "delegation":
-			var delegationEvent blockfrost.WebhookEventDelegation
-			err := json.Unmarshal(payload, &delegationEvent)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing webhook JSON: %v\n", err)
-				w.WriteHeader(http.StatusBadRequest)
-				return
-			}
-			fmt.Printf("Received delegation event: %v\n", delegationEvent)
-		default:
+	http.Handle("/webhook", blockfrost.NewWebhookHandler(SECRET_AUTH_TOKEN, blockfrost.WebhookHandlerOptions{
+		OnBlock: func(ctx context.Context, event blockfrost.WebhookEventBlock) error {
+			fmt.Printf("Received block event: %v\n", event)
+			return nil
+		},
+		OnTransaction: func(ctx context.Context, event blockfrost.WebhookEventTransaction) error {
+			fmt.Printf("Received tx event: %v\n", event)
+			return nil
+		},
+		OnEpoch: func(ctx context.Context, event blockfrost.WebhookEventEpoch) error {
+			fmt.Printf("Received epoch event: %v\n", event)
+			return nil
+		},
+		OnDelegation: func(ctx context.Context, event blockfrost.WebhookEventDelegation) error {
+			fmt.Printf("Received delegation event: %v\n", event)
+			return nil
+		},
+		OnUnknown: func(ctx context.Context, event blockfrost.WebhookEvent, payload json.RawMessage) error {
 			fmt.Fprintf(os.Stderr, "Unhandled webhook type: %s\n", event.Type)
-		}
-
-		w.WriteHeader(http.StatusOK)
-	})
+			return nil
+		},
+		OnError: func(err error) {
+			fmt.Fprintf(os.Stderr, "Error handling webhook request: %v\n", err)
+		},
+	}))
 
 	fmt.Println("Server is starting on port 8080...")
 
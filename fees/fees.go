@@ -0,0 +1,280 @@
+// Package fees computes transaction fees, minimum output values, deposits
+// and collateral from protocol parameters, following the ledger rules of the
+// Babbage and Conway eras.
+//
+// Computations use exact rationals, so that results match the ones of the
+// ledger to the lovelace. Prices are read from the exact values decoded
+// from the API, never from the float fields of EpochParameters.
+package fees
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+// ErrInvalidParameters is returned when protocol parameters cannot be
+// converted.
+var ErrInvalidParameters = errors.New("fees: invalid protocol parameters")
+
+const (
+	// utxoEntryOverhead is the size in bytes accounted for each output in
+	// addition to its serialized size.
+	utxoEntryOverhead = 160
+
+	// refScriptSizeIncrement is the size of each tier of the reference
+	// scripts fee.
+	refScriptSizeIncrement = 25600
+)
+
+// refScriptMultiplier is the price increase of each tier of the reference
+// scripts fee.
+var refScriptMultiplier = big.NewRat(6, 5)
+
+// Params are the protocol parameters used to compute fees and deposits.
+type Params struct {
+	// MinFeeA is the fee per byte of transaction.
+	MinFeeA uint64
+	// MinFeeB is the constant fee of a transaction.
+	MinFeeB uint64
+
+	// PriceMem is the price of a unit of script memory.
+	PriceMem *big.Rat
+	// PriceStep is the price of a script execution step.
+	PriceStep *big.Rat
+
+	// MinFeeRefScriptCostPerByte is the price of a byte of reference
+	// scripts in the first tier.
+	MinFeeRefScriptCostPerByte *big.Rat
+
+	// CoinsPerUTxOByte is the price of a byte of output.
+	CoinsPerUTxOByte uint64
+
+	KeyDeposit       uint64
+	PoolDeposit      uint64
+	DRepDeposit      uint64
+	GovActionDeposit uint64
+
+	// CollateralPercent is the collateral required by transactions running
+	// scripts, as a percentage of their fee.
+	CollateralPercent uint64
+
+	// MaxTxSize is the maximum size of a transaction in bytes.
+	MaxTxSize uint64
+}
+
+// FromEpochParameters converts protocol parameters returned by
+// EpochParameters or LatestEpochParameters. Parameters that are not set in
+// the epoch, such as the Conway ones in earlier epochs, are zero.
+//
+// Prices are read from ep.Rational, which holds their exact values when ep
+// was decoded from JSON. Parameters built in code must set the prices in
+// ep.Rational too: a price only set in its float field is rejected with
+// ErrInvalidParameters, as the float may not hold its exact value.
+func FromEpochParameters(ep blockfrost.EpochParameters) (*Params, error) {
+	p := &Params{
+		PriceMem:                   new(big.Rat),
+		PriceStep:                  new(big.Rat),
+		MinFeeRefScriptCostPerByte: new(big.Rat),
+	}
+	var err error
+	if ep.MinFeeA < 0 || ep.MinFeeB < 0 || ep.MaxTxSize < 0 {
+		return nil, fmt.Errorf("%w: negative fee parameters", ErrInvalidParameters)
+	}
+	p.MinFeeA = uint64(ep.MinFeeA)
+	p.MinFeeB = uint64(ep.MinFeeB)
+	p.MaxTxSize = uint64(ep.MaxTxSize)
+	prices := []struct {
+		name  string
+		exact *blockfrost.Rational
+		set   bool
+		dst   **big.Rat
+	}{
+		{"price_mem", ep.Rational.PriceMem, ep.PriceMem != nil, &p.PriceMem},
+		{"price_step", ep.Rational.PriceStep, ep.PriceStep != nil, &p.PriceStep},
+		{"min_fee_ref_script_cost_per_byte", ep.Rational.MinFeeRefScriptCostPerByte, ep.MinFeeRefScriptCostPerByte != nil, &p.MinFeeRefScriptCostPerByte},
+	}
+	for _, price := range prices {
+		switch {
+		case price.exact != nil:
+			*price.dst = price.exact.Rat()
+		case price.set:
+			return nil, fmt.Errorf("%w: %s has no exact value in Rational", ErrInvalidParameters, price.name)
+		}
+	}
+	for _, r := range []*big.Rat{p.PriceMem, p.PriceStep, p.MinFeeRefScriptCostPerByte} {
+		if r.Sign() < 0 {
+			return nil, fmt.Errorf("%w: negative price %s", ErrInvalidParameters, r.RatString())
+		}
+	}
+	if ep.CollateralPercent != nil {
+		if *ep.CollateralPercent < 0 {
+			return nil, fmt.Errorf("%w: negative collateral percent", ErrInvalidParameters)
+		}
+		p.CollateralPercent = uint64(*ep.CollateralPercent)
+	}
+	for _, f := range []struct {
+		dst   *uint64
+		value *string
+		name  string
+	}{
+		{&p.CoinsPerUTxOByte, ep.CoinsPerUTxOSize, "coins_per_utxo_size"},
+		{&p.KeyDeposit, &ep.KeyDeposit, "key_deposit"},
+		{&p.PoolDeposit, &ep.PoolDeposit, "pool_deposit"},
+		{&p.DRepDeposit, ep.DrepDeposit, "drep_deposit"},
+		{&p.GovActionDeposit, ep.GovActionDeposit, "gov_action_deposit"},
+	} {
+		if f.value == nil || *f.value == "" {
+			continue
+		}
+		if *f.dst, err = strconv.ParseUint(*f.value, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidParameters, f.name, err)
+		}
+	}
+	return p, nil
+}
+
+func ratOrZero(r *big.Rat) *big.Rat {
+	if r == nil {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// floor returns the largest integer lower than or equal to r, which must
+// not be negative.
+func floor(r *big.Rat) uint64 {
+	return new(big.Int).Quo(r.Num(), r.Denom()).Uint64()
+}
+
+// ceil returns the smallest integer greater than or equal to r, which must
+// not be negative.
+func ceil(r *big.Rat) uint64 {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q.Uint64()
+}
+
+// LinearFee returns the size dependent fee of a transaction of size bytes.
+func (p *Params) LinearFee(size int) uint64 {
+	return p.MinFeeA*uint64(size) + p.MinFeeB
+}
+
+// ScriptFee returns the fee paid for the execution units of the scripts of
+// a transaction.
+func (p *Params) ScriptFee(units ledger.ExUnits) uint64 {
+	fee := new(big.Rat).Mul(ratOrZero(p.PriceMem), new(big.Rat).SetInt(new(big.Int).SetUint64(units.Memory)))
+	steps := new(big.Rat).Mul(ratOrZero(p.PriceStep), new(big.Rat).SetInt(new(big.Int).SetUint64(units.Steps)))
+	return ceil(fee.Add(fee, steps))
+}
+
+// TotalExUnits returns the sum of the execution units of redeemers.
+func TotalExUnits(redeemers []ledger.Redeemer) ledger.ExUnits {
+	var total ledger.ExUnits
+	for _, r := range redeemers {
+		total.Memory += r.ExUnits.Memory
+		total.Steps += r.ExUnits.Steps
+	}
+	return total
+}
+
+// ReferenceScriptFee returns the fee paid for size bytes of reference
+// scripts, spent or referenced by a transaction. The price per byte is
+// multiplied by 1.2 for every 25 KiB.
+func (p *Params) ReferenceScriptFee(size int) uint64 {
+	acc := new(big.Rat)
+	price := new(big.Rat).Set(ratOrZero(p.MinFeeRefScriptCostPerByte))
+	n := size
+	for n >= refScriptSizeIncrement {
+		acc.Add(acc, new(big.Rat).Mul(price, big.NewRat(refScriptSizeIncrement, 1)))
+		price.Mul(price, refScriptMultiplier)
+		n -= refScriptSizeIncrement
+	}
+	acc.Add(acc, new(big.Rat).Mul(price, big.NewRat(int64(n), 1)))
+	return floor(acc)
+}
+
+// ReferenceScriptsSize returns the size accounted for scripts in the
+// reference scripts fee.
+func ReferenceScriptsSize(scripts ...ledger.Script) int {
+	size := 0
+	for _, s := range scripts {
+		size += len(s.Bytes)
+	}
+	return size
+}
+
+// MinFee returns the minimum fee of tx, which spends or references
+// refScriptsSize bytes of reference scripts. The fee depends on the size of
+// tx, so tx must hold all of its witnesses, or placeholders of the same
+// size, and a fee of the same encoded size as the final one.
+func (p *Params) MinFee(tx *ledger.Transaction, refScriptsSize int) (uint64, error) {
+	b, err := tx.MarshalCBOR()
+	if err != nil {
+		return 0, err
+	}
+	fee := p.LinearFee(len(b))
+	fee += p.ScriptFee(TotalExUnits(tx.WitnessSet.Redeemers))
+	fee += p.ReferenceScriptFee(refScriptsSize)
+	return fee, nil
+}
+
+// MinLovelace returns the minimum amount of lovelace out must hold, given
+// the size of its encoding once holding that amount.
+func (p *Params) MinLovelace(out ledger.TransactionOutput) (uint64, error) {
+	var coin uint64
+	for {
+		out.Amount.Coin = coin
+		b, err := out.MarshalCBOR()
+		if err != nil {
+			return 0, err
+		}
+		need := (utxoEntryOverhead + uint64(len(b))) * p.CoinsPerUTxOByte
+		if need <= coin {
+			return coin, nil
+		}
+		coin = need
+	}
+}
+
+// Collateral returns the collateral required by a transaction running
+// scripts and paying fee.
+func (p *Params) Collateral(fee uint64) uint64 {
+	return ceil(new(big.Rat).SetFrac(
+		new(big.Int).Mul(new(big.Int).SetUint64(fee), new(big.Int).SetUint64(p.CollateralPercent)),
+		big.NewInt(100),
+	))
+}
+
+// Deposits returns the deposits paid and refunded by the certificates and
+// proposals of body. Stake key deregistrations without an explicit amount
+// are refunded KeyDeposit, and pool registrations are assumed to register
+// new pools.
+func (p *Params) Deposits(body *ledger.TransactionBody) (deposit, refund uint64) {
+	for _, c := range body.Certificates {
+		switch c.Type {
+		case ledger.CertStakeRegistration:
+			deposit += p.KeyDeposit
+		case ledger.CertRegistration, ledger.CertStakeRegistrationDelegation,
+			ledger.CertVoteRegistrationDelegation, ledger.CertStakeVoteRegistrationDelegation,
+			ledger.CertDRepRegistration:
+			deposit += c.Deposit
+		case ledger.CertPoolRegistration:
+			deposit += p.PoolDeposit
+		case ledger.CertStakeDeregistration:
+			refund += p.KeyDeposit
+		case ledger.CertUnregistration, ledger.CertDRepUnregistration:
+			refund += c.Deposit
+		}
+	}
+	for _, pp := range body.ProposalProcedures {
+		deposit += pp.Deposit
+	}
+	return deposit, refund
+}
@@ -0,0 +1,240 @@
+package fees_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/fees"
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+const testdata = "../testdata"
+
+func loadParams(t *testing.T) *fees.Params {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(testdata, "epochparametersintegration.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ep := blockfrost.EpochParameters{}
+	if err := json.Unmarshal(data, &ep); err != nil {
+		t.Fatal(err)
+	}
+	p, err := fees.FromEpochParameters(ep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestFromEpochParameters(t *testing.T) {
+	p := loadParams(t)
+	if p.MinFeeA != 44 || p.MinFeeB != 155381 || p.CoinsPerUTxOByte != 4310 || p.KeyDeposit != 2000000 ||
+		p.PoolDeposit != 500000000 || p.CollateralPercent != 150 || p.MaxTxSize != 16384 {
+		t.Fatalf("unexpected params %+v", p)
+	}
+	if p.PriceMem.Cmp(big.NewRat(577, 10000)) != 0 {
+		t.Fatalf("got price_mem %s", p.PriceMem)
+	}
+	if p.PriceStep.Cmp(big.NewRat(721, 10000000)) != 0 {
+		t.Fatalf("got price_step %s", p.PriceStep)
+	}
+	if p.MinFeeRefScriptCostPerByte.Sign() != 0 || p.DRepDeposit != 0 {
+		t.Fatal("expected Conway parameters to be zero")
+	}
+
+	// Prices beyond the precision of the float fields are read exactly.
+	var ep blockfrost.EpochParameters
+	if err := json.Unmarshal([]byte(`{"key_deposit":"2000000","price_step":0.000072100001,"min_fee_ref_script_cost_per_byte":15,"drep_deposit":"500000000"}`), &ep); err != nil {
+		t.Fatal(err)
+	}
+	p, err := fees.FromEpochParameters(ep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PriceStep.Cmp(big.NewRat(72100001, 1e12)) != 0 || p.MinFeeRefScriptCostPerByte.Cmp(big.NewRat(15, 1)) != 0 || p.DRepDeposit != 500000000 {
+		t.Fatalf("unexpected params %+v", p)
+	}
+
+	// Prices built in code must be exact.
+	ref := 15.0
+	if _, err := fees.FromEpochParameters(blockfrost.EpochParameters{MinFeeRefScriptCostPerByte: &ref}); !errors.Is(err, fees.ErrInvalidParameters) {
+		t.Fatalf("got %v, want ErrInvalidParameters", err)
+	}
+	exact := blockfrost.NewRational(15, 1)
+	ep = blockfrost.EpochParameters{MinFeeRefScriptCostPerByte: &ref}
+	ep.Rational.MinFeeRefScriptCostPerByte = &exact
+	if p, err = fees.FromEpochParameters(ep); err != nil || p.MinFeeRefScriptCostPerByte.Cmp(big.NewRat(15, 1)) != 0 {
+		t.Fatalf("got %+v, %v", p, err)
+	}
+
+	if _, err := fees.FromEpochParameters(blockfrost.EpochParameters{KeyDeposit: "-1"}); !errors.Is(err, fees.ErrInvalidParameters) {
+		t.Fatalf("got %v, want ErrInvalidParameters", err)
+	}
+}
+
+func TestScriptFee(t *testing.T) {
+	p := loadParams(t)
+	tests := []struct {
+		units ledger.ExUnits
+		want  uint64
+	}{
+		{ledger.ExUnits{}, 0},
+		{ledger.ExUnits{Memory: 1, Steps: 1}, 1},
+		{ledger.ExUnits{Memory: 1000000, Steps: 500000000}, 93750},
+		{ledger.ExUnits{Memory: 14000000, Steps: 10000000000}, 1528800},
+	}
+	for _, test := range tests {
+		if got := p.ScriptFee(test.units); got != test.want {
+			t.Errorf("%+v: got %d, want %d", test.units, got, test.want)
+		}
+	}
+
+	total := fees.TotalExUnits([]ledger.Redeemer{
+		{ExUnits: ledger.ExUnits{Memory: 1, Steps: 2}},
+		{ExUnits: ledger.ExUnits{Memory: 3, Steps: 4}},
+	})
+	if total != (ledger.ExUnits{Memory: 4, Steps: 6}) {
+		t.Fatalf("got %+v", total)
+	}
+}
+
+func TestReferenceScriptFee(t *testing.T) {
+	tests := []struct {
+		price *big.Rat
+		size  int
+		want  uint64
+	}{
+		{big.NewRat(15, 1), 0, 0},
+		{big.NewRat(15, 1), 1000, 15000},
+		{big.NewRat(15, 1), 25600, 384000},
+		{big.NewRat(15, 1), 30000, 463200},
+		{big.NewRat(15, 1), 51300, 846960},
+		{big.NewRat(44, 1), 25601, 1126452},
+	}
+	for _, test := range tests {
+		p := &fees.Params{MinFeeRefScriptCostPerByte: test.price}
+		if got := p.ReferenceScriptFee(test.size); got != test.want {
+			t.Errorf("%s x %d: got %d, want %d", test.price, test.size, got, test.want)
+		}
+	}
+
+	if got := fees.ReferenceScriptsSize(ledger.Script{Bytes: make([]byte, 10)}, ledger.Script{Bytes: make([]byte, 5)}); got != 15 {
+		t.Fatalf("got %d", got)
+	}
+}
+
+func TestMinFee(t *testing.T) {
+	p := loadParams(t)
+	for _, name := range []string{"transaction_cbor.json", "tx_cbor_conway.json"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(testdata, "json", "transactions", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc := blockfrost.TransactionCBOR{}
+			if err := json.Unmarshal(data, &tc); err != nil {
+				t.Fatal(err)
+			}
+			tx, err := tc.Decode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.MinFee(tx, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := tx.MarshalCBOR()
+			want := 44*uint64(len(b)) + 155381 + p.ScriptFee(fees.TotalExUnits(tx.WitnessSet.Redeemers))
+			if got != want {
+				t.Fatalf("got %d, want %d", got, want)
+			}
+			withRef, _ := p.MinFee(tx, 1000)
+			if withRef != got {
+				t.Fatalf("got %d, expected no reference script fee before Conway", withRef)
+			}
+		})
+	}
+}
+
+func TestMinLovelace(t *testing.T) {
+	p := loadParams(t)
+	base := append([]byte{0x01}, bytes.Repeat([]byte{0xaa}, 56)...)
+	enterprise := append([]byte{0x61}, bytes.Repeat([]byte{0xaa}, 28)...)
+
+	tests := []struct {
+		name string
+		out  ledger.TransactionOutput
+		want uint64
+	}{
+		{"base address", ledger.TransactionOutput{Address: base}, 978370},
+		{"enterprise address", ledger.TransactionOutput{Address: enterprise}, 857690},
+		{"ignores current amount", ledger.TransactionOutput{Address: base, Amount: ledger.Value{Coin: 1 << 40}}, 978370},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := p.MinLovelace(test.out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("got %d, want %d", got, test.want)
+			}
+		})
+	}
+
+	// Assets increase the size of the output and its minimum amount.
+	out := ledger.TransactionOutput{Address: base, Amount: ledger.Value{Assets: ledger.MultiAsset{
+		ledger.Hash28{1}: {"token": 1},
+	}}}
+	got, err := p.MinLovelace(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got <= 978370 {
+		t.Fatalf("got %d, expected more than an ada only output", got)
+	}
+	out.Amount.Coin = got
+	b, _ := out.MarshalCBOR()
+	if need := (160 + uint64(len(b))) * 4310; need != got {
+		t.Fatalf("got %d, want %d", got, need)
+	}
+}
+
+func TestCollateral(t *testing.T) {
+	p := loadParams(t)
+	for fee, want := range map[uint64]uint64{0: 0, 200000: 300000, 200001: 300002} {
+		if got := p.Collateral(fee); got != want {
+			t.Errorf("%d: got %d, want %d", fee, got, want)
+		}
+	}
+}
+
+func TestDeposits(t *testing.T) {
+	p := &fees.Params{KeyDeposit: 2000000, PoolDeposit: 500000000}
+	body := &ledger.TransactionBody{
+		Certificates: []ledger.Certificate{
+			{Type: ledger.CertStakeRegistration},
+			{Type: ledger.CertRegistration, Deposit: 2000000},
+			{Type: ledger.CertDRepRegistration, Deposit: 500000000},
+			{Type: ledger.CertPoolRegistration},
+			{Type: ledger.CertStakeDelegation},
+			{Type: ledger.CertStakeDeregistration},
+			{Type: ledger.CertDRepUnregistration, Deposit: 500000000},
+		},
+		ProposalProcedures: []ledger.ProposalProcedure{{Deposit: 100000000000}},
+	}
+	deposit, refund := p.Deposits(body)
+	if deposit != 2000000+2000000+500000000+500000000+100000000000 {
+		t.Fatalf("got deposit %d", deposit)
+	}
+	if refund != 2000000+500000000 {
+		t.Fatalf("got refund %d", refund)
+	}
+}
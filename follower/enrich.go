@@ -0,0 +1,331 @@
+package follower
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/internal/apierr"
+)
+
+// ErrIncompleteBlock is returned with blocks some transactions of which
+// could not be fetched.
+var ErrIncompleteBlock = errors.New("follower: incomplete block")
+
+// Resources selects the resources fetched for each transaction, in
+// addition to its content.
+type Resources uint
+
+const (
+	ResourceUTXOs Resources = 1 << iota
+	ResourceMetadata
+	ResourceRedeemers
+	ResourceCertificates
+	ResourceWithdrawals
+
+	// ResourceAll selects all resources.
+	ResourceAll = ResourceUTXOs | ResourceMetadata | ResourceRedeemers | ResourceCertificates | ResourceWithdrawals
+)
+
+// FullBlock is a block with the transactions it includes.
+type FullBlock struct {
+	Block blockfrost.Block
+
+	// Transactions in block order
+	Transactions []FullTransaction
+}
+
+// FullTransaction is a transaction with its resources. Resources the
+// transaction does not have, according to the counts of its content, are
+// not fetched and left nil.
+type FullTransaction struct {
+	Hash    string
+	Content blockfrost.TransactionContent
+
+	UTXOs     blockfrost.TransactionUTXOs
+	Metadata  []blockfrost.TransactionMetadata
+	Redeemers []blockfrost.TransactionRedeemer
+
+	StakeCerts      []blockfrost.TransactionStakeAddressCert
+	Delegations     []blockfrost.TransactionDelegation
+	PoolUpdates     []blockfrost.TransactionPoolCert
+	PoolRetirements []blockfrost.TransactionPoolRetires
+	MIRs            []blockfrost.TransactionMIR
+	Withdrawals     []blockfrost.TransactionWidthrawal
+
+	// Err is the error that prevented fetching the transaction, whose
+	// fields are then incomplete.
+	Err error
+}
+
+// EnrichOptions configures an Enricher.
+type EnrichOptions struct {
+	// Resources fetched for each transaction. Default: ResourceAll.
+	Resources Resources
+
+	// Concurrency is the maximum number of transactions fetched
+	// concurrently. Default: 10.
+	Concurrency int
+
+	// CacheSize is the number of transactions kept to be reused, such as
+	// when a block is enriched again after a failure. Default: 10000.
+	// Negative values disable the cache.
+	CacheSize int
+
+	// RetryInterval is the interval between attempts to enrich a block by
+	// the Handler of an Enricher. Default: 10s.
+	RetryInterval time.Duration
+
+	// OnError is called by the Handler of an Enricher with the errors of
+	// incomplete blocks, before they are retried.
+	OnError func(error)
+}
+
+// Enricher fetches the transactions of blocks.
+type Enricher struct {
+	client blockfrost.APIClient
+	opts   EnrichOptions
+	cache  *txCache
+}
+
+// NewEnricher returns an Enricher fetching from client.
+func NewEnricher(client blockfrost.APIClient, opts EnrichOptions) *Enricher {
+	if opts.Resources == 0 {
+		opts.Resources = ResourceAll
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 10
+	}
+	if opts.CacheSize == 0 {
+		opts.CacheSize = 10000
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = 10 * time.Second
+	}
+	return &Enricher{client: client, opts: opts, cache: newTxCache(opts.CacheSize)}
+}
+
+// Enrich fetches the transactions of b. If some of them cannot be fetched,
+// it returns the partial block along with an error wrapping
+// ErrIncompleteBlock, and the failed transactions have their Err set. Once
+// a request is rate limited, no further request is made for the block.
+func (e *Enricher) Enrich(ctx context.Context, b blockfrost.Block) (*FullBlock, error) {
+	fb := &FullBlock{Block: b}
+	if b.TxCount == 0 {
+		return fb, nil
+	}
+	hashes, err := e.transactions(ctx, b.Hash)
+	if err != nil {
+		return fb, fmt.Errorf("%w: %s: %v", ErrIncompleteBlock, b.Hash, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	fb.Transactions = make([]FullTransaction, len(hashes))
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+		first  error
+	)
+	sem := make(chan struct{}, e.opts.Concurrency)
+	for i, hash := range hashes {
+		fb.Transactions[i].Hash = hash
+		if tx, ok := e.cache.get(hash, b.Hash); ok {
+			fb.Transactions[i] = tx
+			continue
+		}
+		wg.Add(1)
+		go func(tx *FullTransaction) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+				tx.Err = e.fetch(ctx, tx)
+			case <-ctx.Done():
+				tx.Err = ctx.Err()
+			}
+			if tx.Err == nil {
+				e.cache.put(*tx)
+				return
+			}
+			if apierr.IsRateLimited(tx.Err) {
+				cancel()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if failed++; first == nil || errors.Is(first, context.Canceled) {
+				first = tx.Err
+			}
+		}(&fb.Transactions[i])
+	}
+	wg.Wait()
+	if failed > 0 {
+		return fb, fmt.Errorf("%w: %s: %d of %d transactions failed: %v", ErrIncompleteBlock, b.Hash, failed, len(hashes), first)
+	}
+	return fb, nil
+}
+
+// transactions returns the hashes of the transactions of the block, in
+// block order.
+func (e *Enricher) transactions(ctx context.Context, block string) ([]string, error) {
+	const count = 100
+	var hashes []string
+	for page := 1; ; page++ {
+		txs, err := e.client.BlockTransactions(ctx, block, blockfrost.APIQueryParams{Count: count, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range txs {
+			hashes = append(hashes, string(tx))
+		}
+		if len(txs) < count {
+			return hashes, nil
+		}
+	}
+}
+
+// fetch fetches the content and resources of tx.
+func (e *Enricher) fetch(ctx context.Context, tx *FullTransaction) (err error) {
+	c, hash, res := e.client, tx.Hash, e.opts.Resources
+	if tx.Content, err = c.Transaction(ctx, hash); err != nil {
+		return err
+	}
+	content := tx.Content
+	steps := []struct {
+		want  bool
+		fetch func() error
+	}{
+		{res&ResourceUTXOs != 0, func() (err error) {
+			tx.UTXOs, err = c.TransactionUTXOs(ctx, hash)
+			return
+		}},
+		{res&ResourceMetadata != 0, func() (err error) {
+			tx.Metadata, err = c.TransactionMetadata(ctx, hash)
+			return
+		}},
+		{res&ResourceRedeemers != 0 && content.RedeemerCount > 0, func() (err error) {
+			tx.Redeemers, err = c.TransactionRedeemers(ctx, hash)
+			return
+		}},
+		{res&ResourceCertificates != 0 && content.StakeCertCount > 0, func() (err error) {
+			tx.StakeCerts, err = c.TransactionStakeAddressCerts(ctx, hash)
+			return
+		}},
+		{res&ResourceCertificates != 0 && content.DelegationCount > 0, func() (err error) {
+			tx.Delegations, err = c.TransactionDelegationCerts(ctx, hash)
+			return
+		}},
+		{res&ResourceCertificates != 0 && content.PoolUpdateCount > 0, func() (err error) {
+			tx.PoolUpdates, err = c.TransactionPoolUpdateCerts(ctx, hash)
+			return
+		}},
+		{res&ResourceCertificates != 0 && content.PoolRetireCount > 0, func() (err error) {
+			tx.PoolRetirements, err = c.TransactionPoolRetirementCerts(ctx, hash)
+			return
+		}},
+		{res&ResourceCertificates != 0 && content.MirCertCount > 0, func() (err error) {
+			tx.MIRs, err = c.TransactionMIRs(ctx, hash)
+			return
+		}},
+		{res&ResourceWithdrawals != 0 && content.WithdrawalCount > 0, func() (err error) {
+			tx.Withdrawals, err = c.TransactionWithdrawals(ctx, hash)
+			return
+		}},
+	}
+	for _, step := range steps {
+		if !step.want {
+			continue
+		}
+		if err := step.fetch(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FullHandler handles enriched blocks.
+type FullHandler interface {
+	// RollForward is called with each block extending the chain.
+	RollForward(ctx context.Context, b *FullBlock) error
+
+	// RollBackward is called when the blocks after p were rolled back.
+	RollBackward(ctx context.Context, p Point) error
+}
+
+// Handler returns a Handler enriching blocks before passing them to h.
+// Incomplete blocks are enriched again every RetryInterval, reusing the
+// transactions already fetched, until they are complete.
+func (e *Enricher) Handler(h FullHandler) Handler {
+	return &enrichHandler{e: e, h: h}
+}
+
+type enrichHandler struct {
+	e *Enricher
+	h FullHandler
+}
+
+func (eh *enrichHandler) RollForward(ctx context.Context, b blockfrost.Block) error {
+	for {
+		fb, err := eh.e.Enrich(ctx, b)
+		if err == nil {
+			return eh.h.RollForward(ctx, fb)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if eh.e.opts.OnError != nil {
+			eh.e.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(eh.e.opts.RetryInterval):
+		}
+	}
+}
+
+func (eh *enrichHandler) RollBackward(ctx context.Context, p Point) error {
+	return eh.h.RollBackward(ctx, p)
+}
+
+// txCache keeps complete transactions, evicting the oldest ones.
+type txCache struct {
+	mu    sync.Mutex
+	size  int
+	txs   map[string]FullTransaction
+	order []string
+}
+
+func newTxCache(size int) *txCache {
+	return &txCache{size: size, txs: map[string]FullTransaction{}}
+}
+
+// get returns the cached transaction hash of block. Transactions are only
+// immutable within a block, as a rolled back transaction may be included
+// again in another one.
+func (c *txCache) get(hash, block string) (FullTransaction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tx, ok := c.txs[hash]
+	return tx, ok && tx.Content.Block == block
+}
+
+func (c *txCache) put(tx FullTransaction) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.txs[tx.Hash]; !ok {
+		c.order = append(c.order, tx.Hash)
+	}
+	c.txs[tx.Hash] = tx
+	for len(c.order) > c.size {
+		delete(c.txs, c.order[0])
+		c.order = c.order[1:]
+	}
+}
@@ -0,0 +1,210 @@
+package follower_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/follower"
+)
+
+var errRateLimited = &blockfrost.APIError{Response: blockfrost.OverusageLimit{StatusCode: 429, Error: "Project Over Limit"}}
+
+// txServer is a fake client serving the transactions of a block.
+type txServer struct {
+	blockfrost.APIClient
+
+	mu       sync.Mutex
+	block    string
+	txs      []string
+	calls    map[string]int
+	active   int
+	peak     int
+	limitTx  string // rate limited transaction, until limitN calls
+	limitN   int
+	redeemed map[string]bool
+}
+
+func newTxServer(block string, n int) *txServer {
+	s := &txServer{block: block, calls: map[string]int{}, redeemed: map[string]bool{}}
+	for i := 0; i < n; i++ {
+		s.txs = append(s.txs, fmt.Sprintf("tx-%03d", i))
+	}
+	return s
+}
+
+func (s *txServer) call(method, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[method]++
+	if hash == s.limitTx && s.limitN > 0 {
+		s.limitN--
+		return errRateLimited
+	}
+	return nil
+}
+
+func (s *txServer) BlockTransactions(ctx context.Context, hash string, query blockfrost.APIQueryParams) ([]blockfrost.Transaction, error) {
+	if err := s.call("BlockTransactions", ""); err != nil {
+		return nil, err
+	}
+	if hash != s.block {
+		return nil, errNotFound
+	}
+	var page []blockfrost.Transaction
+	for i := (query.Page - 1) * query.Count; i < len(s.txs) && len(page) < query.Count; i++ {
+		page = append(page, blockfrost.Transaction(s.txs[i]))
+	}
+	return page, nil
+}
+
+func (s *txServer) Transaction(ctx context.Context, hash string) (blockfrost.TransactionContent, error) {
+	s.mu.Lock()
+	s.active++
+	if s.active > s.peak {
+		s.peak = s.active
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.active--
+		s.mu.Unlock()
+	}()
+	time.Sleep(time.Millisecond)
+	if err := s.call("Transaction", hash); err != nil {
+		return blockfrost.TransactionContent{}, err
+	}
+	tc := blockfrost.TransactionContent{Hash: hash, Block: s.block}
+	if s.redeemed[hash] {
+		tc.RedeemerCount = 1
+	}
+	return tc, nil
+}
+
+func (s *txServer) TransactionUTXOs(ctx context.Context, hash string) (blockfrost.TransactionUTXOs, error) {
+	return blockfrost.TransactionUTXOs{Hash: hash}, s.call("TransactionUTXOs", hash)
+}
+
+func (s *txServer) TransactionMetadata(ctx context.Context, hash string) ([]blockfrost.TransactionMetadata, error) {
+	return []blockfrost.TransactionMetadata{}, s.call("TransactionMetadata", hash)
+}
+
+func (s *txServer) TransactionRedeemers(ctx context.Context, hash string) ([]blockfrost.TransactionRedeemer, error) {
+	return []blockfrost.TransactionRedeemer{{TxIndex: 0, Purpose: "spend"}}, s.call("TransactionRedeemers", hash)
+}
+
+func (s *txServer) callsOf(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func TestEnrich(t *testing.T) {
+	s := newTxServer("b", 250)
+	s.redeemed["tx-007"] = true
+	e := follower.NewEnricher(s, follower.EnrichOptions{Concurrency: 4})
+
+	fb, err := e.Enrich(context.Background(), blockfrost.Block{Hash: "b", TxCount: 250})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fb.Transactions) != 250 {
+		t.Fatalf("got %d transactions", len(fb.Transactions))
+	}
+	for i, tx := range fb.Transactions {
+		if want := fmt.Sprintf("tx-%03d", i); tx.Hash != want || tx.Content.Hash != want || tx.UTXOs.Hash != want {
+			t.Fatalf("transaction %d: got %+v", i, tx)
+		}
+	}
+	if len(fb.Transactions[7].Redeemers) != 1 || fb.Transactions[8].Redeemers != nil {
+		t.Fatal("redeemers fetched regardless of the redeemer count")
+	}
+	if got := s.callsOf("TransactionRedeemers"); got != 1 {
+		t.Fatalf("got %d redeemer calls, want 1", got)
+	}
+	if got := s.callsOf("BlockTransactions"); got != 3 {
+		t.Fatalf("got %d pages, want 3", got)
+	}
+	if s.peak > 4 {
+		t.Fatalf("got %d concurrent requests, want at most 4", s.peak)
+	}
+
+	// Blocks without transactions need no request.
+	if fb, err := e.Enrich(context.Background(), blockfrost.Block{Hash: "empty"}); err != nil || len(fb.Transactions) != 0 {
+		t.Fatalf("got %v, %v", fb, err)
+	}
+}
+
+func TestEnrichRateLimited(t *testing.T) {
+	s := newTxServer("b", 50)
+	s.limitTx, s.limitN = "tx-010", 1
+	e := follower.NewEnricher(s, follower.EnrichOptions{Concurrency: 1, Resources: follower.ResourceUTXOs})
+	b := blockfrost.Block{Hash: "b", TxCount: 50}
+
+	fb, err := e.Enrich(context.Background(), b)
+	if !errors.Is(err, follower.ErrIncompleteBlock) {
+		t.Fatalf("got %v, want ErrIncompleteBlock", err)
+	}
+	if !errors.Is(fb.Transactions[10].Err, errRateLimited) {
+		t.Fatalf("got %v, want rate limit error", fb.Transactions[10].Err)
+	}
+	if fb.Transactions[0].Err != nil || fb.Transactions[0].UTXOs.Hash != "tx-000" {
+		t.Fatalf("got %+v, want complete transaction", fb.Transactions[0])
+	}
+	fetched := s.callsOf("Transaction")
+	if fetched == 50 {
+		t.Fatal("requests not stopped after rate limit")
+	}
+
+	// The transactions fetched are reused.
+	fb, err = e.Enrich(context.Background(), b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.callsOf("Transaction"); got >= fetched+50 {
+		t.Fatalf("got %d transaction calls, cache not reused", got-fetched)
+	}
+	for i, tx := range fb.Transactions {
+		if tx.Err != nil || tx.Content.Hash != fmt.Sprintf("tx-%03d", i) {
+			t.Fatalf("transaction %d: got %+v", i, tx)
+		}
+	}
+}
+
+type fullRecorder struct {
+	blocks chan *follower.FullBlock
+}
+
+func (r *fullRecorder) RollForward(ctx context.Context, b *follower.FullBlock) error {
+	r.blocks <- b
+	return nil
+}
+
+func (r *fullRecorder) RollBackward(ctx context.Context, p follower.Point) error {
+	return nil
+}
+
+func TestEnricherHandler(t *testing.T) {
+	s := newTxServer("b", 5)
+	s.limitTx, s.limitN = "tx-002", 2
+	var errs []error
+	e := follower.NewEnricher(s, follower.EnrichOptions{
+		RetryInterval: time.Millisecond,
+		OnError:       func(err error) { errs = append(errs, err) },
+	})
+	r := &fullRecorder{blocks: make(chan *follower.FullBlock, 1)}
+	if err := e.Handler(r).RollForward(context.Background(), blockfrost.Block{Hash: "b", TxCount: 5}); err != nil {
+		t.Fatal(err)
+	}
+	fb := <-r.blocks
+	if len(fb.Transactions) != 5 || fb.Transactions[2].Err != nil {
+		t.Fatalf("got %+v", fb.Transactions)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2", len(errs))
+	}
+}
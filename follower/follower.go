@@ -0,0 +1,294 @@
+// Package follower follows the chain block by block, detecting rollbacks.
+//
+// A Follower pages forward with BlocksNext, and checks that each block
+// extends the last one it saw. When it does not, the follower walks back
+// its recent history to find the fork point, and rolls back to it before
+// rolling forward on the new chain:
+//
+//	f := follower.New(client, follower.Options{
+//		Start: follower.FromHeight(9000000),
+//		Depth: 3,
+//		Store: follower.NewFileStore("checkpoint.json"),
+//	})
+//	err := f.Run(ctx, indexer)
+//
+// An Enricher fetches the transactions of each block, for handlers needing
+// more than block headers:
+//
+//	e := follower.NewEnricher(client, follower.EnrichOptions{})
+//	err := f.Run(ctx, e.Handler(indexer))
+package follower
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/internal/apierr"
+)
+
+// ErrForkNotFound is returned when the fork point of a rollback is older
+// than the history kept by the follower.
+var ErrForkNotFound = errors.New("follower: fork point not found")
+
+// Point is a block of the chain.
+type Point struct {
+	Slot   int    `json:"slot"`
+	Hash   string `json:"hash"`
+	Height int    `json:"height"`
+}
+
+// PointOf returns the point of b.
+func PointOf(b blockfrost.Block) Point {
+	return Point{Slot: b.Slot, Hash: b.Hash, Height: b.Height}
+}
+
+// Start is the point the follower starts from, when it has no checkpoint.
+// The zero value starts from the tip.
+type Start struct {
+	point  *Point
+	height int
+}
+
+// FromPoint starts after the block p, which is not rolled forward. Only
+// the hash of p is required.
+func FromPoint(p Point) Start {
+	return Start{point: &p}
+}
+
+// FromHeight starts from the block at height, which is rolled forward
+// first. height must be at least 1.
+func FromHeight(height int) Start {
+	return Start{height: height}
+}
+
+// FromTip starts after the latest block with enough confirmations.
+func FromTip() Start {
+	return Start{}
+}
+
+// Handler handles the blocks followed.
+type Handler interface {
+	// RollForward is called with each block extending the chain.
+	RollForward(ctx context.Context, b blockfrost.Block) error
+
+	// RollBackward is called when the blocks after p were rolled back.
+	RollBackward(ctx context.Context, p Point) error
+}
+
+// Options configures a Follower.
+type Options struct {
+	// Start is the point to start from when Store holds no checkpoint.
+	// Default: the tip.
+	Start Start
+
+	// Depth is the number of confirmations a block needs before being
+	// rolled forward. Deeper blocks are less likely to be rolled back.
+	Depth int
+
+	// Interval between polls once the follower reached the tip, and
+	// between retries of failed requests. Default: 20s.
+	Interval time.Duration
+
+	// History is the number of blocks kept to find the fork point of
+	// rollbacks. Default: 100.
+	History int
+
+	// Store saves checkpoints to resume from after a restart. Default: none.
+	Store CheckpointStore
+
+	// OnError is called with the errors of requests, which are retried
+	// after Interval.
+	OnError func(error)
+}
+
+// Follower follows the chain. Blocks are delivered at least once: after a
+// restart, the blocks handled since the last checkpoint are rolled forward
+// again.
+type Follower struct {
+	client  blockfrost.APIClient
+	opts    Options
+	history []Point
+}
+
+// New returns a Follower of the chain served by client.
+func New(client blockfrost.APIClient, opts Options) *Follower {
+	if opts.Interval <= 0 {
+		opts.Interval = 20 * time.Second
+	}
+	if opts.History <= 0 {
+		opts.History = 100
+	}
+	if opts.Depth < 0 {
+		opts.Depth = 0
+	}
+	return &Follower{client: client, opts: opts}
+}
+
+// fatalError is an error of the handler or of the store, which stops the
+// follower.
+type fatalError struct {
+	err error
+}
+
+func (e *fatalError) Error() string { return e.err.Error() }
+
+func (e *fatalError) Unwrap() error { return e.err }
+
+// Run follows the chain, calling h for each block or rollback, until ctx is
+// done or h or the store return an error, which Run returns.
+func (f *Follower) Run(ctx context.Context, h Handler) error {
+	for {
+		var (
+			progressed bool
+			err        error
+		)
+		if f.history == nil {
+			err = f.init(ctx)
+		} else {
+			progressed, err = f.step(ctx, h)
+		}
+		var fatal *fatalError
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.As(err, &fatal):
+			return fatal.err
+		case err != nil && f.opts.OnError != nil:
+			f.opts.OnError(err)
+		}
+		if progressed {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.opts.Interval):
+		}
+	}
+}
+
+// init loads the history from the store, or from the start point.
+func (f *Follower) init(ctx context.Context) error {
+	if f.opts.Store != nil {
+		points, err := f.opts.Store.Load(ctx)
+		if err != nil {
+			return &fatalError{fmt.Errorf("follower: load checkpoint: %w", err)}
+		}
+		if len(points) > 0 {
+			f.history = points
+			return nil
+		}
+	}
+
+	start := f.opts.Start
+	if start.point != nil {
+		f.history = []Point{*start.point}
+		return nil
+	}
+	var height int
+	if start.height > 0 {
+		height = start.height - 1
+	} else {
+		tip, err := f.client.BlockLatest(ctx)
+		if err != nil {
+			return fmt.Errorf("follower: latest block: %w", err)
+		}
+		if f.opts.Depth == 0 {
+			f.history = []Point{PointOf(tip)}
+			return nil
+		}
+		height = tip.Height - f.opts.Depth
+	}
+	b, err := f.client.Block(ctx, strconv.Itoa(height))
+	if err != nil {
+		return fmt.Errorf("follower: block %d: %w", height, err)
+	}
+	f.history = []Point{PointOf(b)}
+	return nil
+}
+
+// step rolls forward the blocks following the last one seen, or rolls
+// back. It reports whether it made progress.
+func (f *Follower) step(ctx context.Context, h Handler) (bool, error) {
+	last := f.history[len(f.history)-1]
+	blocks, err := f.client.BlocksNext(ctx, last.Hash)
+	if apierr.IsNotFound(err) {
+		return f.rollback(ctx, h)
+	}
+	if err != nil {
+		return false, fmt.Errorf("follower: blocks after %s: %w", last.Hash, err)
+	}
+
+	n := 0
+	for _, b := range blocks {
+		if b.Confirmations < f.opts.Depth {
+			break
+		}
+		if b.PreviousBlock != last.Hash {
+			if n > 0 {
+				if err := f.save(ctx); err != nil {
+					return false, err
+				}
+			}
+			rolledBack, err := f.rollback(ctx, h)
+			return n > 0 || rolledBack, err
+		}
+		if err := h.RollForward(ctx, b); err != nil {
+			return false, &fatalError{err}
+		}
+		last = PointOf(b)
+		f.push(last)
+		n++
+	}
+	if n == 0 {
+		return false, nil
+	}
+	return true, f.save(ctx)
+}
+
+func (f *Follower) push(p Point) {
+	f.history = append(f.history, p)
+	if extra := len(f.history) - f.opts.History; extra > 0 {
+		f.history = append(f.history[:0], f.history[extra:]...)
+	}
+}
+
+func (f *Follower) save(ctx context.Context) error {
+	if f.opts.Store == nil {
+		return nil
+	}
+	if err := f.opts.Store.Save(ctx, f.history); err != nil {
+		return &fatalError{fmt.Errorf("follower: save checkpoint: %w", err)}
+	}
+	return nil
+}
+
+// rollback walks back the history to the most recent point still on the
+// chain, and rolls back to it. Points are looked up by hash: blocks rolled
+// back are not found. It reports whether it rolled back.
+func (f *Follower) rollback(ctx context.Context, h Handler) (bool, error) {
+	for i := len(f.history) - 1; i >= 0; i-- {
+		b, err := f.client.Block(ctx, f.history[i].Hash)
+		if apierr.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("follower: block %s: %w", f.history[i].Hash, err)
+		}
+		if i == len(f.history)-1 {
+			// Nothing was rolled back after all.
+			return false, nil
+		}
+		p := PointOf(b)
+		f.history = append(f.history[:i], p)
+		if err := h.RollBackward(ctx, p); err != nil {
+			return false, &fatalError{err}
+		}
+		return true, f.save(ctx)
+	}
+	return false, &fatalError{fmt.Errorf("%w: rolled back past block %s", ErrForkNotFound, f.history[0].Hash)}
+}
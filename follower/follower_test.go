@@ -0,0 +1,349 @@
+package follower_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/follower"
+)
+
+var errNotFound = &blockfrost.APIError{Response: blockfrost.NotFound{StatusCode: 404, Error: "Not Found"}}
+
+// chain is a fake client serving a chain of blocks, from height 0.
+type chain struct {
+	blockfrost.APIClient
+
+	mu     sync.Mutex
+	blocks []blockfrost.Block
+	fork   int
+}
+
+func newChain(n int) *chain {
+	c := &chain{}
+	c.extend(n + 1)
+	return c
+}
+
+// extend adds n blocks to the chain.
+func (c *chain) extend(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := 0; i < n; i++ {
+		b := blockfrost.Block{Height: len(c.blocks), Slot: 20 * len(c.blocks)}
+		b.Hash = fmt.Sprintf("%d-%d", c.fork, b.Height)
+		if b.Height > 0 {
+			b.PreviousBlock = c.blocks[b.Height-1].Hash
+		}
+		c.blocks = append(c.blocks, b)
+	}
+}
+
+// rollback removes the last n blocks of the chain.
+func (c *chain) rollback(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blocks = c.blocks[:len(c.blocks)-n]
+	c.fork++
+}
+
+func (c *chain) confirmed(b blockfrost.Block) blockfrost.Block {
+	b.Confirmations = len(c.blocks) - 1 - b.Height
+	return b
+}
+
+func (c *chain) find(hashOrNumber string) (blockfrost.Block, bool) {
+	if n, err := strconv.Atoi(hashOrNumber); err == nil {
+		if n < len(c.blocks) {
+			return c.confirmed(c.blocks[n]), true
+		}
+		return blockfrost.Block{}, false
+	}
+	for _, b := range c.blocks {
+		if b.Hash == hashOrNumber {
+			return c.confirmed(b), true
+		}
+	}
+	return blockfrost.Block{}, false
+}
+
+func (c *chain) BlockLatest(ctx context.Context) (blockfrost.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.confirmed(c.blocks[len(c.blocks)-1]), nil
+}
+
+func (c *chain) Block(ctx context.Context, hashOrNumber string) (blockfrost.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if b, ok := c.find(hashOrNumber); ok {
+		return b, nil
+	}
+	return blockfrost.Block{}, errNotFound
+}
+
+func (c *chain) BlocksNext(ctx context.Context, hashOrNumber string) ([]blockfrost.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.find(hashOrNumber)
+	if !ok {
+		return nil, errNotFound
+	}
+	var next []blockfrost.Block
+	for _, n := range c.blocks[b.Height+1:] {
+		if len(next) == 100 {
+			break
+		}
+		next = append(next, c.confirmed(n))
+	}
+	return next, nil
+}
+
+type event struct {
+	forward bool
+	hash    string
+	height  int
+}
+
+// recorder records events, and fails to roll forward with err if set.
+type recorder struct {
+	events chan event
+	err    error
+}
+
+func (r *recorder) RollForward(ctx context.Context, b blockfrost.Block) error {
+	select {
+	case r.events <- event{true, b.Hash, b.Height}:
+		return r.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *recorder) RollBackward(ctx context.Context, p follower.Point) error {
+	select {
+	case r.events <- event{false, p.Hash, p.Height}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func start(t *testing.T, f *follower.Follower, r *recorder) <-chan error {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	stopped := make(chan struct{})
+	go func() {
+		done <- f.Run(ctx, r)
+		close(stopped)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+	return done
+}
+
+func expect(t *testing.T, r *recorder, want ...event) {
+	t.Helper()
+	for _, w := range want {
+		select {
+		case got := <-r.events:
+			if got != w {
+				t.Fatalf("got %+v, want %+v", got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %+v", w)
+		}
+	}
+}
+
+func expectNone(t *testing.T, r *recorder) {
+	t.Helper()
+	select {
+	case got := <-r.events:
+		t.Fatalf("unexpected %+v", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func forward(fork int, from, to int) []event {
+	var out []event
+	for h := from; h <= to; h++ {
+		out = append(out, event{true, fmt.Sprintf("%d-%d", fork, h), h})
+	}
+	return out
+}
+
+func TestFollower(t *testing.T) {
+	c := newChain(250)
+	r := &recorder{events: make(chan event)}
+	f := follower.New(c, follower.Options{Start: follower.FromHeight(1), Interval: time.Millisecond})
+	start(t, f, r)
+
+	// Blocks are paged forward past the page size of BlocksNext.
+	expect(t, r, forward(0, 1, 250)...)
+	expectNone(t, r)
+
+	c.extend(2)
+	expect(t, r, forward(0, 251, 252)...)
+}
+
+func TestFollowerRollback(t *testing.T) {
+	c := newChain(10)
+	r := &recorder{events: make(chan event)}
+	f := follower.New(c, follower.Options{Start: follower.FromPoint(follower.Point{Hash: "0-5", Height: 5}), Interval: time.Millisecond})
+	start(t, f, r)
+	expect(t, r, forward(0, 6, 10)...)
+
+	c.rollback(3)
+	c.extend(4)
+	expect(t, r, event{false, "0-7", 7})
+	expect(t, r, forward(1, 8, 11)...)
+}
+
+func TestFollowerRollbackToStart(t *testing.T) {
+	c := newChain(10)
+	r := &recorder{events: make(chan event)}
+	// The start point is known by its hash only.
+	f := follower.New(c, follower.Options{Start: follower.FromPoint(follower.Point{Hash: "0-5"}), Interval: time.Millisecond})
+	start(t, f, r)
+	expect(t, r, forward(0, 6, 10)...)
+
+	c.rollback(5)
+	c.extend(2)
+	expect(t, r, event{false, "0-5", 5})
+	expect(t, r, forward(1, 6, 7)...)
+}
+
+func TestFollowerDepth(t *testing.T) {
+	c := newChain(10)
+	r := &recorder{events: make(chan event)}
+	f := follower.New(c, follower.Options{Depth: 3, Interval: time.Millisecond})
+	start(t, f, r)
+	expectNone(t, r)
+
+	// Starting from the tip, blocks are rolled forward once they have 3
+	// confirmations.
+	c.extend(4)
+	expect(t, r, forward(0, 8, 11)...)
+	expectNone(t, r)
+}
+
+func TestFollowerForkNotFound(t *testing.T) {
+	c := newChain(10)
+	r := &recorder{events: make(chan event, 100)}
+	f := follower.New(c, follower.Options{Start: follower.FromHeight(1), History: 2, Interval: time.Millisecond})
+	done := start(t, f, r)
+	expect(t, r, forward(0, 1, 10)...)
+
+	c.rollback(5)
+	c.extend(6)
+	select {
+	case err := <-done:
+		if !errors.Is(err, follower.ErrForkNotFound) {
+			t.Fatalf("got %v, want ErrForkNotFound", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+}
+
+// stale serves blocks after the last one whose previous block does not
+// match, as a lagging backend may, counting the requests.
+type stale struct {
+	*chain
+	requests int
+}
+
+func (c *stale) BlocksNext(ctx context.Context, hashOrNumber string) ([]blockfrost.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	return []blockfrost.Block{{Hash: "x", PreviousBlock: "y"}}, nil
+}
+
+func TestFollowerNothingRolledBack(t *testing.T) {
+	c := &stale{chain: newChain(10)}
+	r := &recorder{events: make(chan event, 100)}
+	f := follower.New(c, follower.Options{Interval: 20 * time.Millisecond})
+	start(t, f, r)
+	time.Sleep(100 * time.Millisecond)
+	expectNone(t, r)
+
+	// The last block is still on the chain, so the follower waits between
+	// requests rather than spinning.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.requests > 10 {
+		t.Fatalf("got %d requests", c.requests)
+	}
+}
+
+func TestFollowerHandlerError(t *testing.T) {
+	c := newChain(10)
+	stop := errors.New("stop")
+	r := &recorder{events: make(chan event, 100), err: stop}
+	f := follower.New(c, follower.Options{Start: follower.FromHeight(1), Interval: time.Millisecond})
+	if err := f.Run(context.Background(), r); !errors.Is(err, stop) {
+		t.Fatalf("got %v, want %v", err, stop)
+	}
+}
+
+func TestFollowerResume(t *testing.T) {
+	c := newChain(10)
+	store := follower.NewFileStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	opts := follower.Options{Start: follower.FromHeight(1), Interval: time.Millisecond, Store: store}
+
+	r := &recorder{events: make(chan event)}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- follower.New(c, opts).Run(ctx, r) }()
+	expect(t, r, forward(0, 1, 10)...)
+	cancel()
+	<-done
+
+	points, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := points[len(points)-1]; last != (follower.Point{Slot: 200, Hash: "0-10", Height: 10}) {
+		t.Fatalf("got checkpoint %+v", last)
+	}
+
+	// The follower resumes from the checkpoint, and finds the fork point of
+	// a rollback that happened while it was stopped.
+	c.rollback(2)
+	c.extend(3)
+	start(t, follower.New(c, opts), r)
+	expect(t, r, event{false, "0-8", 8})
+	expect(t, r, forward(1, 9, 11)...)
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := &follower.MemoryStore{}
+	ctx := context.Background()
+	if points, err := s.Load(ctx); err != nil || len(points) != 0 {
+		t.Fatalf("got %v, %v", points, err)
+	}
+	want := []follower.Point{{Slot: 1, Hash: "a", Height: 1}, {Slot: 2, Hash: "b", Height: 2}}
+	if err := s.Save(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	want[0].Hash = "changed"
+	got, err := s.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Hash != "a" || !reflect.DeepEqual(got[1], want[1]) {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -0,0 +1,80 @@
+package follower
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/blockfrost/blockfrost-go/internal/atomicfile"
+)
+
+// CheckpointStore saves the recent history of a Follower, so that it can
+// resume, and find the fork point of rollbacks, after a restart.
+type CheckpointStore interface {
+	// Load returns the saved points, oldest first, or none if nothing was
+	// saved yet.
+	Load(ctx context.Context) ([]Point, error)
+
+	// Save replaces the saved points.
+	Save(ctx context.Context, points []Point) error
+}
+
+// MemoryStore is a CheckpointStore keeping checkpoints in memory, such as
+// for a Follower restarted within a process.
+type MemoryStore struct {
+	mu     sync.Mutex
+	points []Point
+}
+
+// Load returns the saved points.
+func (s *MemoryStore) Load(_ context.Context) ([]Point, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Point(nil), s.points...), nil
+}
+
+// Save replaces the saved points.
+func (s *MemoryStore) Save(_ context.Context, points []Point) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.points = append(s.points[:0], points...)
+	return nil
+}
+
+// FileStore is a CheckpointStore keeping checkpoints in a JSON file.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore returns a FileStore keeping checkpoints in the file at path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load reads the saved points. It returns none if the file does not exist.
+func (s *FileStore) Load(_ context.Context) ([]Point, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var points []Point
+	if err := json.Unmarshal(data, &points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// Save replaces the saved points. The file is never left partially
+// written.
+func (s *FileStore) Save(_ context.Context, points []Point) error {
+	data, err := json.Marshal(points)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(s.path, data)
+}
@@ -2,6 +2,13 @@ module github.com/blockfrost/blockfrost-go
 
 go 1.21
 
-require github.com/hashicorp/go-retryablehttp v0.7.5
+require (
+	filippo.io/edwards25519 v1.1.0
+	github.com/hashicorp/go-retryablehttp v0.7.5
+	golang.org/x/crypto v0.31.0
+)
 
-require github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
+require (
+	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+)
@@ -0,0 +1,33 @@
+// Package apierr classifies the errors of the API client, for the packages
+// polling it.
+package apierr
+
+import (
+	"errors"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+// IsNotFound reports whether err is a 404 response.
+func IsNotFound(err error) bool {
+	var apiErr *blockfrost.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	_, ok := apiErr.Response.(blockfrost.NotFound)
+	return ok
+}
+
+// IsRateLimited reports whether err is a response to a project over its
+// limit, or banned for exceeding it.
+func IsRateLimited(err error) bool {
+	var apiErr *blockfrost.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Response.(type) {
+	case blockfrost.OverusageLimit, blockfrost.AutoBanned:
+		return true
+	}
+	return false
+}
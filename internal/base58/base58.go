@@ -0,0 +1,63 @@
+// Package base58 implements the Base58 encoding with the Bitcoin alphabet,
+// used by Byron era addresses.
+package base58
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var alphabetRev [128]int8
+
+func init() {
+	for i := range alphabetRev {
+		alphabetRev[i] = -1
+	}
+	for i, c := range alphabet {
+		alphabetRev[c] = int8(i)
+	}
+}
+
+var radix = big.NewInt(58)
+
+// Encode encodes data as a Base58 string.
+func Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+	n := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+	out := make([]byte, 0, len(data)*138/100+1)
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		out = append(out, alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Decode decodes a Base58 string.
+func Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+	n := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 128 || alphabetRev[c] < 0 {
+			return nil, fmt.Errorf("base58: invalid character %q at position %d", c, i)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(alphabetRev[c])))
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
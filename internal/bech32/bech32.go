@@ -0,0 +1,168 @@
+// Package bech32 implements the Bech32 encoding described in BIP-173 without
+// the 90 character length limit, as used by Cardano (CIP-5).
+package bech32
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var (
+	ErrInvalidChecksum  = errors.New("bech32: invalid checksum")
+	ErrMixedCase        = errors.New("bech32: mixed case string")
+	ErrMissingSeparator = errors.New("bech32: missing separator")
+	ErrInvalidPadding   = errors.New("bech32: invalid padding")
+)
+
+var charsetRev [128]int8
+
+func init() {
+	for i := range charsetRev {
+		charsetRev[i] = -1
+	}
+	for i, c := range charset {
+		charsetRev[c] = int8(i)
+	}
+}
+
+func polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+func createChecksum(hrp string, data []byte) []byte {
+	values := append(hrpExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	mod := polymod(values) ^ 1
+	out := make([]byte, 6)
+	for i := range out {
+		out[i] = byte((mod >> uint(5*(5-i))) & 31)
+	}
+	return out
+}
+
+// Encode encodes data, given as 8-bit bytes, with the human readable part hrp.
+func Encode(hrp string, data []byte) (string, error) {
+	conv, err := ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return EncodeFromBase32(hrp, conv)
+}
+
+// EncodeFromBase32 encodes data that is already grouped into 5-bit values.
+func EncodeFromBase32(hrp string, data []byte) (string, error) {
+	if hrp == "" {
+		return "", errors.New("bech32: empty human readable part")
+	}
+	hrp = strings.ToLower(hrp)
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(data) + 6)
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range append(data, createChecksum(hrp, data)...) {
+		if v > 31 {
+			return "", fmt.Errorf("bech32: invalid data value %d", v)
+		}
+		sb.WriteByte(charset[v])
+	}
+	return sb.String(), nil
+}
+
+// Decode decodes a bech32 string and returns its human readable part and the
+// data converted to 8-bit bytes.
+func Decode(s string) (string, []byte, error) {
+	hrp, data, err := DecodeToBase32(s)
+	if err != nil {
+		return "", nil, err
+	}
+	conv, err := ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, conv, nil
+}
+
+// DecodeToBase32 decodes a bech32 string and returns its human readable part
+// and the data as 5-bit values, without the checksum.
+func DecodeToBase32(s string) (string, []byte, error) {
+	lower := strings.ToLower(s)
+	if lower != s && strings.ToUpper(s) != s {
+		return "", nil, ErrMixedCase
+	}
+	s = lower
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, ErrMissingSeparator
+	}
+	hrp := s[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, fmt.Errorf("bech32: invalid character in human readable part at position %d", i)
+		}
+	}
+	data := make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		c := s[i]
+		if c >= 128 || charsetRev[c] < 0 {
+			return "", nil, fmt.Errorf("bech32: invalid character %q at position %d", c, i)
+		}
+		data = append(data, byte(charsetRev[c]))
+	}
+	if polymod(append(hrpExpand(hrp), data...)) != 1 {
+		return "", nil, ErrInvalidChecksum
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+// ConvertBits regroups data from groups of fromBits to groups of toBits.
+func ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxv := uint32(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, fmt.Errorf("bech32: invalid data value %d", v)
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, ErrInvalidPadding
+	}
+	return out, nil
+}
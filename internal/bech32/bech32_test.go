@@ -0,0 +1,60 @@
+package bech32
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		hrp  string
+		data string
+		want string
+	}{
+		{
+			"stake",
+			"e1337b62cfff6403a06a3acbc34f8c46003c69fe79a3628cefa9c47251",
+			"stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw",
+		},
+		{
+			"asset",
+			"1cadfc0e7068801d51d240d14a4085f2a3673cbb",
+			"asset1rjklcrnsdzqp65wjgrg55sy9723kw09mlgvlc3",
+		},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.data)
+		got, err := Encode(tt.hrp, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Fatalf("expected %s got %s", tt.want, got)
+		}
+		hrp, decoded, err := Decode(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hrp != tt.hrp || !bytes.Equal(decoded, data) {
+			t.Fatalf("expected %s %x got %s %x", tt.hrp, data, hrp, decoded)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		s    string
+		want error
+	}{
+		{"stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgx", ErrInvalidChecksum},
+		{"Stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw", ErrMixedCase},
+		{"stakeuyehkck", ErrMissingSeparator},
+	}
+	for _, tt := range tests {
+		_, _, err := Decode(tt.s)
+		if err != tt.want {
+			t.Fatalf("expected %v got %v", tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestAppendUint(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want string
+	}{
+		{0, "00"},
+		{23, "17"},
+		{24, "1818"},
+		{1000, "1903e8"},
+		{1000000, "1a000f4240"},
+		{1000000000000, "1b000000e8d4a51000"},
+	}
+	for _, tt := range tests {
+		got := hex.EncodeToString(AppendUint(nil, tt.v))
+		if got != tt.want {
+			t.Fatalf("expected %s got %s", tt.want, got)
+		}
+		v, err := NewDecoder(AppendUint(nil, tt.v)).ReadUint()
+		if err != nil || v != tt.v {
+			t.Fatalf("expected %d got %d (%v)", tt.v, v, err)
+		}
+	}
+}
+
+func TestBigIntRoundTrip(t *testing.T) {
+	tests := []struct {
+		v    string
+		want string
+	}{
+		{"-1", "20"},
+		{"-1000", "3903e7"},
+		{"18446744073709551616", "c249010000000000000000"},
+		{"-18446744073709551617", "c349010000000000000000"},
+	}
+	for _, tt := range tests {
+		v, _ := new(big.Int).SetString(tt.v, 10)
+		enc := AppendBigInt(nil, v)
+		if got := hex.EncodeToString(enc); got != tt.want {
+			t.Fatalf("expected %s got %s", tt.want, got)
+		}
+		got, err := NewDecoder(enc).ReadBigInt()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(v) != 0 {
+			t.Fatalf("expected %s got %s", v, got)
+		}
+	}
+}
+
+func TestIndefiniteItems(t *testing.T) {
+	// [_ h'0102', (_ h'03', h'04'), {_ 1: 2}]
+	data, _ := hex.DecodeString("9f4201025f41034104ffbf0102ffff")
+
+	d := NewDecoder(data)
+	raw, err := d.ReadRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(raw, data) {
+		t.Fatalf("expected raw item to span the whole input")
+	}
+
+	d = NewDecoder(data)
+	var items [][]byte
+	err = d.ReadArray(func(i int) error {
+		if i == 2 {
+			return d.ReadMap(func(int) error {
+				if _, err := d.ReadUint(); err != nil {
+					return err
+				}
+				_, err := d.ReadUint()
+				return err
+			})
+		}
+		b, err := d.ReadBytes()
+		items = append(items, b)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || !bytes.Equal(items[1], []byte{3, 4}) {
+		t.Fatalf("unexpected items %x", items)
+	}
+	if err := d.Finish(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	tests := []string{
+		"",
+		"5a00000010",
+		"9b00000000ffffffff",
+		"9f01",
+		"1f",
+		"ff",
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt)
+		if Valid(data) {
+			t.Fatalf("expected %q to be invalid", tt)
+		}
+	}
+}
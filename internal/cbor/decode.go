@@ -0,0 +1,490 @@
+// Package cbor implements the subset of RFC 8949 needed to work with Cardano
+// ledger data. Decoding works directly on byte slices so callers can keep
+// references to the original encoding of any item, which is required to
+// compute hashes of transaction bodies, datums and scripts.
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Major types
+const (
+	MajorUint   byte = 0
+	MajorNegInt byte = 1
+	MajorBytes  byte = 2
+	MajorText   byte = 3
+	MajorArray  byte = 4
+	MajorMap    byte = 5
+	MajorTag    byte = 6
+	MajorSimple byte = 7
+)
+
+// Well known tags
+const (
+	TagPositiveBigNum uint64 = 2
+	TagNegativeBigNum uint64 = 3
+	TagEncodedCBOR    uint64 = 24
+	TagRational       uint64 = 30
+	TagSet            uint64 = 258
+)
+
+const (
+	infoIndefinite byte = 31
+	breakByte      byte = 0xff
+	maxDepth            = 512
+)
+
+var (
+	ErrUnexpectedEOF = errors.New("cbor: unexpected end of data")
+	ErrTrailingData  = errors.New("cbor: trailing data after item")
+	ErrTooDeep       = errors.New("cbor: maximum nesting depth exceeded")
+)
+
+// TypeError is returned when the next item is not of the expected type.
+type TypeError struct {
+	Expected string
+	Major    byte
+	Offset   int
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("cbor: expected %s, got major type %d at offset %d", e.Expected, e.Major, e.Offset)
+}
+
+// Decoder reads CBOR items sequentially from a byte slice.
+type Decoder struct {
+	data []byte
+	pos  int
+}
+
+// NewDecoder returns a decoder reading from data.
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Pos returns the offset of the next item.
+func (d *Decoder) Pos() int {
+	return d.pos
+}
+
+// Done reports whether all data has been consumed.
+func (d *Decoder) Done() bool {
+	return d.pos >= len(d.data)
+}
+
+// Data returns the underlying byte slice.
+func (d *Decoder) Data() []byte {
+	return d.data
+}
+
+// Finish returns ErrTrailingData if not all data has been consumed.
+func (d *Decoder) Finish() error {
+	if !d.Done() {
+		return ErrTrailingData
+	}
+	return nil
+}
+
+// PeekMajor returns the major type of the next item without consuming it.
+func (d *Decoder) PeekMajor() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, ErrUnexpectedEOF
+	}
+	return d.data[d.pos] >> 5, nil
+}
+
+// PeekBreak reports whether the next byte is the "break" stop code of an
+// indefinite length item.
+func (d *Decoder) PeekBreak() bool {
+	return d.pos < len(d.data) && d.data[d.pos] == breakByte
+}
+
+// PeekNull reports whether the next item is null.
+func (d *Decoder) PeekNull() bool {
+	return d.pos < len(d.data) && d.data[d.pos] == 0xf6
+}
+
+// PeekTag returns the tag number of the next item if it is a tag.
+func (d *Decoder) PeekTag() (uint64, bool) {
+	saved := d.pos
+	defer func() { d.pos = saved }()
+	major, _, arg, err := d.readHead()
+	if err != nil || major != MajorTag {
+		return 0, false
+	}
+	return arg, true
+}
+
+func (d *Decoder) typeError(expected string) error {
+	major, _ := d.PeekMajor()
+	return &TypeError{Expected: expected, Major: major, Offset: d.pos}
+}
+
+// readHead reads the initial byte and argument of an item. For indefinite
+// length items info is 31 and arg is 0.
+func (d *Decoder) readHead() (major byte, info byte, arg uint64, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, 0, ErrUnexpectedEOF
+	}
+	ib := d.data[d.pos]
+	major = ib >> 5
+	info = ib & 0x1f
+	d.pos++
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		n := 1 << (info - 24)
+		if len(d.data)-d.pos < n {
+			return 0, 0, 0, ErrUnexpectedEOF
+		}
+		for i := 0; i < n; i++ {
+			arg = arg<<8 | uint64(d.data[d.pos+i])
+		}
+		d.pos += n
+		return major, info, arg, nil
+	case info == infoIndefinite:
+		if major == MajorUint || major == MajorNegInt || major == MajorTag {
+			return 0, 0, 0, fmt.Errorf("cbor: invalid indefinite length for major type %d", major)
+		}
+		return major, info, 0, nil
+	default:
+		return 0, 0, 0, fmt.Errorf("cbor: invalid additional information %d", info)
+	}
+}
+
+// ReadUint reads an unsigned integer.
+func (d *Decoder) ReadUint() (uint64, error) {
+	saved := d.pos
+	major, info, arg, err := d.readHead()
+	if err != nil {
+		return 0, err
+	}
+	if major != MajorUint || info == infoIndefinite {
+		d.pos = saved
+		return 0, d.typeError("unsigned integer")
+	}
+	return arg, nil
+}
+
+// ReadInt reads a signed integer that fits into an int64.
+func (d *Decoder) ReadInt() (int64, error) {
+	saved := d.pos
+	major, info, arg, err := d.readHead()
+	if err != nil {
+		return 0, err
+	}
+	if (major != MajorUint && major != MajorNegInt) || info == infoIndefinite {
+		d.pos = saved
+		return 0, d.typeError("integer")
+	}
+	if arg > 1<<63-1 {
+		d.pos = saved
+		return 0, fmt.Errorf("cbor: integer overflows int64 at offset %d", saved)
+	}
+	if major == MajorNegInt {
+		return -1 - int64(arg), nil
+	}
+	return int64(arg), nil
+}
+
+// ReadBigInt reads an integer of arbitrary size, including bignums encoded
+// with tags 2 and 3.
+func (d *Decoder) ReadBigInt() (*big.Int, error) {
+	saved := d.pos
+	major, info, arg, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case major == MajorUint && info != infoIndefinite:
+		return new(big.Int).SetUint64(arg), nil
+	case major == MajorNegInt && info != infoIndefinite:
+		v := new(big.Int).SetUint64(arg)
+		return v.Neg(v).Sub(v, big.NewInt(1)), nil
+	case major == MajorTag && (arg == TagPositiveBigNum || arg == TagNegativeBigNum):
+		b, err := d.ReadBytes()
+		if err != nil {
+			return nil, err
+		}
+		v := new(big.Int).SetBytes(b)
+		if arg == TagNegativeBigNum {
+			v.Neg(v).Sub(v, big.NewInt(1))
+		}
+		return v, nil
+	}
+	d.pos = saved
+	return nil, d.typeError("integer")
+}
+
+// ReadBytes reads a byte string, concatenating chunks of indefinite length
+// byte strings.
+func (d *Decoder) ReadBytes() ([]byte, error) {
+	return d.readString(MajorBytes, "byte string")
+}
+
+// ReadText reads a UTF-8 text string.
+func (d *Decoder) ReadText() (string, error) {
+	b, err := d.readString(MajorText, "text string")
+	return string(b), err
+}
+
+func (d *Decoder) readString(want byte, name string) ([]byte, error) {
+	saved := d.pos
+	major, info, arg, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+	if major != want {
+		d.pos = saved
+		return nil, d.typeError(name)
+	}
+	if info != infoIndefinite {
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, ErrUnexpectedEOF
+		}
+		b := d.data[d.pos : d.pos+int(arg)]
+		d.pos += int(arg)
+		return b, nil
+	}
+	var out []byte
+	for !d.PeekBreak() {
+		chunk, err := d.readString(want, name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+	}
+	d.pos++
+	if out == nil {
+		out = []byte{}
+	}
+	return out, nil
+}
+
+// ReadArrayHeader reads the header of an array and returns its length, or
+// -1 for indefinite length arrays.
+func (d *Decoder) ReadArrayHeader() (int, error) {
+	return d.readContainerHeader(MajorArray, "array")
+}
+
+// ReadMapHeader reads the header of a map and returns its number of pairs, or
+// -1 for indefinite length maps.
+func (d *Decoder) ReadMapHeader() (int, error) {
+	return d.readContainerHeader(MajorMap, "map")
+}
+
+func (d *Decoder) readContainerHeader(want byte, name string) (int, error) {
+	saved := d.pos
+	major, info, arg, err := d.readHead()
+	if err != nil {
+		return 0, err
+	}
+	if major != want {
+		d.pos = saved
+		return 0, d.typeError(name)
+	}
+	if info == infoIndefinite {
+		return -1, nil
+	}
+	// Every element takes at least one byte, which bounds allocations made
+	// by callers on malformed input.
+	if arg > uint64(len(d.data)-d.pos) {
+		return 0, ErrUnexpectedEOF
+	}
+	return int(arg), nil
+}
+
+// ReadArray reads an array header and calls fn once per element. fn must
+// consume exactly one item.
+func (d *Decoder) ReadArray(fn func(i int) error) error {
+	n, err := d.ReadArrayHeader()
+	if err != nil {
+		return err
+	}
+	return d.readItems(n, fn)
+}
+
+// ReadMap reads a map header and calls fn once per pair. fn must consume
+// exactly one key and one value.
+func (d *Decoder) ReadMap(fn func(i int) error) error {
+	n, err := d.ReadMapHeader()
+	if err != nil {
+		return err
+	}
+	return d.readItems(n, fn)
+}
+
+func (d *Decoder) readItems(n int, fn func(i int) error) error {
+	if n >= 0 {
+		for i := 0; i < n; i++ {
+			if err := fn(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := 0; ; i++ {
+		if d.Done() {
+			return ErrUnexpectedEOF
+		}
+		if d.PeekBreak() {
+			d.pos++
+			return nil
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadTag reads a tag number. The tagged item follows.
+func (d *Decoder) ReadTag() (uint64, error) {
+	saved := d.pos
+	major, _, arg, err := d.readHead()
+	if err != nil {
+		return 0, err
+	}
+	if major != MajorTag {
+		d.pos = saved
+		return 0, d.typeError("tag")
+	}
+	return arg, nil
+}
+
+// SkipTag consumes the given tag if it is the next item.
+func (d *Decoder) SkipTag(tag uint64) bool {
+	if t, ok := d.PeekTag(); ok && t == tag {
+		_, _ = d.ReadTag()
+		return true
+	}
+	return false
+}
+
+// ReadBool reads a boolean.
+func (d *Decoder) ReadBool() (bool, error) {
+	if d.pos >= len(d.data) {
+		return false, ErrUnexpectedEOF
+	}
+	switch d.data[d.pos] {
+	case 0xf4:
+		d.pos++
+		return false, nil
+	case 0xf5:
+		d.pos++
+		return true, nil
+	}
+	return false, d.typeError("boolean")
+}
+
+// ReadNull consumes a null value.
+func (d *Decoder) ReadNull() error {
+	if !d.PeekNull() {
+		if d.Done() {
+			return ErrUnexpectedEOF
+		}
+		return d.typeError("null")
+	}
+	d.pos++
+	return nil
+}
+
+// ReadRaw consumes the next item and returns its encoding.
+func (d *Decoder) ReadRaw() ([]byte, error) {
+	start := d.pos
+	if err := d.skip(0); err != nil {
+		return nil, err
+	}
+	return d.data[start:d.pos], nil
+}
+
+// Skip consumes the next item.
+func (d *Decoder) Skip() error {
+	return d.skip(0)
+}
+
+func (d *Decoder) skip(depth int) error {
+	if depth > maxDepth {
+		return ErrTooDeep
+	}
+	major, info, arg, err := d.readHead()
+	if err != nil {
+		return err
+	}
+	switch major {
+	case MajorUint, MajorNegInt:
+		return nil
+	case MajorBytes, MajorText:
+		if info == infoIndefinite {
+			for !d.PeekBreak() {
+				if next, err := d.PeekMajor(); err != nil {
+					return err
+				} else if next != major {
+					return d.typeError("string chunk")
+				}
+				if err := d.skip(depth + 1); err != nil {
+					return err
+				}
+			}
+			d.pos++
+			return nil
+		}
+		if arg > uint64(len(d.data)-d.pos) {
+			return ErrUnexpectedEOF
+		}
+		d.pos += int(arg)
+		return nil
+	case MajorArray, MajorMap:
+		items := arg
+		if major == MajorMap {
+			items *= 2
+		}
+		if info == infoIndefinite {
+			for {
+				if d.Done() {
+					return ErrUnexpectedEOF
+				}
+				if d.PeekBreak() {
+					d.pos++
+					return nil
+				}
+				if err := d.skip(depth + 1); err != nil {
+					return err
+				}
+			}
+		}
+		if arg > uint64(len(d.data)-d.pos) {
+			return ErrUnexpectedEOF
+		}
+		for i := uint64(0); i < items; i++ {
+			if err := d.skip(depth + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case MajorTag:
+		return d.skip(depth + 1)
+	default:
+		// Simple values and floats carry their payload in the argument,
+		// which readHead has already consumed.
+		if info == infoIndefinite {
+			return fmt.Errorf("cbor: unexpected break at offset %d", d.pos-1)
+		}
+		if info == 24 && arg < 32 {
+			return fmt.Errorf("cbor: invalid simple value %d", arg)
+		}
+		return nil
+	}
+}
+
+// Valid reports whether data holds exactly one well formed item.
+func Valid(data []byte) bool {
+	d := NewDecoder(data)
+	if err := d.Skip(); err != nil {
+		return false
+	}
+	return d.Done()
+}
@@ -0,0 +1,139 @@
+package cbor
+
+import (
+	"math/big"
+)
+
+// The encoding helpers append the encoding of a value to b and return the
+// extended slice, following the convention of strconv.Append*. All lengths
+// and integers use the shortest possible form, as required by the canonical
+// encoding used for hashing.
+
+// AppendHead appends an item head with the given major type and argument.
+func AppendHead(b []byte, major byte, arg uint64) []byte {
+	m := major << 5
+	switch {
+	case arg < 24:
+		return append(b, m|byte(arg))
+	case arg <= 0xff:
+		return append(b, m|24, byte(arg))
+	case arg <= 0xffff:
+		return append(b, m|25, byte(arg>>8), byte(arg))
+	case arg <= 0xffffffff:
+		return append(b, m|26, byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg))
+	default:
+		return append(b, m|27,
+			byte(arg>>56), byte(arg>>48), byte(arg>>40), byte(arg>>32),
+			byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg))
+	}
+}
+
+// AppendUint appends an unsigned integer.
+func AppendUint(b []byte, v uint64) []byte {
+	return AppendHead(b, MajorUint, v)
+}
+
+// AppendInt appends a signed integer.
+func AppendInt(b []byte, v int64) []byte {
+	if v < 0 {
+		return AppendHead(b, MajorNegInt, uint64(-1-v))
+	}
+	return AppendHead(b, MajorUint, uint64(v))
+}
+
+var maxUint64 = new(big.Int).SetUint64(^uint64(0))
+
+// AppendBigInt appends an integer of arbitrary size. Values outside of the
+// 64-bit range are encoded as bignums (tags 2 and 3).
+func AppendBigInt(b []byte, v *big.Int) []byte {
+	if v.Sign() >= 0 {
+		if v.Cmp(maxUint64) <= 0 {
+			return AppendUint(b, v.Uint64())
+		}
+		b = AppendTag(b, TagPositiveBigNum)
+		return AppendBytes(b, v.Bytes())
+	}
+	// -1 - v
+	n := new(big.Int).Neg(v)
+	n.Sub(n, big.NewInt(1))
+	if n.Cmp(maxUint64) <= 0 {
+		return AppendHead(b, MajorNegInt, n.Uint64())
+	}
+	b = AppendTag(b, TagNegativeBigNum)
+	return AppendBytes(b, n.Bytes())
+}
+
+// AppendBytes appends a definite length byte string.
+func AppendBytes(b []byte, v []byte) []byte {
+	b = AppendHead(b, MajorBytes, uint64(len(v)))
+	return append(b, v...)
+}
+
+// AppendBytesChunked appends a byte string, splitting it into an indefinite
+// length string of chunks of at most size bytes when it is longer than size.
+func AppendBytesChunked(b []byte, v []byte, size int) []byte {
+	if len(v) <= size {
+		return AppendBytes(b, v)
+	}
+	b = append(b, MajorBytes<<5|infoIndefinite)
+	for len(v) > 0 {
+		n := size
+		if len(v) < n {
+			n = len(v)
+		}
+		b = AppendBytes(b, v[:n])
+		v = v[n:]
+	}
+	return AppendBreak(b)
+}
+
+// AppendText appends a text string.
+func AppendText(b []byte, v string) []byte {
+	b = AppendHead(b, MajorText, uint64(len(v)))
+	return append(b, v...)
+}
+
+// AppendArrayHeader appends the header of an array of n elements.
+func AppendArrayHeader(b []byte, n int) []byte {
+	return AppendHead(b, MajorArray, uint64(n))
+}
+
+// AppendMapHeader appends the header of a map of n pairs.
+func AppendMapHeader(b []byte, n int) []byte {
+	return AppendHead(b, MajorMap, uint64(n))
+}
+
+// AppendIndefiniteArray appends the header of an indefinite length array.
+// The array must be terminated with AppendBreak.
+func AppendIndefiniteArray(b []byte) []byte {
+	return append(b, MajorArray<<5|infoIndefinite)
+}
+
+// AppendIndefiniteMap appends the header of an indefinite length map. The map
+// must be terminated with AppendBreak.
+func AppendIndefiniteMap(b []byte) []byte {
+	return append(b, MajorMap<<5|infoIndefinite)
+}
+
+// AppendBreak appends the stop code of an indefinite length item.
+func AppendBreak(b []byte) []byte {
+	return append(b, breakByte)
+}
+
+// AppendTag appends a tag number. The tagged item must follow.
+func AppendTag(b []byte, tag uint64) []byte {
+	return AppendHead(b, MajorTag, tag)
+}
+
+// AppendBool appends a boolean.
+func AppendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0xf5)
+	}
+	return append(b, 0xf4)
+}
+
+// AppendNull appends null.
+func AppendNull(b []byte) []byte {
+	return append(b, 0xf6)
+}
@@ -0,0 +1,210 @@
+package ledger
+
+import (
+	"sort"
+
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+)
+
+// tagAuxiliaryData tags the Alonzo auxiliary data format.
+const tagAuxiliaryData uint64 = 259
+
+type auxFormat uint8
+
+const (
+	auxAuto auxFormat = iota
+	// metadata only
+	auxShelley
+	// [metadata, native scripts]
+	auxShelleyMA
+	// #6.259({0: metadata, 1: native scripts, 2..4: plutus scripts})
+	auxAlonzo
+)
+
+// AuxiliaryData holds the metadata and auxiliary scripts of a transaction.
+// Metadata maps labels to the CBOR encoding of their metadatum, and native
+// scripts hold the CBOR encoding of each script.
+type AuxiliaryData struct {
+	Metadata        map[uint64][]byte
+	NativeScripts   [][]byte
+	PlutusV1Scripts [][]byte
+	PlutusV2Scripts [][]byte
+	PlutusV3Scripts [][]byte
+
+	format auxFormat
+	orig   preserved
+}
+
+// Hash returns the hash of the auxiliary data, as referenced by
+// TransactionBody.AuxiliaryDataHash.
+func (a AuxiliaryData) Hash() Hash32 {
+	data, _ := a.MarshalCBOR()
+	return Blake2b256(data)
+}
+
+// MarshalCBOR returns the CBOR encoding of a.
+func (a AuxiliaryData) MarshalCBOR() ([]byte, error) {
+	return a.orig.encode(a.appendFields(nil)), nil
+}
+
+// UnmarshalCBOR decodes a from its CBOR encoding.
+func (a *AuxiliaryData) UnmarshalCBOR(data []byte) error {
+	d := cbor.NewDecoder(data)
+	if err := a.decode(d); err != nil {
+		return wrap("auxiliary data", err)
+	}
+	return wrap("auxiliary data", d.Finish())
+}
+
+func readMetadata(d *cbor.Decoder) (map[uint64][]byte, error) {
+	out := map[uint64][]byte{}
+	err := d.ReadMap(func(int) error {
+		label, err := d.ReadUint()
+		if err != nil {
+			return err
+		}
+		out[label], err = d.ReadRaw()
+		return err
+	})
+	return out, err
+}
+
+func (a *AuxiliaryData) decode(d *cbor.Decoder) error {
+	start := d.Pos()
+	*a = AuxiliaryData{}
+	major, err := d.PeekMajor()
+	if err != nil {
+		return err
+	}
+	switch major {
+	case cbor.MajorMap:
+		a.format = auxShelley
+		if a.Metadata, err = readMetadata(d); err != nil {
+			return err
+		}
+	case cbor.MajorArray:
+		a.format = auxShelleyMA
+		if _, err := readArrayLen(d, "auxiliary data", 2); err != nil {
+			return err
+		}
+		if a.Metadata, err = readMetadata(d); err != nil {
+			return err
+		}
+		if a.NativeScripts, err = readRawItems(d, nil); err != nil {
+			return err
+		}
+	case cbor.MajorTag:
+		a.format = auxAlonzo
+		tag, _ := d.ReadTag()
+		if tag != tagAuxiliaryData {
+			return invalid("auxiliary data: unexpected tag %d", tag)
+		}
+		err := d.ReadMap(func(int) error {
+			key, err := d.ReadUint()
+			if err != nil {
+				return err
+			}
+			switch key {
+			case 0:
+				a.Metadata, err = readMetadata(d)
+			case 1:
+				a.NativeScripts, err = readRawItems(d, nil)
+			case 2:
+				a.PlutusV1Scripts, err = readByteStrings(d, nil)
+			case 3:
+				a.PlutusV2Scripts, err = readByteStrings(d, nil)
+			case 4:
+				a.PlutusV3Scripts, err = readByteStrings(d, nil)
+			default:
+				err = invalid("auxiliary data: unknown key %d", key)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	default:
+		return invalid("auxiliary data: unexpected major type %d", major)
+	}
+	a.orig = preserved{raw: d.Data()[start:d.Pos()], canon: a.appendFields(nil)}
+	return nil
+}
+
+func appendMetadata(b []byte, m map[uint64][]byte) []byte {
+	labels := make([]uint64, 0, len(m))
+	for label := range m {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i] < labels[j] })
+	b = cbor.AppendMapHeader(b, len(labels))
+	for _, label := range labels {
+		b = cbor.AppendUint(b, label)
+		b = append(b, m[label]...)
+	}
+	return b
+}
+
+func appendRawArray(b []byte, items [][]byte) []byte {
+	b = cbor.AppendArrayHeader(b, len(items))
+	for _, item := range items {
+		b = append(b, item...)
+	}
+	return b
+}
+
+func appendBytesArray(b []byte, items [][]byte) []byte {
+	b = cbor.AppendArrayHeader(b, len(items))
+	for _, item := range items {
+		b = cbor.AppendBytes(b, item)
+	}
+	return b
+}
+
+func (a AuxiliaryData) appendFields(b []byte) []byte {
+	plutus := len(a.PlutusV1Scripts) > 0 || len(a.PlutusV2Scripts) > 0 || len(a.PlutusV3Scripts) > 0
+	format := a.format
+	switch {
+	case plutus:
+		format = auxAlonzo
+	case format == auxAuto && len(a.NativeScripts) == 0, format == auxShelley && len(a.NativeScripts) == 0:
+		format = auxShelley
+	case format == auxAuto, format == auxShelley:
+		format = auxAlonzo
+	}
+
+	switch format {
+	case auxShelley:
+		return appendMetadata(b, a.Metadata)
+	case auxShelleyMA:
+		b = cbor.AppendArrayHeader(b, 2)
+		b = appendMetadata(b, a.Metadata)
+		return appendRawArray(b, a.NativeScripts)
+	}
+
+	type entry struct {
+		present bool
+		append  func([]byte) []byte
+	}
+	entries := []entry{
+		{len(a.Metadata) > 0, func(b []byte) []byte { return appendMetadata(b, a.Metadata) }},
+		{len(a.NativeScripts) > 0, func(b []byte) []byte { return appendRawArray(b, a.NativeScripts) }},
+		{len(a.PlutusV1Scripts) > 0, func(b []byte) []byte { return appendBytesArray(b, a.PlutusV1Scripts) }},
+		{len(a.PlutusV2Scripts) > 0, func(b []byte) []byte { return appendBytesArray(b, a.PlutusV2Scripts) }},
+		{len(a.PlutusV3Scripts) > 0, func(b []byte) []byte { return appendBytesArray(b, a.PlutusV3Scripts) }},
+	}
+	n := 0
+	for _, e := range entries {
+		if e.present {
+			n++
+		}
+	}
+	b = cbor.AppendTag(b, tagAuxiliaryData)
+	b = cbor.AppendMapHeader(b, n)
+	for key, e := range entries {
+		if e.present {
+			b = cbor.AppendUint(b, uint64(key))
+			b = e.append(b)
+		}
+	}
+	return b
+}
@@ -0,0 +1,320 @@
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+)
+
+// TransactionInput references an output of a previous transaction.
+type TransactionInput struct {
+	TxID  Hash32
+	Index uint64
+}
+
+// String returns the input as "<tx hash>#<index>".
+func (i TransactionInput) String() string {
+	return fmt.Sprintf("%s#%d", i.TxID, i.Index)
+}
+
+// Withdrawal withdraws rewards from a reward account.
+type Withdrawal struct {
+	// Raw reward address
+	RewardAccount []byte
+	Amount        uint64
+}
+
+// TransactionBody is the part of a transaction covered by its hash and
+// signatures. Optional fields are nil or empty when absent.
+type TransactionBody struct {
+	Inputs                []TransactionInput
+	Outputs               []TransactionOutput
+	Fee                   uint64
+	TTL                   *uint64
+	Certificates          []Certificate
+	Withdrawals           []Withdrawal
+	AuxiliaryDataHash     *Hash32
+	ValidityIntervalStart *uint64
+	Mint                  Mint
+	ScriptDataHash        *Hash32
+	Collateral            []TransactionInput
+	RequiredSigners       []Hash28
+	NetworkID             *uint64
+	CollateralReturn      *TransactionOutput
+	TotalCollateral       *uint64
+	ReferenceInputs       []TransactionInput
+	VotingProcedures      []VotingProcedure
+	ProposalProcedures    []ProposalProcedure
+	CurrentTreasuryValue  *uint64
+	Donation              *uint64
+
+	// Update holds the CBOR encoding of a pre-Conway protocol parameter
+	// update proposal.
+	Update []byte
+
+	// untaggedSets is set for bodies whose sets are not tagged with 258, as
+	// before Conway.
+	untaggedSets bool
+	orig         preserved
+}
+
+// Hash returns the hash of the body, which is the ID of the transaction.
+func (b TransactionBody) Hash() Hash32 {
+	data, _ := b.MarshalCBOR()
+	return Blake2b256(data)
+}
+
+// MarshalCBOR returns the CBOR encoding of b.
+func (b TransactionBody) MarshalCBOR() ([]byte, error) {
+	return b.orig.encode(b.appendFields(nil)), nil
+}
+
+// UnmarshalCBOR decodes b from its CBOR encoding.
+func (b *TransactionBody) UnmarshalCBOR(data []byte) error {
+	d := cbor.NewDecoder(data)
+	if err := b.decode(d); err != nil {
+		return wrap("transaction body", err)
+	}
+	return wrap("transaction body", d.Finish())
+}
+
+func readInputs(d *cbor.Decoder, tagged *bool) ([]TransactionInput, error) {
+	out := []TransactionInput{}
+	err := readSet(d, tagged, func(int) error {
+		if _, err := readArrayLen(d, "input", 2); err != nil {
+			return err
+		}
+		txID, err := readHash32(d)
+		if err != nil {
+			return err
+		}
+		index, err := d.ReadUint()
+		if err != nil {
+			return err
+		}
+		out = append(out, TransactionInput{TxID: txID, Index: index})
+		return nil
+	})
+	return out, err
+}
+
+func appendInputs(b []byte, inputs []TransactionInput, untagged bool) []byte {
+	b = appendSetHeader(b, len(inputs), untagged)
+	for _, in := range inputs {
+		b = cbor.AppendArrayHeader(b, 2)
+		b = cbor.AppendBytes(b, in.TxID[:])
+		b = cbor.AppendUint(b, in.Index)
+	}
+	return b
+}
+
+func readOptionalUint(d *cbor.Decoder) (*uint64, error) {
+	v, err := d.ReadUint()
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func readOptionalHash32(d *cbor.Decoder) (*Hash32, error) {
+	h, err := readHash32(d)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (b *TransactionBody) decode(d *cbor.Decoder) error {
+	start := d.Pos()
+	*b = TransactionBody{}
+	tagged := false
+	seen := map[uint64]bool{}
+	err := d.ReadMap(func(int) error {
+		key, err := d.ReadUint()
+		if err != nil {
+			return err
+		}
+		if seen[key] {
+			return invalid("duplicate body key %d", key)
+		}
+		seen[key] = true
+		switch key {
+		case 0:
+			b.Inputs, err = readInputs(d, &tagged)
+		case 1:
+			b.Outputs = []TransactionOutput{}
+			err = d.ReadArray(func(int) error {
+				var o TransactionOutput
+				err := o.decode(d)
+				b.Outputs = append(b.Outputs, o)
+				return err
+			})
+		case 2:
+			b.Fee, err = d.ReadUint()
+		case 3:
+			b.TTL, err = readOptionalUint(d)
+		case 4:
+			b.Certificates = []Certificate{}
+			err = readSet(d, nil, func(int) error {
+				var c Certificate
+				err := c.decode(d)
+				b.Certificates = append(b.Certificates, c)
+				return err
+			})
+		case 5:
+			b.Withdrawals = []Withdrawal{}
+			err = d.ReadMap(func(int) error {
+				account, err := d.ReadBytes()
+				if err != nil {
+					return err
+				}
+				amount, err := d.ReadUint()
+				b.Withdrawals = append(b.Withdrawals, Withdrawal{RewardAccount: account, Amount: amount})
+				return err
+			})
+		case 6:
+			b.Update, err = d.ReadRaw()
+		case 7:
+			b.AuxiliaryDataHash, err = readOptionalHash32(d)
+		case 8:
+			b.ValidityIntervalStart, err = readOptionalUint(d)
+		case 9:
+			b.Mint, err = readMultiAsset(d, d.ReadInt)
+		case 11:
+			b.ScriptDataHash, err = readOptionalHash32(d)
+		case 13:
+			b.Collateral, err = readInputs(d, nil)
+		case 14:
+			b.RequiredSigners = []Hash28{}
+			err = readSet(d, nil, func(int) error {
+				h, err := readHash28(d)
+				b.RequiredSigners = append(b.RequiredSigners, h)
+				return err
+			})
+		case 15:
+			b.NetworkID, err = readOptionalUint(d)
+		case 16:
+			b.CollateralReturn = &TransactionOutput{}
+			err = b.CollateralReturn.decode(d)
+		case 17:
+			b.TotalCollateral, err = readOptionalUint(d)
+		case 18:
+			b.ReferenceInputs, err = readInputs(d, nil)
+		case 19:
+			b.VotingProcedures, err = readVotingProcedures(d)
+		case 20:
+			b.ProposalProcedures = []ProposalProcedure{}
+			err = readSet(d, nil, func(int) error {
+				p, err := readProposalProcedure(d)
+				b.ProposalProcedures = append(b.ProposalProcedures, p)
+				return err
+			})
+		case 21:
+			b.CurrentTreasuryValue, err = readOptionalUint(d)
+		case 22:
+			b.Donation, err = readOptionalUint(d)
+		default:
+			err = invalid("unknown body key %d", key)
+		}
+		if err != nil {
+			return wrap(fmt.Sprintf("body key %d", key), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !seen[0] || !seen[1] || !seen[2] {
+		return invalid("body is missing inputs, outputs or fee")
+	}
+	b.untaggedSets = !tagged
+	b.orig = preserved{raw: d.Data()[start:d.Pos()], canon: b.appendFields(nil)}
+	return nil
+}
+
+func (b TransactionBody) appendFields(out []byte) []byte {
+	type entry struct {
+		key     uint64
+		present bool
+		append  func([]byte) []byte
+	}
+	appendUint := func(v *uint64) func([]byte) []byte {
+		return func(out []byte) []byte { return cbor.AppendUint(out, *v) }
+	}
+	appendHash := func(h *Hash32) func([]byte) []byte {
+		return func(out []byte) []byte { return cbor.AppendBytes(out, h[:]) }
+	}
+	entries := []entry{
+		{0, true, func(out []byte) []byte { return appendInputs(out, b.Inputs, b.untaggedSets) }},
+		{1, true, func(out []byte) []byte {
+			out = cbor.AppendArrayHeader(out, len(b.Outputs))
+			for _, o := range b.Outputs {
+				enc, _ := o.MarshalCBOR()
+				out = append(out, enc...)
+			}
+			return out
+		}},
+		{2, true, func(out []byte) []byte { return cbor.AppendUint(out, b.Fee) }},
+		{3, b.TTL != nil, appendUint(b.TTL)},
+		{4, len(b.Certificates) > 0, func(out []byte) []byte {
+			out = appendSetHeader(out, len(b.Certificates), b.untaggedSets)
+			for _, c := range b.Certificates {
+				out = c.appendTo(out)
+			}
+			return out
+		}},
+		{5, len(b.Withdrawals) > 0, func(out []byte) []byte {
+			out = cbor.AppendMapHeader(out, len(b.Withdrawals))
+			for _, w := range b.Withdrawals {
+				out = cbor.AppendBytes(out, w.RewardAccount)
+				out = cbor.AppendUint(out, w.Amount)
+			}
+			return out
+		}},
+		{6, b.Update != nil, func(out []byte) []byte { return append(out, b.Update...) }},
+		{7, b.AuxiliaryDataHash != nil, appendHash(b.AuxiliaryDataHash)},
+		{8, b.ValidityIntervalStart != nil, appendUint(b.ValidityIntervalStart)},
+		{9, len(b.Mint) > 0, func(out []byte) []byte { return appendMultiAsset(out, b.Mint, cbor.AppendInt) }},
+		{11, b.ScriptDataHash != nil, appendHash(b.ScriptDataHash)},
+		{13, len(b.Collateral) > 0, func(out []byte) []byte { return appendInputs(out, b.Collateral, b.untaggedSets) }},
+		{14, len(b.RequiredSigners) > 0, func(out []byte) []byte {
+			out = appendSetHeader(out, len(b.RequiredSigners), b.untaggedSets)
+			for _, h := range b.RequiredSigners {
+				out = cbor.AppendBytes(out, h[:])
+			}
+			return out
+		}},
+		{15, b.NetworkID != nil, appendUint(b.NetworkID)},
+		{16, b.CollateralReturn != nil, func(out []byte) []byte {
+			enc, _ := b.CollateralReturn.MarshalCBOR()
+			return append(out, enc...)
+		}},
+		{17, b.TotalCollateral != nil, appendUint(b.TotalCollateral)},
+		{18, len(b.ReferenceInputs) > 0, func(out []byte) []byte { return appendInputs(out, b.ReferenceInputs, b.untaggedSets) }},
+		{19, len(b.VotingProcedures) > 0, func(out []byte) []byte { return appendVotingProcedures(out, b.VotingProcedures) }},
+		{20, len(b.ProposalProcedures) > 0, func(out []byte) []byte {
+			out = appendSetHeader(out, len(b.ProposalProcedures), b.untaggedSets)
+			for _, p := range b.ProposalProcedures {
+				out = appendProposalProcedure(out, p)
+			}
+			return out
+		}},
+		{21, b.CurrentTreasuryValue != nil, appendUint(b.CurrentTreasuryValue)},
+		{22, b.Donation != nil, appendUint(b.Donation)},
+	}
+
+	n := 0
+	for _, e := range entries {
+		if e.present {
+			n++
+		}
+	}
+	out = cbor.AppendMapHeader(out, n)
+	for _, e := range entries {
+		if e.present {
+			out = cbor.AppendUint(out, e.key)
+			out = e.append(out)
+		}
+	}
+	return out
+}
@@ -0,0 +1,503 @@
+package ledger
+
+import (
+	"net"
+
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+)
+
+// CertificateType identifies the kind of a certificate.
+type CertificateType uint8
+
+const (
+	CertStakeRegistration       CertificateType = 0
+	CertStakeDeregistration     CertificateType = 1
+	CertStakeDelegation         CertificateType = 2
+	CertPoolRegistration        CertificateType = 3
+	CertPoolRetirement          CertificateType = 4
+	CertGenesisKeyDelegation    CertificateType = 5
+	CertMoveInstantaneousReward CertificateType = 6
+
+	// Conway certificates
+	CertRegistration                    CertificateType = 7
+	CertUnregistration                  CertificateType = 8
+	CertVoteDelegation                  CertificateType = 9
+	CertStakeVoteDelegation             CertificateType = 10
+	CertStakeRegistrationDelegation     CertificateType = 11
+	CertVoteRegistrationDelegation      CertificateType = 12
+	CertStakeVoteRegistrationDelegation CertificateType = 13
+	CertCommitteeHotAuthorization       CertificateType = 14
+	CertCommitteeColdResignation        CertificateType = 15
+	CertDRepRegistration                CertificateType = 16
+	CertDRepUnregistration              CertificateType = 17
+	CertDRepUpdate                      CertificateType = 18
+	maxCertificateType                                  = CertDRepUpdate
+)
+
+// DRepType identifies the kind of a delegated representative.
+type DRepType uint8
+
+const (
+	DRepKeyHash      DRepType = 0
+	DRepScriptHash   DRepType = 1
+	DRepAbstain      DRepType = 2
+	DRepNoConfidence DRepType = 3
+)
+
+// DRep is a delegated representative. Hash is only set for DRepKeyHash and
+// DRepScriptHash.
+type DRep struct {
+	Type DRepType
+	Hash Hash28
+}
+
+// Anchor references off-chain metadata by URL and hash.
+type Anchor struct {
+	URL      string
+	DataHash Hash32
+}
+
+// RelayType identifies the kind of a pool relay.
+type RelayType uint8
+
+const (
+	RelaySingleHostAddr RelayType = 0
+	RelaySingleHostName RelayType = 1
+	RelayMultiHostName  RelayType = 2
+)
+
+// Relay is a stake pool relay.
+type Relay struct {
+	Type    RelayType
+	Port    *uint16
+	IPv4    net.IP
+	IPv6    net.IP
+	DNSName string
+}
+
+// PoolMetadata references the metadata of a stake pool.
+type PoolMetadata struct {
+	URL  string
+	Hash Hash32
+}
+
+// PoolParams are the parameters of a stake pool registration.
+type PoolParams struct {
+	Operator      Hash28
+	VRFKeyHash    Hash32
+	Pledge        uint64
+	Cost          uint64
+	Margin        Rational
+	RewardAccount []byte
+	Owners        []Hash28
+	Relays        []Relay
+	Metadata      *PoolMetadata
+}
+
+// Certificate is a certificate of a transaction. The fields that are set
+// depend on Type:
+//
+//   - Credential is the stake credential of stake certificates, the DRep
+//     credential of DRep certificates and the cold credential of committee
+//     certificates.
+//   - HotCredential is the hot credential of CertCommitteeHotAuthorization.
+//   - PoolKeyHash is the pool of delegations and retirements.
+//   - Deposit is the deposit of Conway registration certificates, or the
+//     refund of unregistration certificates.
+//
+// Genesis key delegation and MIR certificates are not decoded, and are only
+// available as Raw.
+type Certificate struct {
+	Type          CertificateType
+	Credential    address.Credential
+	HotCredential address.Credential
+	PoolKeyHash   Hash28
+	DRep          *DRep
+	Deposit       uint64
+	Epoch         uint64
+	PoolParams    *PoolParams
+	Anchor        *Anchor
+
+	// Raw holds the CBOR encoding of certificates that are not decoded
+	Raw []byte
+}
+
+func (c *Certificate) decode(d *cbor.Decoder) error {
+	raw, err := d.ReadRaw()
+	if err != nil {
+		return err
+	}
+	d = cbor.NewDecoder(raw)
+	n, err := d.ReadArrayHeader()
+	if err != nil {
+		return err
+	}
+	t, err := d.ReadUint()
+	if err != nil {
+		return err
+	}
+	if t > uint64(maxCertificateType) {
+		return invalid("unknown certificate type %d", t)
+	}
+	*c = Certificate{Type: CertificateType(t)}
+	if c.Type == CertGenesisKeyDelegation || c.Type == CertMoveInstantaneousReward {
+		c.Raw = raw
+		return nil
+	}
+
+	want, fields := c.fields()
+	if n != want {
+		return invalid("certificate %d: unexpected array length %d", t, n)
+	}
+	for _, f := range fields {
+		if err := f.read(d); err != nil {
+			return err
+		}
+	}
+	return d.Finish()
+}
+
+func (c Certificate) appendTo(b []byte) []byte {
+	if c.Type == CertGenesisKeyDelegation || c.Type == CertMoveInstantaneousReward {
+		return append(b, c.Raw...)
+	}
+	n, fields := c.fields()
+	b = cbor.AppendArrayHeader(b, n)
+	b = cbor.AppendUint(b, uint64(c.Type))
+	for _, f := range fields {
+		b = f.append(b)
+	}
+	return b
+}
+
+// field reads and appends one element of a certificate.
+type field struct {
+	read   func(d *cbor.Decoder) error
+	append func(b []byte) []byte
+}
+
+// fields returns the array length of the certificate and its elements after
+// the type.
+func (c *Certificate) fields() (int, []field) {
+	cred := field{
+		read: func(d *cbor.Decoder) (err error) {
+			c.Credential, err = readCredential(d)
+			return
+		},
+		append: func(b []byte) []byte { return appendCredential(b, c.Credential) },
+	}
+	pool := field{
+		read: func(d *cbor.Decoder) (err error) {
+			c.PoolKeyHash, err = readHash28(d)
+			return
+		},
+		append: func(b []byte) []byte { return cbor.AppendBytes(b, c.PoolKeyHash[:]) },
+	}
+	deposit := field{
+		read: func(d *cbor.Decoder) (err error) {
+			c.Deposit, err = d.ReadUint()
+			return
+		},
+		append: func(b []byte) []byte { return cbor.AppendUint(b, c.Deposit) },
+	}
+	drep := field{
+		read: func(d *cbor.Decoder) error {
+			v, err := readDRep(d)
+			c.DRep = &v
+			return err
+		},
+		append: func(b []byte) []byte {
+			if c.DRep == nil {
+				return appendDRep(b, DRep{})
+			}
+			return appendDRep(b, *c.DRep)
+		},
+	}
+	anchor := field{
+		read: func(d *cbor.Decoder) (err error) {
+			c.Anchor, err = readOptionalAnchor(d)
+			return
+		},
+		append: func(b []byte) []byte { return appendOptionalAnchor(b, c.Anchor) },
+	}
+
+	switch c.Type {
+	case CertStakeRegistration, CertStakeDeregistration:
+		return 2, []field{cred}
+	case CertStakeDelegation:
+		return 3, []field{cred, pool}
+	case CertPoolRegistration:
+		return 10, []field{{
+			read: func(d *cbor.Decoder) error {
+				c.PoolParams = &PoolParams{}
+				return c.PoolParams.decode(d)
+			},
+			append: func(b []byte) []byte {
+				if c.PoolParams == nil {
+					return PoolParams{}.appendTo(b)
+				}
+				return c.PoolParams.appendTo(b)
+			},
+		}}
+	case CertPoolRetirement:
+		return 3, []field{pool, {
+			read: func(d *cbor.Decoder) (err error) {
+				c.Epoch, err = d.ReadUint()
+				return
+			},
+			append: func(b []byte) []byte { return cbor.AppendUint(b, c.Epoch) },
+		}}
+	case CertRegistration, CertUnregistration:
+		return 3, []field{cred, deposit}
+	case CertVoteDelegation:
+		return 3, []field{cred, drep}
+	case CertStakeVoteDelegation:
+		return 4, []field{cred, pool, drep}
+	case CertStakeRegistrationDelegation:
+		return 4, []field{cred, pool, deposit}
+	case CertVoteRegistrationDelegation:
+		return 4, []field{cred, drep, deposit}
+	case CertStakeVoteRegistrationDelegation:
+		return 5, []field{cred, pool, drep, deposit}
+	case CertCommitteeHotAuthorization:
+		return 3, []field{cred, {
+			read: func(d *cbor.Decoder) (err error) {
+				c.HotCredential, err = readCredential(d)
+				return
+			},
+			append: func(b []byte) []byte { return appendCredential(b, c.HotCredential) },
+		}}
+	case CertCommitteeColdResignation:
+		return 3, []field{cred, anchor}
+	case CertDRepRegistration:
+		return 4, []field{cred, deposit, anchor}
+	case CertDRepUnregistration:
+		return 3, []field{cred, deposit}
+	case CertDRepUpdate:
+		return 3, []field{cred, anchor}
+	}
+	return 0, nil
+}
+
+func readDRep(d *cbor.Decoder) (r DRep, err error) {
+	n, err := readArrayLen(d, "drep", 1, 2)
+	if err != nil {
+		return
+	}
+	t, err := d.ReadUint()
+	if err != nil {
+		return
+	}
+	r.Type = DRepType(t)
+	switch {
+	case (r.Type == DRepKeyHash || r.Type == DRepScriptHash) && n == 2:
+		r.Hash, err = readHash28(d)
+	case (r.Type == DRepAbstain || r.Type == DRepNoConfidence) && n == 1:
+	default:
+		err = invalid("invalid drep type %d", t)
+	}
+	return
+}
+
+func appendDRep(b []byte, r DRep) []byte {
+	if r.Type == DRepAbstain || r.Type == DRepNoConfidence {
+		b = cbor.AppendArrayHeader(b, 1)
+		return cbor.AppendUint(b, uint64(r.Type))
+	}
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendUint(b, uint64(r.Type))
+	return cbor.AppendBytes(b, r.Hash[:])
+}
+
+func readAnchor(d *cbor.Decoder) (a Anchor, err error) {
+	if _, err = readArrayLen(d, "anchor", 2); err != nil {
+		return
+	}
+	if a.URL, err = d.ReadText(); err != nil {
+		return
+	}
+	a.DataHash, err = readHash32(d)
+	return
+}
+
+func appendAnchor(b []byte, a Anchor) []byte {
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendText(b, a.URL)
+	return cbor.AppendBytes(b, a.DataHash[:])
+}
+
+func readOptionalAnchor(d *cbor.Decoder) (*Anchor, error) {
+	if readOptionalNull(d) {
+		return nil, nil
+	}
+	a, err := readAnchor(d)
+	return &a, err
+}
+
+func appendOptionalAnchor(b []byte, a *Anchor) []byte {
+	if a == nil {
+		return cbor.AppendNull(b)
+	}
+	return appendAnchor(b, *a)
+}
+
+// decode reads the pool parameters, which are flattened into the pool
+// registration certificate.
+func (p *PoolParams) decode(d *cbor.Decoder) (err error) {
+	if p.Operator, err = readHash28(d); err != nil {
+		return
+	}
+	if p.VRFKeyHash, err = readHash32(d); err != nil {
+		return
+	}
+	if p.Pledge, err = d.ReadUint(); err != nil {
+		return
+	}
+	if p.Cost, err = d.ReadUint(); err != nil {
+		return
+	}
+	if p.Margin, err = readRational(d); err != nil {
+		return
+	}
+	if p.RewardAccount, err = d.ReadBytes(); err != nil {
+		return
+	}
+	p.Owners = []Hash28{}
+	if err = readSet(d, nil, func(int) error {
+		h, err := readHash28(d)
+		p.Owners = append(p.Owners, h)
+		return err
+	}); err != nil {
+		return
+	}
+	p.Relays = []Relay{}
+	if err = d.ReadArray(func(int) error {
+		r, err := readRelay(d)
+		p.Relays = append(p.Relays, r)
+		return err
+	}); err != nil {
+		return
+	}
+	if readOptionalNull(d) {
+		return nil
+	}
+	if _, err = readArrayLen(d, "pool metadata", 2); err != nil {
+		return
+	}
+	p.Metadata = &PoolMetadata{}
+	if p.Metadata.URL, err = d.ReadText(); err != nil {
+		return
+	}
+	p.Metadata.Hash, err = readHash32(d)
+	return
+}
+
+func (p PoolParams) appendTo(b []byte) []byte {
+	b = cbor.AppendBytes(b, p.Operator[:])
+	b = cbor.AppendBytes(b, p.VRFKeyHash[:])
+	b = cbor.AppendUint(b, p.Pledge)
+	b = cbor.AppendUint(b, p.Cost)
+	b = appendRational(b, p.Margin)
+	b = cbor.AppendBytes(b, p.RewardAccount)
+	b = appendSetHeader(b, len(p.Owners), false)
+	for _, o := range p.Owners {
+		b = cbor.AppendBytes(b, o[:])
+	}
+	b = cbor.AppendArrayHeader(b, len(p.Relays))
+	for _, r := range p.Relays {
+		b = appendRelay(b, r)
+	}
+	if p.Metadata == nil {
+		return cbor.AppendNull(b)
+	}
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendText(b, p.Metadata.URL)
+	return cbor.AppendBytes(b, p.Metadata.Hash[:])
+}
+
+func readRelay(d *cbor.Decoder) (r Relay, err error) {
+	n, err := readArrayLen(d, "relay", 2, 3, 4)
+	if err != nil {
+		return
+	}
+	t, err := d.ReadUint()
+	if err != nil {
+		return
+	}
+	r.Type = RelayType(t)
+	readPort := func() error {
+		if readOptionalNull(d) {
+			return nil
+		}
+		p, err := d.ReadUint()
+		if err != nil {
+			return err
+		}
+		if p > 0xffff {
+			return invalid("invalid relay port %d", p)
+		}
+		port := uint16(p)
+		r.Port = &port
+		return nil
+	}
+	readIP := func(size int) (net.IP, error) {
+		if readOptionalNull(d) {
+			return nil, nil
+		}
+		ip := make(net.IP, size)
+		return ip, readFixedBytes(d, ip)
+	}
+	switch {
+	case r.Type == RelaySingleHostAddr && n == 4:
+		if err = readPort(); err != nil {
+			return
+		}
+		if r.IPv4, err = readIP(net.IPv4len); err != nil {
+			return
+		}
+		r.IPv6, err = readIP(net.IPv6len)
+	case r.Type == RelaySingleHostName && n == 3:
+		if err = readPort(); err != nil {
+			return
+		}
+		r.DNSName, err = d.ReadText()
+	case r.Type == RelayMultiHostName && n == 2:
+		r.DNSName, err = d.ReadText()
+	default:
+		err = invalid("invalid relay type %d", t)
+	}
+	return
+}
+
+func appendRelay(b []byte, r Relay) []byte {
+	appendPort := func(b []byte) []byte {
+		if r.Port == nil {
+			return cbor.AppendNull(b)
+		}
+		return cbor.AppendUint(b, uint64(*r.Port))
+	}
+	appendIP := func(b []byte, ip net.IP) []byte {
+		if ip == nil {
+			return cbor.AppendNull(b)
+		}
+		return cbor.AppendBytes(b, ip)
+	}
+	switch r.Type {
+	case RelaySingleHostAddr:
+		b = cbor.AppendArrayHeader(b, 4)
+		b = cbor.AppendUint(b, uint64(r.Type))
+		b = appendPort(b)
+		b = appendIP(b, r.IPv4.To4())
+		return appendIP(b, r.IPv6)
+	case RelaySingleHostName:
+		b = cbor.AppendArrayHeader(b, 3)
+		b = cbor.AppendUint(b, uint64(r.Type))
+		b = appendPort(b)
+		return cbor.AppendText(b, r.DNSName)
+	default:
+		b = cbor.AppendArrayHeader(b, 2)
+		b = cbor.AppendUint(b, uint64(r.Type))
+		return cbor.AppendText(b, r.DNSName)
+	}
+}
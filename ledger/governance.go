@@ -0,0 +1,233 @@
+package ledger
+
+import (
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+)
+
+// VoterType identifies the kind of a voter.
+type VoterType uint8
+
+const (
+	VoterCommitteeHotKeyHash    VoterType = 0
+	VoterCommitteeHotScriptHash VoterType = 1
+	VoterDRepKeyHash            VoterType = 2
+	VoterDRepScriptHash         VoterType = 3
+	VoterStakePoolKeyHash       VoterType = 4
+)
+
+// Voter is a constitutional committee member, DRep or stake pool casting a
+// vote.
+type Voter struct {
+	Type VoterType
+	Hash Hash28
+}
+
+// Vote is the choice of a voter.
+type Vote uint8
+
+const (
+	VoteNo      Vote = 0
+	VoteYes     Vote = 1
+	VoteAbstain Vote = 2
+)
+
+// GovActionID identifies a governance action by the transaction that
+// proposed it and its index in that transaction.
+type GovActionID struct {
+	TxID  Hash32
+	Index uint64
+}
+
+// VotingProcedure is a vote cast by a voter on a governance action.
+type VotingProcedure struct {
+	Voter    Voter
+	ActionID GovActionID
+	Vote     Vote
+	Anchor   *Anchor
+}
+
+// GovActionType identifies the kind of a governance action.
+type GovActionType uint8
+
+const (
+	GovActionParameterChange     GovActionType = 0
+	GovActionHardForkInitiation  GovActionType = 1
+	GovActionTreasuryWithdrawals GovActionType = 2
+	GovActionNoConfidence        GovActionType = 3
+	GovActionUpdateCommittee     GovActionType = 4
+	GovActionNewConstitution     GovActionType = 5
+	GovActionInfo                GovActionType = 6
+)
+
+// GovAction is a governance action. Only its type and the action it
+// follows are decoded; the full action is available as Raw, which is also
+// used to encode it.
+type GovAction struct {
+	Type GovActionType
+
+	// PrevActionID is the last enacted action of the same purpose, for
+	// actions that reference it.
+	PrevActionID *GovActionID
+
+	Raw []byte
+}
+
+// ProposalProcedure proposes a governance action.
+type ProposalProcedure struct {
+	Deposit       uint64
+	RewardAccount []byte
+	Action        GovAction
+	Anchor        Anchor
+}
+
+func readGovActionID(d *cbor.Decoder) (id GovActionID, err error) {
+	if _, err = readArrayLen(d, "governance action id", 2); err != nil {
+		return
+	}
+	if id.TxID, err = readHash32(d); err != nil {
+		return
+	}
+	id.Index, err = d.ReadUint()
+	return
+}
+
+func appendGovActionID(b []byte, id GovActionID) []byte {
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendBytes(b, id.TxID[:])
+	return cbor.AppendUint(b, id.Index)
+}
+
+// readVotingProcedures reads the nested voter => action => vote map into a
+// flat list, in encoding order.
+func readVotingProcedures(d *cbor.Decoder) ([]VotingProcedure, error) {
+	out := []VotingProcedure{}
+	err := d.ReadMap(func(int) error {
+		if _, err := readArrayLen(d, "voter", 2); err != nil {
+			return err
+		}
+		t, err := d.ReadUint()
+		if err != nil {
+			return err
+		}
+		if t > uint64(VoterStakePoolKeyHash) {
+			return invalid("unknown voter type %d", t)
+		}
+		voter := Voter{Type: VoterType(t)}
+		if voter.Hash, err = readHash28(d); err != nil {
+			return err
+		}
+		return d.ReadMap(func(int) error {
+			p := VotingProcedure{Voter: voter}
+			if p.ActionID, err = readGovActionID(d); err != nil {
+				return err
+			}
+			if _, err := readArrayLen(d, "voting procedure", 2); err != nil {
+				return err
+			}
+			vote, err := d.ReadUint()
+			if err != nil {
+				return err
+			}
+			if vote > uint64(VoteAbstain) {
+				return invalid("unknown vote %d", vote)
+			}
+			p.Vote = Vote(vote)
+			if p.Anchor, err = readOptionalAnchor(d); err != nil {
+				return err
+			}
+			out = append(out, p)
+			return nil
+		})
+	})
+	return out, err
+}
+
+// appendVotingProcedures groups procedures by voter, in order of first
+// appearance.
+func appendVotingProcedures(b []byte, procedures []VotingProcedure) []byte {
+	var voters []Voter
+	byVoter := map[Voter][]VotingProcedure{}
+	for _, p := range procedures {
+		if _, ok := byVoter[p.Voter]; !ok {
+			voters = append(voters, p.Voter)
+		}
+		byVoter[p.Voter] = append(byVoter[p.Voter], p)
+	}
+	b = cbor.AppendMapHeader(b, len(voters))
+	for _, v := range voters {
+		b = cbor.AppendArrayHeader(b, 2)
+		b = cbor.AppendUint(b, uint64(v.Type))
+		b = cbor.AppendBytes(b, v.Hash[:])
+		b = cbor.AppendMapHeader(b, len(byVoter[v]))
+		for _, p := range byVoter[v] {
+			b = appendGovActionID(b, p.ActionID)
+			b = cbor.AppendArrayHeader(b, 2)
+			b = cbor.AppendUint(b, uint64(p.Vote))
+			b = appendOptionalAnchor(b, p.Anchor)
+		}
+	}
+	return b
+}
+
+func readGovAction(d *cbor.Decoder) (a GovAction, err error) {
+	if a.Raw, err = d.ReadRaw(); err != nil {
+		return
+	}
+	d = cbor.NewDecoder(a.Raw)
+	n, err := d.ReadArrayHeader()
+	if err != nil {
+		return
+	}
+	if n < 1 {
+		return a, invalid("empty governance action")
+	}
+	t, err := d.ReadUint()
+	if err != nil {
+		return
+	}
+	if t > uint64(GovActionInfo) {
+		return a, invalid("unknown governance action %d", t)
+	}
+	a.Type = GovActionType(t)
+	switch a.Type {
+	case GovActionParameterChange, GovActionHardForkInitiation, GovActionNoConfidence,
+		GovActionUpdateCommittee, GovActionNewConstitution:
+		if n < 2 {
+			return a, invalid("governance action %d: missing previous action", t)
+		}
+		if readOptionalNull(d) {
+			return
+		}
+		id, err := readGovActionID(d)
+		if err != nil {
+			return a, err
+		}
+		a.PrevActionID = &id
+	}
+	return
+}
+
+func readProposalProcedure(d *cbor.Decoder) (p ProposalProcedure, err error) {
+	if _, err = readArrayLen(d, "proposal procedure", 4); err != nil {
+		return
+	}
+	if p.Deposit, err = d.ReadUint(); err != nil {
+		return
+	}
+	if p.RewardAccount, err = d.ReadBytes(); err != nil {
+		return
+	}
+	if p.Action, err = readGovAction(d); err != nil {
+		return
+	}
+	p.Anchor, err = readAnchor(d)
+	return
+}
+
+func appendProposalProcedure(b []byte, p ProposalProcedure) []byte {
+	b = cbor.AppendArrayHeader(b, 4)
+	b = cbor.AppendUint(b, p.Deposit)
+	b = cbor.AppendBytes(b, p.RewardAccount)
+	b = append(b, p.Action.Raw...)
+	return appendAnchor(b, p.Anchor)
+}
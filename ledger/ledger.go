@@ -0,0 +1,246 @@
+// Package ledger decodes and encodes Cardano transactions in their CBOR
+// representation, from the Shelley era to the Conway era.
+//
+// Decoded items remember their original encoding. When an item is encoded
+// again without having been modified, its original bytes are reused, so
+// hashes computed on decoded transactions match the ones computed by the
+// node even when the encoding was not canonical. Modified items, and items
+// built from scratch, are encoded canonically.
+//
+// Byron transactions are not supported.
+package ledger
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+	"golang.org/x/crypto/blake2b"
+)
+
+// ErrInvalidTransaction is returned when decoding data that is not a valid
+// transaction or transaction component.
+var ErrInvalidTransaction = errors.New("ledger: invalid transaction")
+
+// Hash28 is a blake2b-224 hash, used for key hashes, script hashes and
+// policy IDs.
+type Hash28 [28]byte
+
+// Hash32 is a blake2b-256 hash, used for transaction IDs, datum hashes and
+// metadata hashes.
+type Hash32 [32]byte
+
+// String returns the hex encoding of h.
+func (h Hash28) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// String returns the hex encoding of h.
+func (h Hash32) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// Hash28FromHex parses a hex encoded Hash28.
+func Hash28FromHex(s string) (h Hash28, err error) {
+	err = decodeHashHex(h[:], s)
+	return
+}
+
+// Hash32FromHex parses a hex encoded Hash32.
+func Hash32FromHex(s string) (h Hash32, err error) {
+	err = decodeHashHex(h[:], s)
+	return
+}
+
+func decodeHashHex(dst []byte, s string) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return fmt.Errorf("ledger: expected %d bytes hash, got %d", len(dst), len(b))
+	}
+	copy(dst, b)
+	return nil
+}
+
+// Blake2b224 returns the blake2b-224 hash of data.
+func Blake2b224(data []byte) Hash28 {
+	h, _ := blake2b.New(28, nil)
+	h.Write(data)
+	var out Hash28
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// Blake2b256 returns the blake2b-256 hash of data.
+func Blake2b256(data []byte) Hash32 {
+	return blake2b.Sum256(data)
+}
+
+// Rational is a non negative fraction, such as a pool margin.
+type Rational struct {
+	Num uint64
+	Den uint64
+}
+
+// preserved remembers the original encoding of a decoded item, along with
+// the canonical encoding of its fields at decode time. An item whose fields
+// still encode to the same canonical bytes is unchanged and is encoded with
+// its original bytes.
+type preserved struct {
+	raw   []byte
+	canon []byte
+}
+
+func (p preserved) encode(fresh []byte) []byte {
+	if p.raw != nil && bytes.Equal(fresh, p.canon) {
+		return p.raw
+	}
+	return fresh
+}
+
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidTransaction, fmt.Sprintf(format, args...))
+}
+
+func wrap(what string, err error) error {
+	if err == nil || errors.Is(err, ErrInvalidTransaction) {
+		return err
+	}
+	return fmt.Errorf("%w: %s: %v", ErrInvalidTransaction, what, err)
+}
+
+func readHash28(d *cbor.Decoder) (h Hash28, err error) {
+	b, err := d.ReadBytes()
+	if err != nil {
+		return h, err
+	}
+	if len(b) != len(h) {
+		return h, invalid("expected 28 bytes hash, got %d", len(b))
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+func readHash32(d *cbor.Decoder) (h Hash32, err error) {
+	b, err := d.ReadBytes()
+	if err != nil {
+		return h, err
+	}
+	if len(b) != len(h) {
+		return h, invalid("expected 32 bytes hash, got %d", len(b))
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+func readFixedBytes(d *cbor.Decoder, dst []byte) error {
+	b, err := d.ReadBytes()
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return invalid("expected %d bytes, got %d", len(dst), len(b))
+	}
+	copy(dst, b)
+	return nil
+}
+
+// readSet reads a set, which since Conway may be tagged with 258.
+func readSet(d *cbor.Decoder, tagged *bool, fn func(i int) error) error {
+	if d.SkipTag(cbor.TagSet) && tagged != nil {
+		*tagged = true
+	}
+	return d.ReadArray(fn)
+}
+
+func appendSetHeader(b []byte, n int, untagged bool) []byte {
+	if !untagged {
+		b = cbor.AppendTag(b, cbor.TagSet)
+	}
+	return cbor.AppendArrayHeader(b, n)
+}
+
+// readArrayLen reads an array header and checks its length against the
+// accepted lengths.
+func readArrayLen(d *cbor.Decoder, what string, lengths ...int) (int, error) {
+	n, err := d.ReadArrayHeader()
+	if err != nil {
+		return 0, err
+	}
+	for _, l := range lengths {
+		if n == l {
+			return n, nil
+		}
+	}
+	return 0, invalid("%s: unexpected array length %d", what, n)
+}
+
+func readOptionalNull(d *cbor.Decoder) bool {
+	if d.PeekNull() {
+		_ = d.ReadNull()
+		return true
+	}
+	return false
+}
+
+func readRational(d *cbor.Decoder) (r Rational, err error) {
+	d.SkipTag(cbor.TagRational)
+	if _, err = readArrayLen(d, "rational", 2); err != nil {
+		return
+	}
+	if r.Num, err = d.ReadUint(); err != nil {
+		return
+	}
+	r.Den, err = d.ReadUint()
+	return
+}
+
+func appendRational(b []byte, r Rational) []byte {
+	b = cbor.AppendTag(b, cbor.TagRational)
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendUint(b, r.Num)
+	return cbor.AppendUint(b, r.Den)
+}
+
+func readCredential(d *cbor.Decoder) (c address.Credential, err error) {
+	if _, err = readArrayLen(d, "credential", 2); err != nil {
+		return
+	}
+	t, err := d.ReadUint()
+	if err != nil {
+		return
+	}
+	if t > 1 {
+		return c, invalid("unknown credential type %d", t)
+	}
+	c.Type = address.CredentialType(t)
+	c.Hash, err = readHash28(d)
+	return
+}
+
+func appendCredential(b []byte, c address.Credential) []byte {
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendUint(b, uint64(c.Type))
+	return cbor.AppendBytes(b, c.Hash[:])
+}
+
+// sortedKeys returns the keys of m in canonical CBOR order, by their
+// encoding.
+func sortedKeys[K comparable, V any](m map[K]V, enc func(K) []byte) []K {
+	keys := make([]K, 0, len(m))
+	encoded := make(map[K][]byte, len(m))
+	for k := range m {
+		keys = append(keys, k)
+		encoded[k] = enc(k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(encoded[keys[i]], encoded[keys[j]]) < 0
+	})
+	return keys
+}
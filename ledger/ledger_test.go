@@ -0,0 +1,409 @@
+package ledger_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+const testdata = "../testdata"
+
+func loadCBOR(t *testing.T, path ...string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(append([]string{testdata}, path...)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := blockfrost.TransactionCBOR{}
+	if err := json.Unmarshal(data, &tc); err != nil {
+		t.Fatal(err)
+	}
+	b, err := hex.DecodeString(tc.Cbor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func hash28(b byte) ledger.Hash28 {
+	var h ledger.Hash28
+	for i := range h {
+		h[i] = b
+	}
+	return h
+}
+
+func hash32(b byte) ledger.Hash32 {
+	var h ledger.Hash32
+	for i := range h {
+		h[i] = b
+	}
+	return h
+}
+
+func testRoundTrip(t *testing.T, data []byte) *ledger.Transaction {
+	t.Helper()
+	tx, err := ledger.DecodeTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tx.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("round trip mismatch\nexpected %x\ngot      %x", data, got)
+	}
+	return tx
+}
+
+func TestDecodeShelleyTransactions(t *testing.T) {
+	tests := []struct {
+		path []string
+		hash string
+		fee  uint64
+	}{
+		{
+			[]string{"transactioncborintegration.golden"},
+			"6e5f825c82c1c6d6b77f2a14092f3b78c8f1b66db6f4cf8caec1555b6f967b3b",
+			182485,
+		},
+		{
+			[]string{"json", "transactions", "transaction_cbor.json"},
+			"b9436af1e6337a59c026fc3963a41efa5ab7b074feb8ddcce4c7e731409dfad6",
+			200000,
+		},
+	}
+	for _, tt := range tests {
+		tx := testRoundTrip(t, loadCBOR(t, tt.path...))
+		if got := tx.Hash().String(); got != tt.hash {
+			t.Fatalf("expected hash %s got %s", tt.hash, got)
+		}
+		if tx.Body.Fee != tt.fee {
+			t.Fatalf("expected fee %d got %d", tt.fee, tx.Body.Fee)
+		}
+		if tx.Invalid || tx.AuxiliaryData != nil {
+			t.Fatal("unexpected is_valid or auxiliary data")
+		}
+		if len(tx.WitnessSet.BootstrapWitnesses) == 0 && len(tx.WitnessSet.VKeyWitnesses) == 0 {
+			t.Fatal("expected witnesses")
+		}
+		for _, o := range tx.Body.Outputs {
+			if _, err := o.ParseAddress(); err != nil {
+				t.Fatalf("invalid output address: %v", err)
+			}
+		}
+	}
+}
+
+func TestTransactionCBORDecode(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(testdata, "transactioncborintegration.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := blockfrost.TransactionCBOR{}
+	if err := json.Unmarshal(data, &tc); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := tc.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.Body.Inputs) != 2 || len(tx.Body.Outputs) != 2 {
+		t.Fatalf("expected 2 inputs and 2 outputs got %d and %d", len(tx.Body.Inputs), len(tx.Body.Outputs))
+	}
+	addr, err := tx.Body.Outputs[0].ParseAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.Kind() != address.KindByron {
+		t.Fatalf("expected byron address got %s", addr.Kind())
+	}
+	if tx.Hex() != tc.Cbor {
+		t.Fatal("expected Hex to return the original encoding")
+	}
+}
+
+// tx_cbor_conway.json is a synthetic transaction with placeholder hashes and
+// keys, built to set every Conway body field at once. It is not on chain:
+// its expected hash is the Blake2b-256 of its body bytes, computed outside of
+// this package. Hashes of on-chain transactions are checked by
+// TestDecodeShelleyTransactions.
+func TestDecodeConwayTransaction(t *testing.T) {
+	data := loadCBOR(t, "json", "transactions", "tx_cbor_conway.json")
+	tx := testRoundTrip(t, data)
+	body := tx.Body
+
+	if got := tx.Hash().String(); got != "a75d043e184ec03f1f0342ebfe1a895b8942d507c380b462bec91e3a292f2f4a" {
+		t.Fatalf("unexpected hash %s", got)
+	}
+	if len(body.Inputs) != 2 || body.Inputs[1].String() != hash32(0xaa).String()+"#1" {
+		t.Fatalf("unexpected inputs %v", body.Inputs)
+	}
+	if body.Fee != 1000 || *body.TTL != 150000000 || *body.ValidityIntervalStart != 140000000 {
+		t.Fatalf("unexpected fee or validity interval")
+	}
+
+	// Outputs
+	if len(body.Outputs) != 2 {
+		t.Fatalf("expected 2 outputs got %d", len(body.Outputs))
+	}
+	out := body.Outputs[0]
+	if out.Amount.Coin != 5000000 || out.Amount.Assets[hash28(0x77)]["tok"] != 10 {
+		t.Fatalf("unexpected amount %+v", out.Amount)
+	}
+	if hex.EncodeToString(out.Datum) != "d8799f4100ff" || out.DatumHash != nil {
+		t.Fatalf("unexpected inline datum %x", out.Datum)
+	}
+	if out.ScriptRef == nil || out.ScriptRef.Type != ledger.ScriptPlutusV3 {
+		t.Fatal("expected plutus v3 reference script")
+	}
+	if got := out.ScriptRef.Hash().String(); got != "4c7c6b75b8cf0398629bc8a6dcd527b1a36c12b476760302892aac71" {
+		t.Fatalf("unexpected script hash %s", got)
+	}
+	legacy := body.Outputs[1]
+	if legacy.Amount.Coin != 2000000 || legacy.DatumHash == nil || *legacy.DatumHash != hash32(0xdd) {
+		t.Fatalf("unexpected legacy output %+v", legacy)
+	}
+
+	// Certificates
+	wantTypes := []ledger.CertificateType{
+		ledger.CertStakeRegistration,
+		ledger.CertRegistration,
+		ledger.CertVoteDelegation,
+		ledger.CertStakeVoteRegistrationDelegation,
+		ledger.CertCommitteeHotAuthorization,
+		ledger.CertDRepRegistration,
+		ledger.CertDRepUpdate,
+		ledger.CertPoolRegistration,
+		ledger.CertPoolRetirement,
+	}
+	if len(body.Certificates) != len(wantTypes) {
+		t.Fatalf("expected %d certificates got %d", len(wantTypes), len(body.Certificates))
+	}
+	for i, c := range body.Certificates {
+		if c.Type != wantTypes[i] {
+			t.Fatalf("certificate %d: expected type %d got %d", i, wantTypes[i], c.Type)
+		}
+	}
+	certs := body.Certificates
+	if certs[1].Deposit != 2000000 || certs[1].Credential.Hash != hash28(0x22) {
+		t.Fatalf("unexpected registration %+v", certs[1])
+	}
+	if certs[2].DRep == nil || certs[2].DRep.Type != ledger.DRepAbstain {
+		t.Fatalf("unexpected vote delegation %+v", certs[2])
+	}
+	if certs[3].PoolKeyHash != hash28(0x33) || certs[3].DRep.Hash != hash28(0x44) || certs[3].Deposit != 2000000 {
+		t.Fatalf("unexpected stake vote registration delegation %+v", certs[3])
+	}
+	if certs[4].HotCredential.Type != address.CredentialScript || certs[4].HotCredential.Hash != hash28(0x66) {
+		t.Fatalf("unexpected hot credential %+v", certs[4])
+	}
+	if certs[5].Anchor == nil || certs[5].Anchor.URL != "https://example.com/a.json" || certs[5].Deposit != 500000000 {
+		t.Fatalf("unexpected drep registration %+v", certs[5])
+	}
+	if certs[6].Anchor != nil || certs[6].Credential.Type != address.CredentialScript {
+		t.Fatalf("unexpected drep update %+v", certs[6])
+	}
+	pp := certs[7].PoolParams
+	if pp == nil || pp.Margin != (ledger.Rational{Num: 1, Den: 50}) || len(pp.Owners) != 1 || pp.Metadata == nil {
+		t.Fatalf("unexpected pool params %+v", pp)
+	}
+	if len(pp.Relays) != 3 || pp.Relays[0].IPv4.String() != "1.2.3.4" || *pp.Relays[0].Port != 3001 ||
+		pp.Relays[1].DNSName != "relay.example.com" || pp.Relays[2].Type != ledger.RelayMultiHostName {
+		t.Fatalf("unexpected relays %+v", pp.Relays)
+	}
+	if certs[8].Epoch != 500 {
+		t.Fatalf("unexpected retirement epoch %d", certs[8].Epoch)
+	}
+
+	// Other body fields
+	if len(body.Withdrawals) != 1 || body.Withdrawals[0].Amount != 1234 {
+		t.Fatalf("unexpected withdrawals %+v", body.Withdrawals)
+	}
+	if body.Mint[hash28(0x77)]["burn"] != -5 || body.Mint[hash28(0x77)]["tok"] != 10 {
+		t.Fatalf("unexpected mint %+v", body.Mint)
+	}
+	if len(body.Collateral) != 1 || len(body.RequiredSigners) != 1 || *body.NetworkID != 1 {
+		t.Fatal("unexpected collateral, required signers or network id")
+	}
+	if body.CollateralReturn == nil || body.CollateralReturn.Amount.Coin != 4000000 || *body.TotalCollateral != 1000000 {
+		t.Fatal("unexpected collateral return")
+	}
+	if len(body.ReferenceInputs) != 1 || body.ReferenceInputs[0].TxID != hash32(0xbb) {
+		t.Fatal("unexpected reference inputs")
+	}
+	if *body.CurrentTreasuryValue != 12345 || *body.Donation != 678 {
+		t.Fatal("unexpected treasury value or donation")
+	}
+
+	// Governance
+	if len(body.VotingProcedures) != 2 {
+		t.Fatalf("expected 2 votes got %d", len(body.VotingProcedures))
+	}
+	vote := body.VotingProcedures[0]
+	if vote.Voter.Type != ledger.VoterDRepKeyHash || vote.Vote != ledger.VoteYes || vote.Anchor == nil {
+		t.Fatalf("unexpected vote %+v", vote)
+	}
+	vote = body.VotingProcedures[1]
+	if vote.Voter.Type != ledger.VoterStakePoolKeyHash || vote.Vote != ledger.VoteNo || vote.ActionID.Index != 1 {
+		t.Fatalf("unexpected vote %+v", vote)
+	}
+	if len(body.ProposalProcedures) != 2 {
+		t.Fatalf("expected 2 proposals got %d", len(body.ProposalProcedures))
+	}
+	if p := body.ProposalProcedures[0]; p.Action.Type != ledger.GovActionInfo || p.Deposit != 100000000000 {
+		t.Fatalf("unexpected proposal %+v", p)
+	}
+	p := body.ProposalProcedures[1]
+	if p.Action.Type != ledger.GovActionParameterChange || p.Action.PrevActionID == nil || p.Action.PrevActionID.Index != 3 {
+		t.Fatalf("unexpected proposal %+v", p)
+	}
+
+	// Witnesses
+	ws := tx.WitnessSet
+	if len(ws.VKeyWitnesses) != 1 || len(ws.NativeScripts) != 1 || len(ws.PlutusData) != 1 || len(ws.PlutusV3Scripts) != 1 {
+		t.Fatalf("unexpected witness set %+v", ws)
+	}
+	if len(ws.Redeemers) != 2 || ws.Redeemers[1].Tag != ledger.RedeemerMint || ws.Redeemers[1].ExUnits.Steps != 4000 {
+		t.Fatalf("unexpected redeemers %+v", ws.Redeemers)
+	}
+	scripts := ws.Scripts()
+	if len(scripts) != 2 || scripts[0].Hash().String() != "5334c8c55ec6518f2e262da617d3170bf0722f43813d64dbaf0ff0b9" {
+		t.Fatalf("unexpected scripts %+v", scripts)
+	}
+
+	// Auxiliary data
+	if tx.Invalid || tx.AuxiliaryData == nil {
+		t.Fatal("expected valid transaction with auxiliary data")
+	}
+	if tx.AuxiliaryData.Hash() != *body.AuxiliaryDataHash {
+		t.Fatalf("expected auxiliary data hash %s got %s", body.AuxiliaryDataHash, tx.AuxiliaryData.Hash())
+	}
+	if _, ok := tx.AuxiliaryData.Metadata[674]; !ok || len(tx.AuxiliaryData.NativeScripts) != 1 {
+		t.Fatalf("unexpected auxiliary data %+v", tx.AuxiliaryData)
+	}
+}
+
+func TestModifiedWitnessSetKeepsBody(t *testing.T) {
+	data := loadCBOR(t, "json", "transactions", "tx_cbor_conway.json")
+	tx, err := ledger.DecodeTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := tx.Body.MarshalCBOR()
+	redeemers := tx.WitnessSet.RedeemersCBOR()
+	datums := tx.WitnessSet.PlutusDataCBOR()
+
+	tx.WitnessSet.VKeyWitnesses = append(tx.WitnessSet.VKeyWitnesses, ledger.VKeyWitness{})
+	encoded, err := tx.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(encoded, body) {
+		t.Fatal("expected the body encoding to be preserved")
+	}
+	decoded, err := ledger.DecodeTransaction(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.WitnessSet.VKeyWitnesses) != 2 {
+		t.Fatalf("expected 2 witnesses got %d", len(decoded.WitnessSet.VKeyWitnesses))
+	}
+	if decoded.Hash() != tx.Hash() {
+		t.Fatal("expected the transaction hash to be unchanged")
+	}
+	if !bytes.Equal(decoded.WitnessSet.RedeemersCBOR(), redeemers) || !bytes.Equal(decoded.WitnessSet.PlutusDataCBOR(), datums) {
+		t.Fatal("expected redeemers and datums encoding to be preserved")
+	}
+}
+
+func TestModifiedBodyIsReencoded(t *testing.T) {
+	data := loadCBOR(t, "json", "transactions", "tx_cbor_conway.json")
+	tx, err := ledger.DecodeTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := tx.Hash()
+	tx.Body.Fee = 2000
+	if tx.Hash() == hash {
+		t.Fatal("expected the hash to change with the fee")
+	}
+	decoded, err := ledger.DecodeTransaction(mustMarshal(t, tx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Body.Fee != 2000 || decoded.Hash() != tx.Hash() {
+		t.Fatalf("unexpected re-encoded body, fee %d", decoded.Body.Fee)
+	}
+	// The fee is not canonically encoded in the original body, which is
+	// used again once the body is back to its decoded state.
+	tx.Body.Fee = 1000
+	if tx.Hash() != hash {
+		t.Fatal("expected the original encoding to be restored")
+	}
+}
+
+func mustMarshal(t *testing.T, tx *ledger.Transaction) []byte {
+	t.Helper()
+	b, err := tx.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestNewTransaction(t *testing.T) {
+	addr, err := address.Parse("addr_test1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &ledger.Transaction{
+		Body: ledger.TransactionBody{
+			Inputs:  []ledger.TransactionInput{{TxID: hash32(1), Index: 0}},
+			Outputs: []ledger.TransactionOutput{ledger.NewTransactionOutput(addr, ledger.Value{Coin: 1000000})},
+			Fee:     170000,
+		},
+	}
+	decoded, err := ledger.DecodeTransaction(mustMarshal(t, tx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Invalid || decoded.Body.Fee != 170000 || decoded.Hash() != tx.Hash() {
+		t.Fatal("unexpected decoded transaction")
+	}
+	got, err := decoded.Body.Outputs[0].ParseAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(addr) {
+		t.Fatalf("expected %s got %s", addr, got)
+	}
+}
+
+func TestDecodeInvalidTransaction(t *testing.T) {
+	tests := []string{
+		"",
+		"80",
+		// Missing fee
+		"84a200800180a0f5f6",
+		// Trailing data
+		"84a300800180020aa0f5f600",
+		// Unknown body key
+		"84a4008001800200181e00a0f5f6",
+	}
+	for _, s := range tests {
+		data, _ := hex.DecodeString(s)
+		if _, err := ledger.DecodeTransaction(data); !errors.Is(err, ledger.ErrInvalidTransaction) {
+			t.Fatalf("%q: expected %v got %v", s, ledger.ErrInvalidTransaction, err)
+		}
+	}
+}
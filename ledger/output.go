@@ -0,0 +1,372 @@
+package ledger
+
+import (
+	"encoding/hex"
+
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+)
+
+// AssetName is the raw name of a native asset.
+type AssetName string
+
+// Hex returns the hex encoding of the name.
+func (n AssetName) Hex() string {
+	return hex.EncodeToString([]byte(n))
+}
+
+// MultiAsset holds quantities of native assets grouped by policy ID.
+type MultiAsset map[Hash28]map[AssetName]uint64
+
+// Mint holds quantities of native assets minted, or burnt when negative,
+// grouped by policy ID.
+type Mint map[Hash28]map[AssetName]int64
+
+// Value is an amount of lovelace and native assets.
+type Value struct {
+	Coin   uint64
+	Assets MultiAsset
+}
+
+// ScriptType identifies the language of a script.
+type ScriptType uint8
+
+const (
+	ScriptNative   ScriptType = 0
+	ScriptPlutusV1 ScriptType = 1
+	ScriptPlutusV2 ScriptType = 2
+	ScriptPlutusV3 ScriptType = 3
+)
+
+// Script is a native or Plutus script. For native scripts, Bytes holds the
+// CBOR encoding of the script. For Plutus scripts, it holds the serialized
+// script as found in the witness set.
+type Script struct {
+	Type  ScriptType
+	Bytes []byte
+}
+
+// Hash returns the hash of the script, which is also the policy ID of
+// assets it controls and the credential of addresses it locks.
+func (s Script) Hash() Hash28 {
+	return Blake2b224(append([]byte{byte(s.Type)}, s.Bytes...))
+}
+
+// TransactionOutput is an output of a transaction.
+type TransactionOutput struct {
+	// Raw address, as parsed by ParseAddress
+	Address []byte
+
+	Amount Value
+
+	// Hash of the datum attached to the output, if any
+	DatumHash *Hash32
+
+	// CBOR encoding of the inline datum attached to the output, if any
+	Datum []byte
+
+	// Reference script attached to the output, if any
+	ScriptRef *Script
+
+	// legacy is set for outputs decoded from the pre-Babbage array format
+	legacy bool
+	orig   preserved
+}
+
+// NewTransactionOutput returns an output paying amount to addr.
+func NewTransactionOutput(addr address.Address, amount Value) TransactionOutput {
+	return TransactionOutput{Address: addr.Bytes(), Amount: amount}
+}
+
+// ParseAddress parses the address of the output.
+func (o TransactionOutput) ParseAddress() (address.Address, error) {
+	return address.FromBytes(o.Address)
+}
+
+// MarshalCBOR returns the CBOR encoding of o.
+func (o TransactionOutput) MarshalCBOR() ([]byte, error) {
+	return o.orig.encode(o.appendFields(nil)), nil
+}
+
+// UnmarshalCBOR decodes o from its CBOR encoding.
+func (o *TransactionOutput) UnmarshalCBOR(data []byte) error {
+	d := cbor.NewDecoder(data)
+	if err := o.decode(d); err != nil {
+		return wrap("output", err)
+	}
+	return wrap("output", d.Finish())
+}
+
+func (o *TransactionOutput) decode(d *cbor.Decoder) error {
+	start := d.Pos()
+	*o = TransactionOutput{}
+	major, err := d.PeekMajor()
+	if err != nil {
+		return err
+	}
+	if major == cbor.MajorArray {
+		if err := o.decodeLegacy(d); err != nil {
+			return err
+		}
+	} else if err := o.decodeMap(d); err != nil {
+		return err
+	}
+	o.orig = preserved{raw: d.Data()[start:d.Pos()], canon: o.appendFields(nil)}
+	return nil
+}
+
+func (o *TransactionOutput) decodeLegacy(d *cbor.Decoder) error {
+	n, err := readArrayLen(d, "output", 2, 3)
+	if err != nil {
+		return err
+	}
+	o.legacy = true
+	if o.Address, err = d.ReadBytes(); err != nil {
+		return err
+	}
+	if o.Amount, err = readValue(d); err != nil {
+		return err
+	}
+	if n == 3 {
+		h, err := readHash32(d)
+		if err != nil {
+			return err
+		}
+		o.DatumHash = &h
+	}
+	return nil
+}
+
+func (o *TransactionOutput) decodeMap(d *cbor.Decoder) error {
+	return d.ReadMap(func(int) error {
+		key, err := d.ReadUint()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case 0:
+			o.Address, err = d.ReadBytes()
+		case 1:
+			o.Amount, err = readValue(d)
+		case 2:
+			err = o.decodeDatumOption(d)
+		case 3:
+			var s Script
+			if s, err = readScriptRef(d); err == nil {
+				o.ScriptRef = &s
+			}
+		default:
+			err = invalid("output: unknown key %d", key)
+		}
+		return err
+	})
+}
+
+func (o *TransactionOutput) decodeDatumOption(d *cbor.Decoder) error {
+	if _, err := readArrayLen(d, "datum option", 2); err != nil {
+		return err
+	}
+	t, err := d.ReadUint()
+	if err != nil {
+		return err
+	}
+	switch t {
+	case 0:
+		h, err := readHash32(d)
+		if err != nil {
+			return err
+		}
+		o.DatumHash = &h
+	case 1:
+		if o.Datum, err = readEncodedCBOR(d); err != nil {
+			return err
+		}
+	default:
+		return invalid("unknown datum option %d", t)
+	}
+	return nil
+}
+
+func (o TransactionOutput) appendFields(b []byte) []byte {
+	if o.legacy && o.Datum == nil && o.ScriptRef == nil {
+		n := 2
+		if o.DatumHash != nil {
+			n = 3
+		}
+		b = cbor.AppendArrayHeader(b, n)
+		b = cbor.AppendBytes(b, o.Address)
+		b = appendValue(b, o.Amount)
+		if o.DatumHash != nil {
+			b = cbor.AppendBytes(b, o.DatumHash[:])
+		}
+		return b
+	}
+
+	n := 2
+	if o.DatumHash != nil || o.Datum != nil {
+		n++
+	}
+	if o.ScriptRef != nil {
+		n++
+	}
+	b = cbor.AppendMapHeader(b, n)
+	b = cbor.AppendUint(b, 0)
+	b = cbor.AppendBytes(b, o.Address)
+	b = cbor.AppendUint(b, 1)
+	b = appendValue(b, o.Amount)
+	switch {
+	case o.Datum != nil:
+		b = cbor.AppendUint(b, 2)
+		b = cbor.AppendArrayHeader(b, 2)
+		b = cbor.AppendUint(b, 1)
+		b = appendEncodedCBOR(b, o.Datum)
+	case o.DatumHash != nil:
+		b = cbor.AppendUint(b, 2)
+		b = cbor.AppendArrayHeader(b, 2)
+		b = cbor.AppendUint(b, 0)
+		b = cbor.AppendBytes(b, o.DatumHash[:])
+	}
+	if o.ScriptRef != nil {
+		b = cbor.AppendUint(b, 3)
+		b = appendEncodedCBOR(b, appendScript(nil, *o.ScriptRef))
+	}
+	return b
+}
+
+// readEncodedCBOR reads an item embedded as a tag 24 byte string.
+func readEncodedCBOR(d *cbor.Decoder) ([]byte, error) {
+	tag, err := d.ReadTag()
+	if err != nil {
+		return nil, err
+	}
+	if tag != cbor.TagEncodedCBOR {
+		return nil, invalid("expected tag 24, got %d", tag)
+	}
+	b, err := d.ReadBytes()
+	if err != nil {
+		return nil, err
+	}
+	if !cbor.Valid(b) {
+		return nil, invalid("malformed embedded CBOR")
+	}
+	return b, nil
+}
+
+func appendEncodedCBOR(b []byte, item []byte) []byte {
+	b = cbor.AppendTag(b, cbor.TagEncodedCBOR)
+	return cbor.AppendBytes(b, item)
+}
+
+func readScriptRef(d *cbor.Decoder) (Script, error) {
+	data, err := readEncodedCBOR(d)
+	if err != nil {
+		return Script{}, err
+	}
+	sd := cbor.NewDecoder(data)
+	s, err := readScript(sd)
+	if err != nil {
+		return Script{}, err
+	}
+	return s, sd.Finish()
+}
+
+func readScript(d *cbor.Decoder) (s Script, err error) {
+	if _, err = readArrayLen(d, "script", 2); err != nil {
+		return
+	}
+	t, err := d.ReadUint()
+	if err != nil {
+		return
+	}
+	s.Type = ScriptType(t)
+	switch s.Type {
+	case ScriptNative:
+		s.Bytes, err = d.ReadRaw()
+	case ScriptPlutusV1, ScriptPlutusV2, ScriptPlutusV3:
+		s.Bytes, err = d.ReadBytes()
+	default:
+		err = invalid("unknown script type %d", t)
+	}
+	return
+}
+
+func appendScript(b []byte, s Script) []byte {
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendUint(b, uint64(s.Type))
+	if s.Type == ScriptNative {
+		return append(b, s.Bytes...)
+	}
+	return cbor.AppendBytes(b, s.Bytes)
+}
+
+func readValue(d *cbor.Decoder) (v Value, err error) {
+	major, err := d.PeekMajor()
+	if err != nil {
+		return
+	}
+	if major == cbor.MajorUint {
+		v.Coin, err = d.ReadUint()
+		return
+	}
+	if _, err = readArrayLen(d, "value", 2); err != nil {
+		return
+	}
+	if v.Coin, err = d.ReadUint(); err != nil {
+		return
+	}
+	v.Assets, err = readMultiAsset(d, d.ReadUint)
+	return
+}
+
+func appendValue(b []byte, v Value) []byte {
+	if len(v.Assets) == 0 {
+		return cbor.AppendUint(b, v.Coin)
+	}
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendUint(b, v.Coin)
+	return appendMultiAsset(b, v.Assets, cbor.AppendUint)
+}
+
+func readMultiAsset[Q uint64 | int64](d *cbor.Decoder, readQ func() (Q, error)) (map[Hash28]map[AssetName]Q, error) {
+	out := map[Hash28]map[AssetName]Q{}
+	err := d.ReadMap(func(int) error {
+		policy, err := readHash28(d)
+		if err != nil {
+			return err
+		}
+		assets := map[AssetName]Q{}
+		out[policy] = assets
+		return d.ReadMap(func(int) error {
+			name, err := d.ReadBytes()
+			if err != nil {
+				return err
+			}
+			if len(name) > 32 {
+				return invalid("asset name longer than 32 bytes")
+			}
+			q, err := readQ()
+			if err != nil {
+				return err
+			}
+			assets[AssetName(name)] = q
+			return nil
+		})
+	})
+	return out, err
+}
+
+func appendMultiAsset[Q uint64 | int64](b []byte, m map[Hash28]map[AssetName]Q, appendQ func([]byte, Q) []byte) []byte {
+	policies := sortedKeys(m, func(k Hash28) []byte { return k[:] })
+	b = cbor.AppendMapHeader(b, len(policies))
+	for _, policy := range policies {
+		b = cbor.AppendBytes(b, policy[:])
+		assets := m[policy]
+		names := sortedKeys(assets, func(k AssetName) []byte { return cbor.AppendBytes(nil, []byte(k)) })
+		b = cbor.AppendMapHeader(b, len(names))
+		for _, name := range names {
+			b = cbor.AppendBytes(b, []byte(name))
+			b = appendQ(b, assets[name])
+		}
+	}
+	return b
+}
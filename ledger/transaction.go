@@ -0,0 +1,109 @@
+package ledger
+
+import (
+	"encoding/hex"
+
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+)
+
+// Transaction is a signed transaction.
+type Transaction struct {
+	Body       TransactionBody
+	WitnessSet WitnessSet
+
+	// Invalid is set for transactions submitted with is_valid set to false,
+	// whose scripts are expected to fail and which only consume collateral.
+	Invalid bool
+
+	AuxiliaryData *AuxiliaryData
+
+	// legacy is set for transactions decoded from the pre-Alonzo format
+	// without the is_valid flag.
+	legacy bool
+}
+
+// DecodeTransaction decodes a transaction from its CBOR encoding.
+func DecodeTransaction(data []byte) (*Transaction, error) {
+	tx := &Transaction{}
+	if err := tx.UnmarshalCBOR(data); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// DecodeTransactionHex decodes a transaction from the hex encoding of its
+// CBOR encoding, as returned by the API.
+func DecodeTransactionHex(s string) (*Transaction, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, wrap("hex", err)
+	}
+	return DecodeTransaction(data)
+}
+
+// Hash returns the hash of the transaction body, which is the transaction
+// ID.
+func (tx *Transaction) Hash() Hash32 {
+	return tx.Body.Hash()
+}
+
+// UnmarshalCBOR decodes tx from its CBOR encoding.
+func (tx *Transaction) UnmarshalCBOR(data []byte) error {
+	d := cbor.NewDecoder(data)
+	n, err := readArrayLen(d, "transaction", 3, 4)
+	if err != nil {
+		return wrap("transaction", err)
+	}
+	*tx = Transaction{legacy: n == 3}
+	if err := tx.Body.decode(d); err != nil {
+		return wrap("transaction body", err)
+	}
+	if err := tx.WitnessSet.decode(d); err != nil {
+		return wrap("witness set", err)
+	}
+	if n == 4 {
+		valid, err := d.ReadBool()
+		if err != nil {
+			return wrap("is_valid", err)
+		}
+		tx.Invalid = !valid
+	}
+	if !readOptionalNull(d) {
+		tx.AuxiliaryData = &AuxiliaryData{}
+		if err := tx.AuxiliaryData.decode(d); err != nil {
+			return wrap("auxiliary data", err)
+		}
+	}
+	return wrap("transaction", d.Finish())
+}
+
+// MarshalCBOR returns the CBOR encoding of tx. Components that were decoded
+// and not modified keep their original encoding.
+func (tx *Transaction) MarshalCBOR() ([]byte, error) {
+	legacy := tx.legacy && !tx.Invalid
+	var b []byte
+	if legacy {
+		b = cbor.AppendArrayHeader(b, 3)
+	} else {
+		b = cbor.AppendArrayHeader(b, 4)
+	}
+	body, _ := tx.Body.MarshalCBOR()
+	b = append(b, body...)
+	witnesses, _ := tx.WitnessSet.MarshalCBOR()
+	b = append(b, witnesses...)
+	if !legacy {
+		b = cbor.AppendBool(b, !tx.Invalid)
+	}
+	if tx.AuxiliaryData == nil {
+		return cbor.AppendNull(b), nil
+	}
+	aux, _ := tx.AuxiliaryData.MarshalCBOR()
+	return append(b, aux...), nil
+}
+
+// Hex returns the hex encoding of the CBOR encoding of tx, in the format
+// returned by TransactionCBOR.
+func (tx *Transaction) Hex() string {
+	b, _ := tx.MarshalCBOR()
+	return hex.EncodeToString(b)
+}
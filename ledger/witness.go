@@ -0,0 +1,431 @@
+package ledger
+
+import (
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+)
+
+// VKeyWitness is a signature of the transaction body by a payment or stake
+// key.
+type VKeyWitness struct {
+	VKey      [32]byte
+	Signature [64]byte
+}
+
+// BootstrapWitness is a signature of the transaction body by the key of a
+// Byron address.
+type BootstrapWitness struct {
+	PublicKey  [32]byte
+	Signature  [64]byte
+	ChainCode  []byte
+	Attributes []byte
+}
+
+// RedeemerTag identifies what a redeemer is used for.
+type RedeemerTag uint8
+
+const (
+	RedeemerSpend    RedeemerTag = 0
+	RedeemerMint     RedeemerTag = 1
+	RedeemerCert     RedeemerTag = 2
+	RedeemerReward   RedeemerTag = 3
+	RedeemerVoting   RedeemerTag = 4
+	RedeemerProposal RedeemerTag = 5
+)
+
+// ExUnits are the execution units of a script.
+type ExUnits struct {
+	Memory uint64
+	Steps  uint64
+}
+
+// Redeemer is the argument passed to a Plutus script, along with the
+// execution units it may use.
+type Redeemer struct {
+	Tag     RedeemerTag
+	Index   uint64
+	Data    []byte // CBOR encoding of the Plutus data
+	ExUnits ExUnits
+}
+
+// Witness set keys
+const (
+	witnessVKeys uint64 = iota
+	witnessNativeScripts
+	witnessBootstrap
+	witnessPlutusV1
+	witnessPlutusData
+	witnessRedeemers
+	witnessPlutusV2
+	witnessPlutusV3
+	witnessKeys
+)
+
+// WitnessSet holds the witnesses of a transaction. Native scripts and
+// Plutus data hold the CBOR encoding of each item.
+type WitnessSet struct {
+	VKeyWitnesses      []VKeyWitness
+	NativeScripts      [][]byte
+	BootstrapWitnesses []BootstrapWitness
+	PlutusV1Scripts    [][]byte
+	PlutusData         [][]byte
+	Redeemers          []Redeemer
+	PlutusV2Scripts    [][]byte
+	PlutusV3Scripts    [][]byte
+
+	// untaggedSets is set for witness sets whose sets are not tagged with
+	// 258, as before Conway.
+	untaggedSets bool
+	// legacyRedeemers is set for redeemers encoded as an array, as before
+	// Conway.
+	legacyRedeemers bool
+	orig            preserved
+	// fields preserves the encoding of each entry, so that adding a
+	// signature keeps the encoding of datums and redeemers, which are
+	// covered by the script data hash.
+	fields [witnessKeys]preserved
+}
+
+// MarshalCBOR returns the CBOR encoding of w.
+func (w WitnessSet) MarshalCBOR() ([]byte, error) {
+	return w.orig.encode(w.appendFields(nil)), nil
+}
+
+// UnmarshalCBOR decodes w from its CBOR encoding.
+func (w *WitnessSet) UnmarshalCBOR(data []byte) error {
+	d := cbor.NewDecoder(data)
+	if err := w.decode(d); err != nil {
+		return wrap("witness set", err)
+	}
+	return wrap("witness set", d.Finish())
+}
+
+// Scripts returns the scripts of the witness set.
+func (w WitnessSet) Scripts() []Script {
+	var out []Script
+	for _, s := range w.NativeScripts {
+		out = append(out, Script{Type: ScriptNative, Bytes: s})
+	}
+	for _, group := range []struct {
+		t       ScriptType
+		scripts [][]byte
+	}{{ScriptPlutusV1, w.PlutusV1Scripts}, {ScriptPlutusV2, w.PlutusV2Scripts}, {ScriptPlutusV3, w.PlutusV3Scripts}} {
+		for _, s := range group.scripts {
+			out = append(out, Script{Type: group.t, Bytes: s})
+		}
+	}
+	return out
+}
+
+func readByteStrings(d *cbor.Decoder, tagged *bool) ([][]byte, error) {
+	out := [][]byte{}
+	err := readSet(d, tagged, func(int) error {
+		b, err := d.ReadBytes()
+		out = append(out, b)
+		return err
+	})
+	return out, err
+}
+
+func readRawItems(d *cbor.Decoder, tagged *bool) ([][]byte, error) {
+	out := [][]byte{}
+	err := readSet(d, tagged, func(int) error {
+		b, err := d.ReadRaw()
+		out = append(out, b)
+		return err
+	})
+	return out, err
+}
+
+func (w *WitnessSet) decode(d *cbor.Decoder) error {
+	start := d.Pos()
+	*w = WitnessSet{}
+	tagged := false
+	err := d.ReadMap(func(int) error {
+		key, err := d.ReadUint()
+		if err != nil {
+			return err
+		}
+		if key >= witnessKeys {
+			return invalid("unknown witness set key %d", key)
+		}
+		fieldStart := d.Pos()
+		switch key {
+		case witnessVKeys:
+			w.VKeyWitnesses = []VKeyWitness{}
+			err = readSet(d, &tagged, func(int) error {
+				var v VKeyWitness
+				if _, err := readArrayLen(d, "vkey witness", 2); err != nil {
+					return err
+				}
+				if err := readFixedBytes(d, v.VKey[:]); err != nil {
+					return err
+				}
+				if err := readFixedBytes(d, v.Signature[:]); err != nil {
+					return err
+				}
+				w.VKeyWitnesses = append(w.VKeyWitnesses, v)
+				return nil
+			})
+		case witnessNativeScripts:
+			w.NativeScripts, err = readRawItems(d, &tagged)
+		case witnessBootstrap:
+			w.BootstrapWitnesses = []BootstrapWitness{}
+			err = readSet(d, &tagged, func(int) error {
+				var v BootstrapWitness
+				if _, err := readArrayLen(d, "bootstrap witness", 4); err != nil {
+					return err
+				}
+				if err := readFixedBytes(d, v.PublicKey[:]); err != nil {
+					return err
+				}
+				if err := readFixedBytes(d, v.Signature[:]); err != nil {
+					return err
+				}
+				var err error
+				if v.ChainCode, err = d.ReadBytes(); err != nil {
+					return err
+				}
+				if v.Attributes, err = d.ReadBytes(); err != nil {
+					return err
+				}
+				w.BootstrapWitnesses = append(w.BootstrapWitnesses, v)
+				return nil
+			})
+		case witnessPlutusV1:
+			w.PlutusV1Scripts, err = readByteStrings(d, &tagged)
+		case witnessPlutusData:
+			w.PlutusData, err = readRawItems(d, &tagged)
+		case witnessRedeemers:
+			err = w.decodeRedeemers(d)
+		case witnessPlutusV2:
+			w.PlutusV2Scripts, err = readByteStrings(d, &tagged)
+		case witnessPlutusV3:
+			w.PlutusV3Scripts, err = readByteStrings(d, &tagged)
+		}
+		if err != nil {
+			return err
+		}
+		w.fields[key].raw = d.Data()[fieldStart:d.Pos()]
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	w.untaggedSets = !tagged
+	for key := range w.fields {
+		if w.fields[key].raw != nil {
+			w.fields[key].canon = w.appendField(nil, uint64(key))
+		}
+	}
+	w.orig = preserved{raw: d.Data()[start:d.Pos()], canon: w.appendFields(nil)}
+	return nil
+}
+
+func (w *WitnessSet) decodeRedeemers(d *cbor.Decoder) error {
+	w.Redeemers = []Redeemer{}
+	readExUnits := func(r *Redeemer) (err error) {
+		if _, err = readArrayLen(d, "ex units", 2); err != nil {
+			return
+		}
+		if r.ExUnits.Memory, err = d.ReadUint(); err != nil {
+			return
+		}
+		r.ExUnits.Steps, err = d.ReadUint()
+		return
+	}
+	readTag := func(r *Redeemer) error {
+		t, err := d.ReadUint()
+		if err != nil {
+			return err
+		}
+		if t > uint64(RedeemerProposal) {
+			return invalid("unknown redeemer tag %d", t)
+		}
+		r.Tag = RedeemerTag(t)
+		r.Index, err = d.ReadUint()
+		return err
+	}
+
+	major, err := d.PeekMajor()
+	if err != nil {
+		return err
+	}
+	if major == cbor.MajorArray {
+		w.legacyRedeemers = true
+		return d.ReadArray(func(int) error {
+			var r Redeemer
+			if _, err := readArrayLen(d, "redeemer", 4); err != nil {
+				return err
+			}
+			if err := readTag(&r); err != nil {
+				return err
+			}
+			var err error
+			if r.Data, err = d.ReadRaw(); err != nil {
+				return err
+			}
+			if err := readExUnits(&r); err != nil {
+				return err
+			}
+			w.Redeemers = append(w.Redeemers, r)
+			return nil
+		})
+	}
+	return d.ReadMap(func(int) error {
+		var r Redeemer
+		if _, err := readArrayLen(d, "redeemer key", 2); err != nil {
+			return err
+		}
+		if err := readTag(&r); err != nil {
+			return err
+		}
+		if _, err := readArrayLen(d, "redeemer value", 2); err != nil {
+			return err
+		}
+		var err error
+		if r.Data, err = d.ReadRaw(); err != nil {
+			return err
+		}
+		if err := readExUnits(&r); err != nil {
+			return err
+		}
+		w.Redeemers = append(w.Redeemers, r)
+		return nil
+	})
+}
+
+func (w WitnessSet) present(key uint64) bool {
+	switch key {
+	case witnessVKeys:
+		return len(w.VKeyWitnesses) > 0
+	case witnessNativeScripts:
+		return len(w.NativeScripts) > 0
+	case witnessBootstrap:
+		return len(w.BootstrapWitnesses) > 0
+	case witnessPlutusV1:
+		return len(w.PlutusV1Scripts) > 0
+	case witnessPlutusData:
+		return len(w.PlutusData) > 0
+	case witnessRedeemers:
+		return len(w.Redeemers) > 0
+	case witnessPlutusV2:
+		return len(w.PlutusV2Scripts) > 0
+	case witnessPlutusV3:
+		return len(w.PlutusV3Scripts) > 0
+	}
+	return false
+}
+
+func (w WitnessSet) appendFields(b []byte) []byte {
+	n := 0
+	for key := uint64(0); key < witnessKeys; key++ {
+		if w.present(key) {
+			n++
+		}
+	}
+	b = cbor.AppendMapHeader(b, n)
+	for key := uint64(0); key < witnessKeys; key++ {
+		if w.present(key) {
+			b = cbor.AppendUint(b, key)
+			b = append(b, w.fields[key].encode(w.appendField(nil, key))...)
+		}
+	}
+	return b
+}
+
+func (w WitnessSet) appendField(b []byte, key uint64) []byte {
+	appendByteStrings := func(b []byte, items [][]byte) []byte {
+		b = appendSetHeader(b, len(items), w.untaggedSets)
+		for _, item := range items {
+			b = cbor.AppendBytes(b, item)
+		}
+		return b
+	}
+	appendRawItems := func(b []byte, items [][]byte) []byte {
+		b = appendSetHeader(b, len(items), w.untaggedSets)
+		for _, item := range items {
+			b = append(b, item...)
+		}
+		return b
+	}
+
+	switch key {
+	case witnessVKeys:
+		b = appendSetHeader(b, len(w.VKeyWitnesses), w.untaggedSets)
+		for _, v := range w.VKeyWitnesses {
+			b = cbor.AppendArrayHeader(b, 2)
+			b = cbor.AppendBytes(b, v.VKey[:])
+			b = cbor.AppendBytes(b, v.Signature[:])
+		}
+	case witnessNativeScripts:
+		b = appendRawItems(b, w.NativeScripts)
+	case witnessBootstrap:
+		b = appendSetHeader(b, len(w.BootstrapWitnesses), w.untaggedSets)
+		for _, v := range w.BootstrapWitnesses {
+			b = cbor.AppendArrayHeader(b, 4)
+			b = cbor.AppendBytes(b, v.PublicKey[:])
+			b = cbor.AppendBytes(b, v.Signature[:])
+			b = cbor.AppendBytes(b, v.ChainCode)
+			b = cbor.AppendBytes(b, v.Attributes)
+		}
+	case witnessPlutusV1:
+		b = appendByteStrings(b, w.PlutusV1Scripts)
+	case witnessPlutusData:
+		b = appendRawItems(b, w.PlutusData)
+	case witnessRedeemers:
+		b = w.appendRedeemers(b)
+	case witnessPlutusV2:
+		b = appendByteStrings(b, w.PlutusV2Scripts)
+	case witnessPlutusV3:
+		b = appendByteStrings(b, w.PlutusV3Scripts)
+	}
+	return b
+}
+
+func (w WitnessSet) appendRedeemers(b []byte) []byte {
+	if w.legacyRedeemers {
+		b = cbor.AppendArrayHeader(b, len(w.Redeemers))
+		for _, r := range w.Redeemers {
+			b = cbor.AppendArrayHeader(b, 4)
+			b = cbor.AppendUint(b, uint64(r.Tag))
+			b = cbor.AppendUint(b, r.Index)
+			b = append(b, r.Data...)
+			b = appendExUnits(b, r.ExUnits)
+		}
+		return b
+	}
+	b = cbor.AppendMapHeader(b, len(w.Redeemers))
+	for _, r := range w.Redeemers {
+		b = cbor.AppendArrayHeader(b, 2)
+		b = cbor.AppendUint(b, uint64(r.Tag))
+		b = cbor.AppendUint(b, r.Index)
+		b = cbor.AppendArrayHeader(b, 2)
+		b = append(b, r.Data...)
+		b = appendExUnits(b, r.ExUnits)
+	}
+	return b
+}
+
+func appendExUnits(b []byte, u ExUnits) []byte {
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendUint(b, u.Memory)
+	return cbor.AppendUint(b, u.Steps)
+}
+
+// RedeemersCBOR returns the encoding of the redeemers as it appears in the
+// witness set, which is the one covered by the script data hash.
+func (w WitnessSet) RedeemersCBOR() []byte {
+	if !w.present(witnessRedeemers) {
+		return nil
+	}
+	return w.fields[witnessRedeemers].encode(w.appendField(nil, witnessRedeemers))
+}
+
+// PlutusDataCBOR returns the encoding of the Plutus data as it appears in
+// the witness set, which is the one covered by the script data hash.
+func (w WitnessSet) PlutusDataCBOR() []byte {
+	if !w.present(witnessPlutusData) {
+		return nil
+	}
+	return w.fields[witnessPlutusData].encode(w.appendField(nil, witnessPlutusData))
+}
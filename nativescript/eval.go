@@ -0,0 +1,283 @@
+package nativescript
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+// Interval is a transaction validity interval, as set by the
+// ValidityIntervalStart and TTL fields of a transaction body. A transaction
+// is valid from slot InvalidBefore included to slot InvalidHereafter
+// excluded. A nil bound is unbounded.
+type Interval struct {
+	InvalidBefore    *uint64
+	InvalidHereafter *uint64
+}
+
+// TransactionInterval returns the validity interval of a transaction body.
+func TransactionInterval(body *ledger.TransactionBody) Interval {
+	return Interval{InvalidBefore: body.ValidityIntervalStart, InvalidHereafter: body.TTL}
+}
+
+// Contains reports whether slot is within iv.
+func (iv Interval) Contains(slot uint64) bool {
+	return (iv.InvalidBefore == nil || *iv.InvalidBefore <= slot) &&
+		(iv.InvalidHereafter == nil || slot < *iv.InvalidHereafter)
+}
+
+// IsEmpty reports whether no slot is within iv.
+func (iv Interval) IsEmpty() bool {
+	return iv.InvalidBefore != nil && iv.InvalidHereafter != nil && *iv.InvalidBefore >= *iv.InvalidHereafter
+}
+
+// intersect returns the slots within both iv and other.
+func (iv Interval) intersect(other Interval) Interval {
+	out := iv
+	if other.InvalidBefore != nil && (out.InvalidBefore == nil || *other.InvalidBefore > *out.InvalidBefore) {
+		out.InvalidBefore = other.InvalidBefore
+	}
+	if other.InvalidHereafter != nil && (out.InvalidHereafter == nil || *other.InvalidHereafter < *out.InvalidHereafter) {
+		out.InvalidHereafter = other.InvalidHereafter
+	}
+	return out
+}
+
+// hull returns the smallest interval holding both iv and other.
+func (iv Interval) hull(other Interval) Interval {
+	out := iv
+	if other.InvalidBefore == nil || out.InvalidBefore != nil && *other.InvalidBefore < *out.InvalidBefore {
+		out.InvalidBefore = other.InvalidBefore
+	}
+	if other.InvalidHereafter == nil || out.InvalidHereafter != nil && *other.InvalidHereafter > *out.InvalidHereafter {
+		out.InvalidHereafter = other.InvalidHereafter
+	}
+	return out
+}
+
+func slotPtr(slot uint64) *uint64 {
+	return &slot
+}
+
+func signerSet(signers []ledger.Hash28) map[ledger.Hash28]bool {
+	set := make(map[ledger.Hash28]bool, len(signers))
+	for _, h := range signers {
+		set[h] = true
+	}
+	return set
+}
+
+// Evaluate reports whether s is satisfied by a transaction signed by signers
+// with validity interval iv, following the ledger rules: an After script
+// requires the interval to start at or after its slot, and a Before script
+// requires the interval to end at or before its slot.
+func (s Script) Evaluate(signers []ledger.Hash28, iv Interval) bool {
+	return s.evaluate(signerSet(signers), iv)
+}
+
+func (s Script) evaluate(signers map[ledger.Hash28]bool, iv Interval) bool {
+	switch s.Type {
+	case TypeSig:
+		return signers[s.KeyHash]
+	case TypeAll:
+		for _, child := range s.Scripts {
+			if !child.evaluate(signers, iv) {
+				return false
+			}
+		}
+		return true
+	case TypeAny:
+		for _, child := range s.Scripts {
+			if child.evaluate(signers, iv) {
+				return true
+			}
+		}
+		return false
+	case TypeAtLeast:
+		n := 0
+		for _, child := range s.Scripts {
+			if n >= s.Required {
+				break
+			}
+			if child.evaluate(signers, iv) {
+				n++
+			}
+		}
+		return n >= s.Required
+	case TypeAfter:
+		return iv.InvalidBefore != nil && s.Slot <= *iv.InvalidBefore
+	case TypeBefore:
+		return iv.InvalidHereafter != nil && *iv.InvalidHereafter <= s.Slot
+	}
+	return false
+}
+
+// Window returns the validity interval holding the interval of every
+// transaction satisfying s, and false if no transaction can satisfy s
+// because of its time locks or required counts.
+func (s Script) Window() (Interval, bool) {
+	switch s.Type {
+	case TypeSig:
+		return Interval{}, true
+	case TypeAfter:
+		return Interval{InvalidBefore: slotPtr(s.Slot)}, true
+	case TypeBefore:
+		return Interval{InvalidHereafter: slotPtr(s.Slot)}, true
+	case TypeAll:
+		out := Interval{}
+		for _, child := range s.Scripts {
+			w, ok := child.Window()
+			if !ok {
+				return Interval{}, false
+			}
+			out = out.intersect(w)
+		}
+		return out, !out.IsEmpty()
+	case TypeAny, TypeAtLeast:
+		required := 1
+		if s.Type == TypeAtLeast {
+			if s.Required == 0 {
+				return Interval{}, true
+			}
+			required = s.Required
+		}
+		var windows []Interval
+		for _, child := range s.Scripts {
+			if w, ok := child.Window(); ok {
+				windows = append(windows, w)
+			}
+		}
+		if len(windows) < required {
+			return Interval{}, false
+		}
+		out := windows[0]
+		for _, w := range windows[1:] {
+			out = out.hull(w)
+		}
+		return out, true
+	}
+	return Interval{}, false
+}
+
+// Requirement is what a transaction needs to satisfy a script.
+type Requirement struct {
+	// Signers are the key hashes that must sign the transaction, sorted.
+	Signers []ledger.Hash28
+
+	// Missing are the Signers that are not available yet, sorted.
+	Missing []ledger.Hash28
+
+	// Interval is the widest validity interval the transaction can declare.
+	// The transaction interval must be within Interval.
+	Interval Interval
+}
+
+// Require returns what a transaction submitted at slot needs to satisfy s,
+// given the key hashes of the available signers, and false if s cannot be
+// satisfied at slot. At each Any and AtLeast script, the scripts missing
+// the fewest signatures are chosen.
+//
+// A multisig wallet would call Require with the keys it holds to find the
+// co-signers to ask for, and set the validity interval of the transaction
+// within the returned Interval.
+func (s Script) Require(available []ledger.Hash28, slot uint64) (Requirement, bool) {
+	set := signerSet(available)
+	sol, ok := s.require(set, slot)
+	if !ok {
+		return Requirement{}, false
+	}
+	r := Requirement{Interval: sol.interval}
+	for h := range sol.signers {
+		r.Signers = append(r.Signers, h)
+		if !set[h] {
+			r.Missing = append(r.Missing, h)
+		}
+	}
+	sortHashes(r.Signers)
+	sortHashes(r.Missing)
+	return r, true
+}
+
+// SatisfiedAt reports whether s is satisfied at slot by signatures of
+// signers, with a suitable validity interval.
+func (s Script) SatisfiedAt(signers []ledger.Hash28, slot uint64) bool {
+	r, ok := s.Require(signers, slot)
+	return ok && len(r.Missing) == 0
+}
+
+type solution struct {
+	signers  map[ledger.Hash28]bool
+	missing  int
+	interval Interval
+}
+
+func (sol *solution) merge(other solution, available map[ledger.Hash28]bool) {
+	for h := range other.signers {
+		if !sol.signers[h] {
+			sol.signers[h] = true
+			if !available[h] {
+				sol.missing++
+			}
+		}
+	}
+	sol.interval = sol.interval.intersect(other.interval)
+}
+
+func (s Script) require(available map[ledger.Hash28]bool, slot uint64) (solution, bool) {
+	sol := solution{signers: map[ledger.Hash28]bool{}}
+	switch s.Type {
+	case TypeSig:
+		sol.signers[s.KeyHash] = true
+		if !available[s.KeyHash] {
+			sol.missing = 1
+		}
+		return sol, true
+	case TypeAfter:
+		sol.interval.InvalidBefore = slotPtr(s.Slot)
+		return sol, s.Slot <= slot
+	case TypeBefore:
+		sol.interval.InvalidHereafter = slotPtr(s.Slot)
+		return sol, slot < s.Slot
+	case TypeAll:
+		for _, child := range s.Scripts {
+			c, ok := child.require(available, slot)
+			if !ok {
+				return solution{}, false
+			}
+			sol.merge(c, available)
+		}
+		return sol, true
+	case TypeAny, TypeAtLeast:
+		required := 1
+		if s.Type == TypeAtLeast {
+			required = s.Required
+		}
+		var candidates []solution
+		for _, child := range s.Scripts {
+			if c, ok := child.require(available, slot); ok {
+				candidates = append(candidates, c)
+			}
+		}
+		if len(candidates) < required {
+			return solution{}, false
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].missing != candidates[j].missing {
+				return candidates[i].missing < candidates[j].missing
+			}
+			return len(candidates[i].signers) < len(candidates[j].signers)
+		})
+		for _, c := range candidates[:required] {
+			sol.merge(c, available)
+		}
+		return sol, true
+	}
+	return solution{}, false
+}
+
+func sortHashes(hashes []ledger.Hash28) {
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i][:], hashes[j][:]) < 0
+	})
+}
@@ -0,0 +1,109 @@
+package nativescript
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+// scriptJSON is the JSON representation of a script, as returned by
+// ScriptJSON and used by cardano-cli:
+//
+//	{"type": "sig", "keyHash": "..."}
+//	{"type": "all", "scripts": [...]}
+//	{"type": "any", "scripts": [...]}
+//	{"type": "atLeast", "required": 2, "scripts": [...]}
+//	{"type": "after", "slot": 42}
+//	{"type": "before", "slot": 42}
+type scriptJSON struct {
+	Type     string    `json:"type"`
+	KeyHash  string    `json:"keyHash,omitempty"`
+	Required *int      `json:"required,omitempty"`
+	Slot     *uint64   `json:"slot,omitempty"`
+	Scripts  *[]Script `json:"scripts,omitempty"`
+}
+
+// FromJSONValue converts a JSON value, as decoded by encoding/json into
+// ScriptJSON.JSON, to a native script.
+func FromJSONValue(v interface{}) (*Script, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidScript, err)
+	}
+	s := &Script{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Script) MarshalJSON() ([]byte, error) {
+	if err := s.validate(0); err != nil {
+		return nil, err
+	}
+	sj := scriptJSON{Type: s.Type.String()}
+	switch s.Type {
+	case TypeSig:
+		sj.KeyHash = s.KeyHash.String()
+	case TypeAll, TypeAny, TypeAtLeast:
+		if s.Type == TypeAtLeast {
+			sj.Required = &s.Required
+		}
+		scripts := s.Scripts
+		if scripts == nil {
+			scripts = []Script{}
+		}
+		sj.Scripts = &scripts
+	case TypeAfter, TypeBefore:
+		sj.Slot = &s.Slot
+	}
+	return json.Marshal(sj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Script) UnmarshalJSON(data []byte) error {
+	var sj scriptJSON
+	if err := json.Unmarshal(data, &sj); err != nil {
+		if errors.Is(err, ErrInvalidScript) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrInvalidScript, err)
+	}
+	*s = Script{}
+	for t, name := range typeNames {
+		if name == sj.Type {
+			s.Type = t
+		}
+	}
+	if typeNames[s.Type] != sj.Type {
+		return fmt.Errorf("%w: unknown script type %q", ErrInvalidScript, sj.Type)
+	}
+	switch s.Type {
+	case TypeSig:
+		h, err := ledger.Hash28FromHex(sj.KeyHash)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidScript, err)
+		}
+		s.KeyHash = h
+	case TypeAll, TypeAny, TypeAtLeast:
+		if sj.Scripts == nil {
+			return fmt.Errorf("%w: %s script without scripts", ErrInvalidScript, sj.Type)
+		}
+		s.Scripts = *sj.Scripts
+		if s.Type == TypeAtLeast {
+			if sj.Required == nil || *sj.Required < 0 {
+				return fmt.Errorf("%w: atLeast script without required count", ErrInvalidScript)
+			}
+			s.Required = *sj.Required
+		}
+	case TypeAfter, TypeBefore:
+		if sj.Slot == nil {
+			return fmt.Errorf("%w: %s script without slot", ErrInvalidScript, sj.Type)
+		}
+		s.Slot = *sj.Slot
+	}
+	return nil
+}
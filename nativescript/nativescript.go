@@ -0,0 +1,334 @@
+// Package nativescript implements native scripts, also known as timelock
+// or multisig scripts.
+//
+// Scripts are decoded from the JSON returned by ScriptJSON or from their
+// CBOR encoding, as found in witness sets and auxiliary data. They can be
+// hashed, evaluated against a set of signers and a validity interval, and
+// queried for the signatures and validity interval a transaction needs to
+// satisfy them.
+package nativescript
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+// ErrInvalidScript is returned when decoding data that is not a valid native
+// script.
+var ErrInvalidScript = errors.New("nativescript: invalid script")
+
+// Type is the type of a native script.
+type Type uint64
+
+const (
+	// TypeSig requires a signature from a key hash.
+	TypeSig Type = 0
+	// TypeAll requires all of its scripts.
+	TypeAll Type = 1
+	// TypeAny requires any of its scripts.
+	TypeAny Type = 2
+	// TypeAtLeast requires a number of its scripts.
+	TypeAtLeast Type = 3
+	// TypeAfter requires the transaction to be invalid before a slot.
+	TypeAfter Type = 4
+	// TypeBefore requires the transaction to be invalid from a slot.
+	TypeBefore Type = 5
+)
+
+var typeNames = map[Type]string{
+	TypeSig:     "sig",
+	TypeAll:     "all",
+	TypeAny:     "any",
+	TypeAtLeast: "atLeast",
+	TypeAfter:   "after",
+	TypeBefore:  "before",
+}
+
+// String returns the name of t, as used in JSON.
+func (t Type) String() string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("Type(%d)", uint64(t))
+}
+
+const maxDepth = 256
+
+// Script is a native script. Only the fields relevant to its Type are set.
+type Script struct {
+	Type Type
+
+	// KeyHash is the key hash of a TypeSig script.
+	KeyHash ledger.Hash28
+
+	// Scripts are the scripts of a TypeAll, TypeAny or TypeAtLeast script.
+	Scripts []Script
+
+	// Required is the number of scripts required by a TypeAtLeast script.
+	Required int
+
+	// Slot is the slot of a TypeAfter or TypeBefore script.
+	Slot uint64
+
+	// raw and canon are the original encoding of a decoded script and the
+	// canonical encoding of its fields at decoding time.
+	raw   []byte
+	canon []byte
+}
+
+// Sig returns a script requiring a signature from keyHash.
+func Sig(keyHash ledger.Hash28) Script {
+	return Script{Type: TypeSig, KeyHash: keyHash}
+}
+
+// All returns a script requiring all of scripts.
+func All(scripts ...Script) Script {
+	return Script{Type: TypeAll, Scripts: scripts}
+}
+
+// Any returns a script requiring any of scripts.
+func Any(scripts ...Script) Script {
+	return Script{Type: TypeAny, Scripts: scripts}
+}
+
+// AtLeast returns a script requiring at least required of scripts.
+func AtLeast(required int, scripts ...Script) Script {
+	return Script{Type: TypeAtLeast, Required: required, Scripts: scripts}
+}
+
+// After returns a script requiring the transaction to be invalid before
+// slot, so that it can only be valid from slot onwards.
+func After(slot uint64) Script {
+	return Script{Type: TypeAfter, Slot: slot}
+}
+
+// Before returns a script requiring the transaction to be invalid from
+// slot onwards.
+func Before(slot uint64) Script {
+	return Script{Type: TypeBefore, Slot: slot}
+}
+
+// Decode decodes a native script from its CBOR encoding.
+func Decode(data []byte) (*Script, error) {
+	s := &Script{}
+	if err := s.UnmarshalCBOR(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// DecodeHex decodes a native script from the hex encoding of its CBOR
+// encoding.
+func DecodeHex(s string) (*Script, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidScript, err)
+	}
+	return Decode(data)
+}
+
+// FromLedger decodes the native script held by s.
+func FromLedger(s ledger.Script) (*Script, error) {
+	if s.Type != ledger.ScriptNative {
+		return nil, fmt.Errorf("%w: not a native script", ErrInvalidScript)
+	}
+	return Decode(s.Bytes)
+}
+
+// UnmarshalCBOR decodes a native script from its CBOR encoding.
+func (s *Script) UnmarshalCBOR(data []byte) error {
+	d := cbor.NewDecoder(data)
+	if err := s.decode(d, 0); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidScript, err)
+	}
+	if err := d.Finish(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidScript, err)
+	}
+	return nil
+}
+
+func (s *Script) decode(d *cbor.Decoder, depth int) error {
+	if depth > maxDepth {
+		return cbor.ErrTooDeep
+	}
+	start := d.Pos()
+	*s = Script{}
+	n, err := d.ReadArrayHeader()
+	if err != nil {
+		return err
+	}
+	t, err := d.ReadUint()
+	if err != nil {
+		return err
+	}
+	s.Type = Type(t)
+
+	want := 2
+	switch s.Type {
+	case TypeSig:
+		b, err := d.ReadBytes()
+		if err != nil {
+			return err
+		}
+		if len(b) != len(s.KeyHash) {
+			return fmt.Errorf("expected %d bytes key hash, got %d", len(s.KeyHash), len(b))
+		}
+		copy(s.KeyHash[:], b)
+	case TypeAll, TypeAny, TypeAtLeast:
+		if s.Type == TypeAtLeast {
+			want = 3
+			required, err := d.ReadUint()
+			if err != nil {
+				return err
+			}
+			if required > uint64(^uint(0)>>1) {
+				return fmt.Errorf("required count %d out of range", required)
+			}
+			s.Required = int(required)
+		}
+		s.Scripts = []Script{}
+		err := d.ReadArray(func(int) error {
+			var child Script
+			if err := child.decode(d, depth+1); err != nil {
+				return err
+			}
+			s.Scripts = append(s.Scripts, child)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	case TypeAfter, TypeBefore:
+		if s.Slot, err = d.ReadUint(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown script type %d", t)
+	}
+	if n != want {
+		return fmt.Errorf("%s script: unexpected array length %d", s.Type, n)
+	}
+
+	s.raw = append([]byte{}, d.Data()[start:d.Pos()]...)
+	s.canon = s.appendCanonical(nil)
+	return nil
+}
+
+// MarshalCBOR returns the CBOR encoding of s. Decoded scripts that were not
+// modified are encoded with their original bytes.
+func (s Script) MarshalCBOR() ([]byte, error) {
+	if err := s.validate(0); err != nil {
+		return nil, err
+	}
+	return s.appendCBOR(nil), nil
+}
+
+func (s Script) validate(depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("%w: maximum nesting depth exceeded", ErrInvalidScript)
+	}
+	switch s.Type {
+	case TypeSig, TypeAfter, TypeBefore:
+	case TypeAll, TypeAny, TypeAtLeast:
+		if s.Required < 0 {
+			return fmt.Errorf("%w: negative required count %d", ErrInvalidScript, s.Required)
+		}
+		for _, child := range s.Scripts {
+			if err := child.validate(depth + 1); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unknown script type %d", ErrInvalidScript, uint64(s.Type))
+	}
+	return nil
+}
+
+func (s Script) appendCBOR(b []byte) []byte {
+	fresh := s.appendCanonical(nil)
+	if s.raw != nil && bytes.Equal(fresh, s.canon) {
+		return append(b, s.raw...)
+	}
+	return append(b, fresh...)
+}
+
+// appendCanonical appends the canonical encoding of s, reusing the original
+// encoding of unmodified children.
+func (s Script) appendCanonical(b []byte) []byte {
+	switch s.Type {
+	case TypeSig:
+		b = cbor.AppendArrayHeader(b, 2)
+		b = cbor.AppendUint(b, uint64(s.Type))
+		return cbor.AppendBytes(b, s.KeyHash[:])
+	case TypeAll, TypeAny, TypeAtLeast:
+		if s.Type == TypeAtLeast {
+			b = cbor.AppendArrayHeader(b, 3)
+			b = cbor.AppendUint(b, uint64(s.Type))
+			b = cbor.AppendUint(b, uint64(s.Required))
+		} else {
+			b = cbor.AppendArrayHeader(b, 2)
+			b = cbor.AppendUint(b, uint64(s.Type))
+		}
+		b = cbor.AppendArrayHeader(b, len(s.Scripts))
+		for _, child := range s.Scripts {
+			b = child.appendCBOR(b)
+		}
+		return b
+	default:
+		b = cbor.AppendArrayHeader(b, 2)
+		b = cbor.AppendUint(b, uint64(s.Type))
+		return cbor.AppendUint(b, s.Slot)
+	}
+}
+
+// Hex returns the hex encoding of the CBOR encoding of s.
+func (s Script) Hex() (string, error) {
+	b, err := s.MarshalCBOR()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Ledger returns s as a script of a witness set or reference script.
+func (s Script) Ledger() (ledger.Script, error) {
+	b, err := s.MarshalCBOR()
+	if err != nil {
+		return ledger.Script{}, err
+	}
+	return ledger.Script{Type: ledger.ScriptNative, Bytes: b}, nil
+}
+
+// Hash returns the script hash of s, which is also the policy ID of assets
+// minted under s.
+func (s Script) Hash() (ledger.Hash28, error) {
+	ls, err := s.Ledger()
+	if err != nil {
+		return ledger.Hash28{}, err
+	}
+	return ls.Hash(), nil
+}
+
+// KeyHashes returns the key hashes of the TypeSig scripts of s, in order of
+// appearance and without duplicates.
+func (s Script) KeyHashes() []ledger.Hash28 {
+	seen := map[ledger.Hash28]bool{}
+	var out []ledger.Hash28
+	var walk func(s Script)
+	walk = func(s Script) {
+		if s.Type == TypeSig && !seen[s.KeyHash] {
+			seen[s.KeyHash] = true
+			out = append(out, s.KeyHash)
+		}
+		for _, child := range s.Scripts {
+			walk(child)
+		}
+	}
+	walk(s)
+	return out
+}
@@ -0,0 +1,266 @@
+package nativescript_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/ledger"
+	"github.com/blockfrost/blockfrost-go/nativescript"
+)
+
+const testdata = "../testdata"
+
+func key(b byte) ledger.Hash28 {
+	var h ledger.Hash28
+	for i := range h {
+		h[i] = b
+	}
+	return h
+}
+
+func slot(s uint64) *uint64 {
+	return &s
+}
+
+func loadScriptJSON(t *testing.T) *nativescript.Script {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(testdata, "integrationresourcescriptjson.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sj := blockfrost.ScriptJSON{}
+	if err := json.Unmarshal(data, &sj); err != nil {
+		t.Fatal(err)
+	}
+	s, err := sj.NativeScript()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestScriptJSONFixture(t *testing.T) {
+	s := loadScriptJSON(t)
+	if s.Type != nativescript.TypeAll || len(s.Scripts) != 3 || s.Scripts[0].Type != nativescript.TypeSig {
+		t.Fatalf("unexpected script %+v", s)
+	}
+	h, err := s.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.String(), "65c197d565e88a20885e535f93755682444d3c02fd44dd70883fe89e"; got != want {
+		t.Fatalf("got hash %s, want %s", got, want)
+	}
+
+	// The script decoded from its CBOR encoding is the same.
+	enc, err := s.Hex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := nativescript.DecodeHex(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := json.Marshal(s)
+	b, _ := json.Marshal(decoded)
+	if string(a) != string(b) {
+		t.Fatalf("got %s, want %s", b, a)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	in := `{"type":"any","scripts":[` +
+		`{"type":"atLeast","required":1,"scripts":[{"type":"sig","keyHash":"` + strings.Repeat("01", 28) + `"}]},` +
+		`{"type":"all","scripts":[]},` +
+		`{"type":"after","slot":10},` +
+		`{"type":"before","slot":20}]}`
+	var s nativescript.Script
+	if err := json.Unmarshal([]byte(in), &s); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != in {
+		t.Fatalf("got %s, want %s", out, in)
+	}
+
+	for _, bad := range []string{
+		`{"type":"sig","keyHash":"01"}`,
+		`{"type":"nope"}`,
+		`{"type":"all"}`,
+		`{"type":"atLeast","scripts":[]}`,
+		`{"type":"after"}`,
+		`{"type":"all","scripts":[{"type":"before"}]}`,
+	} {
+		var s nativescript.Script
+		if err := json.Unmarshal([]byte(bad), &s); !errors.Is(err, nativescript.ErrInvalidScript) {
+			t.Errorf("%s: got %v, want ErrInvalidScript", bad, err)
+		}
+	}
+}
+
+func TestCBOR(t *testing.T) {
+	s := nativescript.AtLeast(2, nativescript.Sig(key(1)), nativescript.After(10), nativescript.Before(1000))
+	got, err := s.Hex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := key(1)
+	want := "830302838200581c" + hex.EncodeToString(k[:]) + "82040a82051903e8"
+	if got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	for _, bad := range []string{"", "8200", "820058020101", "8306", "8203", "83010080", "820480"} {
+		if _, err := nativescript.DecodeHex(bad); !errors.Is(err, nativescript.ErrInvalidScript) {
+			t.Errorf("%q: got %v, want ErrInvalidScript", bad, err)
+		}
+	}
+}
+
+func TestCBORPreservesEncoding(t *testing.T) {
+	// Slot 10 encoded on two bytes instead of one.
+	raw := "8201818204180a"
+	s, err := nativescript.DecodeHex(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.Hex()
+	if got != raw {
+		t.Fatalf("got %s, want %s", got, raw)
+	}
+
+	ls, err := s.Ledger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, _ := s.Hash()
+	if ls.Hash() != h {
+		t.Fatalf("got %s, want %s", ls.Hash(), h)
+	}
+	back, err := nativescript.FromLedger(ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := back.Hex(); got != raw {
+		t.Fatalf("got %s, want %s", got, raw)
+	}
+
+	// Modified scripts are encoded canonically.
+	s.Scripts[0].Slot = 11
+	if got, _ := s.Hex(); got != "82018182040b" {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	s := nativescript.All(
+		nativescript.AtLeast(2, nativescript.Sig(key(1)), nativescript.Sig(key(2)), nativescript.Sig(key(3))),
+		nativescript.After(100),
+		nativescript.Before(200),
+	)
+	tests := []struct {
+		name    string
+		signers []ledger.Hash28
+		iv      nativescript.Interval
+		want    bool
+	}{
+		{"satisfied", []ledger.Hash28{key(1), key(3)}, nativescript.Interval{InvalidBefore: slot(100), InvalidHereafter: slot(200)}, true},
+		{"not enough signers", []ledger.Hash28{key(1), key(4)}, nativescript.Interval{InvalidBefore: slot(100), InvalidHereafter: slot(200)}, false},
+		{"unbounded start", []ledger.Hash28{key(1), key(2)}, nativescript.Interval{InvalidHereafter: slot(200)}, false},
+		{"early start", []ledger.Hash28{key(1), key(2)}, nativescript.Interval{InvalidBefore: slot(99), InvalidHereafter: slot(200)}, false},
+		{"late end", []ledger.Hash28{key(1), key(2)}, nativescript.Interval{InvalidBefore: slot(150), InvalidHereafter: slot(201)}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := s.Evaluate(test.signers, test.iv); got != test.want {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	tx := &ledger.TransactionBody{ValidityIntervalStart: slot(120), TTL: slot(180)}
+	if !s.Evaluate([]ledger.Hash28{key(2), key(3)}, nativescript.TransactionInterval(tx)) {
+		t.Fatal("expected transaction to satisfy the script")
+	}
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name   string
+		script nativescript.Script
+		want   nativescript.Interval
+		ok     bool
+	}{
+		{"sig", nativescript.Sig(key(1)), nativescript.Interval{}, true},
+		{"all", nativescript.All(nativescript.After(10), nativescript.Before(20), nativescript.After(5)), nativescript.Interval{InvalidBefore: slot(10), InvalidHereafter: slot(20)}, true},
+		{"empty all", nativescript.All(nativescript.After(20), nativescript.Before(20)), nativescript.Interval{}, false},
+		{"any", nativescript.Any(nativescript.All(nativescript.After(10), nativescript.Before(20)), nativescript.All(nativescript.After(30), nativescript.Before(40))), nativescript.Interval{InvalidBefore: slot(10), InvalidHereafter: slot(40)}, true},
+		{"any unbounded", nativescript.Any(nativescript.Sig(key(1)), nativescript.After(30)), nativescript.Interval{}, true},
+		{"at least too many", nativescript.AtLeast(3, nativescript.Sig(key(1)), nativescript.Sig(key(2))), nativescript.Interval{}, false},
+		{"at least zero", nativescript.AtLeast(0, nativescript.After(30)), nativescript.Interval{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := test.script.Window()
+			if ok != test.ok {
+				t.Fatalf("got ok %v, want %v", ok, test.ok)
+			}
+			if ok && !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	// A treasury spendable by 2 of 3 board members, or by the owner alone
+	// after slot 1000.
+	s := nativescript.Any(
+		nativescript.AtLeast(2, nativescript.Sig(key(1)), nativescript.Sig(key(2)), nativescript.Sig(key(3))),
+		nativescript.All(nativescript.Sig(key(9)), nativescript.After(1000)),
+	)
+
+	r, ok := s.Require([]ledger.Hash28{key(2)}, 500)
+	if !ok {
+		t.Fatal("expected script to be satisfiable")
+	}
+	if !reflect.DeepEqual(r.Missing, []ledger.Hash28{key(1)}) || !reflect.DeepEqual(r.Signers, []ledger.Hash28{key(1), key(2)}) {
+		t.Fatalf("got %+v", r)
+	}
+	if r.Interval != (nativescript.Interval{}) {
+		t.Fatalf("got interval %+v", r.Interval)
+	}
+	if s.SatisfiedAt([]ledger.Hash28{key(2)}, 500) {
+		t.Fatal("expected script not to be satisfied")
+	}
+
+	r, ok = s.Require([]ledger.Hash28{key(9)}, 1500)
+	if !ok || len(r.Missing) != 0 || *r.Interval.InvalidBefore != 1000 || r.Interval.InvalidHereafter != nil {
+		t.Fatalf("got %+v, %v", r, ok)
+	}
+	if !s.SatisfiedAt([]ledger.Hash28{key(9)}, 1500) || s.SatisfiedAt([]ledger.Hash28{key(9)}, 999) {
+		t.Fatal("unexpected evaluation of the owner branch")
+	}
+	if !s.Evaluate(r.Signers, r.Interval) {
+		t.Fatal("expected requirement to satisfy the script")
+	}
+
+	expired := nativescript.All(nativescript.Sig(key(1)), nativescript.Before(100))
+	if _, ok := expired.Require(nil, 100); ok {
+		t.Fatal("expected expired script not to be satisfiable")
+	}
+
+	if got := s.KeyHashes(); !reflect.DeepEqual(got, []ledger.Hash28{key(1), key(2), key(3), key(9)}) {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -0,0 +1,216 @@
+package params
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+// GovernanceTypeParameterChange is the GovernanceType of proposals
+// changing protocol parameters.
+const GovernanceTypeParameterChange = "parameter_change"
+
+// Entry is the change of the protocol parameters at the start of an epoch.
+type Entry struct {
+	// Epoch is the first epoch with the new parameters.
+	Epoch int
+
+	Changes []Change
+
+	// Proposals are the parameter change proposals enacted at the start of
+	// Epoch, when HistoryOptions.Proposals is set.
+	Proposals []blockfrost.ProposalDetails
+}
+
+// Cache keeps the parameters of epochs across calls to History. The zero
+// value is an empty cache, safe for concurrent use.
+type Cache struct {
+	mu     sync.Mutex
+	params map[int]blockfrost.EpochParameters
+}
+
+func (c *Cache) get(epoch int) (blockfrost.EpochParameters, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, ok := c.params[epoch]
+	return p, ok
+}
+
+func (c *Cache) put(p blockfrost.EpochParameters) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.params == nil {
+		c.params = map[int]blockfrost.EpochParameters{}
+	}
+	c.params[p.Epoch] = p
+}
+
+// HistoryOptions configures History.
+type HistoryOptions struct {
+	// Concurrency is the maximum number of concurrent requests. Default: 10.
+	Concurrency int
+
+	// Cache keeps the parameters fetched, to be reused by later calls.
+	// Default: none, parameters are fetched again on each call.
+	Cache *Cache
+
+	// Proposals links the changes to the parameter change proposals that
+	// enacted them, at the cost of listing all the proposals.
+	Proposals bool
+}
+
+// History returns the changes of the protocol parameters over the epochs
+// from to to, inclusive: one Entry for each epoch after from whose
+// parameters differ from the previous epoch.
+func History(ctx context.Context, client blockfrost.APIClient, from, to int, opts HistoryOptions) ([]Entry, error) {
+	if from < 0 || to < from {
+		return nil, fmt.Errorf("params: invalid epoch range %d-%d", from, to)
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 10
+	}
+	if opts.Cache == nil {
+		opts.Cache = &Cache{}
+	}
+
+	all, err := fetchAll(ctx, client, from, to, opts)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for i := 1; i < len(all); i++ {
+		if changes := Diff(all[i-1], all[i]); len(changes) > 0 {
+			entries = append(entries, Entry{Epoch: from + i, Changes: changes})
+		}
+	}
+	if opts.Proposals && len(entries) > 0 {
+		if err := link(ctx, client, entries, opts.Concurrency); err != nil {
+			return entries, err
+		}
+	}
+	return entries, nil
+}
+
+// fetchAll returns the parameters of the epochs from to to, through the
+// cache.
+func fetchAll(ctx context.Context, client blockfrost.APIClient, from, to int, opts HistoryOptions) ([]blockfrost.EpochParameters, error) {
+	all := make([]blockfrost.EpochParameters, to-from+1)
+	errs := make([]error, len(all))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+	for i := range all {
+		if p, ok := opts.Cache.get(from + i); ok {
+			all[i] = p
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			p, err := client.EpochParameters(ctx, from+i)
+			if err != nil {
+				errs[i] = fmt.Errorf("params: epoch %d: %w", from+i, err)
+				return
+			}
+			all[i] = p
+			opts.Cache.put(p)
+		}(i)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// link sets the proposals of the entries, and of their changes, from the
+// parameter change proposals enacted in their epochs.
+func link(ctx context.Context, client blockfrost.APIClient, entries []Entry, concurrency int) error {
+	var (
+		candidates []blockfrost.Proposal
+		listErr    error
+	)
+	// The channel is drained even after an error, to let its goroutines
+	// return.
+	for res := range client.ProposalsAll(ctx) {
+		if res.Err != nil && listErr == nil {
+			listErr = fmt.Errorf("params: proposals: %w", res.Err)
+		}
+		for _, p := range res.Res {
+			if p.GovernanceType == GovernanceTypeParameterChange {
+				candidates = append(candidates, p)
+			}
+		}
+	}
+	if listErr != nil {
+		return listErr
+	}
+
+	type proposal struct {
+		details blockfrost.ProposalDetails
+		params  map[string]interface{}
+		err     error
+	}
+	proposals := make([]proposal, len(candidates))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, c := range candidates {
+		wg.Add(1)
+		go func(p *proposal, c blockfrost.Proposal) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if p.details, p.err = client.Proposal(ctx, c.TxHash, c.CertIndex); p.err != nil {
+				p.err = fmt.Errorf("params: proposal %s#%d: %w", c.TxHash, c.CertIndex, p.err)
+				return
+			}
+			if p.details.EnactedEpoch == nil {
+				return
+			}
+			pp, err := client.ProposalParameters(ctx, c.TxHash, c.CertIndex)
+			if err != nil {
+				p.err = fmt.Errorf("params: parameters of proposal %s#%d: %w", c.TxHash, c.CertIndex, err)
+				return
+			}
+			p.params = pp.Parameters
+		}(&proposals[i], c)
+	}
+	wg.Wait()
+
+	byEpoch := map[int][]*proposal{}
+	var errs []error
+	for i := range proposals {
+		p := &proposals[i]
+		if p.err != nil {
+			errs = append(errs, p.err)
+			continue
+		}
+		if e := p.details.EnactedEpoch; e != nil {
+			byEpoch[*e] = append(byEpoch[*e], p)
+		}
+	}
+	for i := range entries {
+		e := &entries[i]
+		for _, p := range byEpoch[e.Epoch] {
+			e.Proposals = append(e.Proposals, p.details)
+			for j := range e.Changes {
+				c := &e.Changes[j]
+				if changes(p.params, c.Parameter) {
+					c.Proposal = &p.details
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// changes reports whether the parameters of a proposal change the
+// parameter name. Proposals name cost models "cost_models", where epoch
+// parameters have their raw form.
+func changes(proposed map[string]interface{}, name string) bool {
+	return proposed[name] != nil || name == "cost_models_raw" && proposed["cost_models"] != nil
+}
@@ -0,0 +1,145 @@
+package params_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/params"
+)
+
+// governance is a fake client serving epoch parameters and the proposals
+// that changed them.
+type governance struct {
+	blockfrost.APIClient
+
+	mu    sync.Mutex
+	calls map[int]int
+}
+
+// EpochParameters returns the parameters of epoch: min_fee_a is raised at
+// epoch 505, and the PlutusV3 cost model changes at epoch 507, along with
+// max_tx_size.
+func (g *governance) EpochParameters(ctx context.Context, epoch int) (blockfrost.EpochParameters, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls == nil {
+		g.calls = map[int]int{}
+	}
+	g.calls[epoch]++
+	if epoch > 510 {
+		return blockfrost.EpochParameters{}, &blockfrost.APIError{Response: blockfrost.NotFound{StatusCode: 404}}
+	}
+	p := blockfrost.EpochParameters{
+		Epoch:         epoch,
+		MinFeeA:       44,
+		MaxTxSize:     16384,
+		CostModelsRaw: map[string]interface{}{"PlutusV3": []interface{}{1.0, 2.0}},
+	}
+	if epoch >= 505 {
+		p.MinFeeA = 45
+	}
+	if epoch >= 507 {
+		p.MaxTxSize = 32768
+		p.CostModelsRaw = map[string]interface{}{"PlutusV3": []interface{}{1.0, 4.0}}
+	}
+	return p, nil
+}
+
+func intPtr(n int) *int { return &n }
+
+var proposals = map[string]struct {
+	details blockfrost.ProposalDetails
+	params  map[string]interface{}
+}{
+	"fee": {
+		blockfrost.ProposalDetails{TxHash: "fee", GovernanceType: "parameter_change", EnactedEpoch: intPtr(505)},
+		map[string]interface{}{"min_fee_a": 45.0, "max_tx_size": nil},
+	},
+	"costs": {
+		blockfrost.ProposalDetails{TxHash: "costs", GovernanceType: "parameter_change", EnactedEpoch: intPtr(507)},
+		map[string]interface{}{"cost_models": map[string]interface{}{"PlutusV3": []interface{}{1.0, 4.0}}},
+	},
+	"size": {
+		blockfrost.ProposalDetails{TxHash: "size", GovernanceType: "parameter_change", EnactedEpoch: intPtr(507)},
+		map[string]interface{}{"max_tx_size": 32768.0},
+	},
+	"dropped": {
+		blockfrost.ProposalDetails{TxHash: "dropped", GovernanceType: "parameter_change", DroppedEpoch: intPtr(506)},
+		nil,
+	},
+}
+
+func (g *governance) ProposalsAll(ctx context.Context) <-chan blockfrost.ProposalResult {
+	ch := make(chan blockfrost.ProposalResult, 1)
+	res := []blockfrost.Proposal{{TxHash: "withdrawal", GovernanceType: "treasury_withdrawals"}}
+	for hash, p := range proposals {
+		res = append(res, blockfrost.Proposal{TxHash: hash, GovernanceType: p.details.GovernanceType})
+	}
+	ch <- blockfrost.ProposalResult{Res: res}
+	close(ch)
+	return ch
+}
+
+func (g *governance) Proposal(ctx context.Context, txHash string, certIndex int) (blockfrost.ProposalDetails, error) {
+	p, ok := proposals[txHash]
+	if !ok {
+		return blockfrost.ProposalDetails{}, errors.New("unexpected request for " + txHash)
+	}
+	return p.details, nil
+}
+
+func (g *governance) ProposalParameters(ctx context.Context, txHash string, certIndex int) (blockfrost.ProposalParameters, error) {
+	return blockfrost.ProposalParameters{TxHash: txHash, Parameters: proposals[txHash].params}, nil
+}
+
+func TestHistory(t *testing.T) {
+	g := &governance{}
+	cache := &params.Cache{}
+	entries, err := params.History(context.Background(), g, 500, 510, params.HistoryOptions{Cache: cache, Proposals: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Epoch != 505 || entries[1].Epoch != 507 {
+		t.Fatalf("got %+v", entries)
+	}
+
+	fee := entries[0]
+	if len(fee.Changes) != 1 || fee.Changes[0].Parameter != "min_fee_a" || fee.Changes[0].Proposal == nil || fee.Changes[0].Proposal.TxHash != "fee" {
+		t.Fatalf("got %+v", fee)
+	}
+	if len(fee.Proposals) != 1 {
+		t.Fatalf("got proposals %+v", fee.Proposals)
+	}
+
+	linked := map[string]string{}
+	for _, c := range entries[1].Changes {
+		if c.Proposal != nil {
+			linked[c.Parameter] = c.Proposal.TxHash
+		}
+	}
+	if linked["max_tx_size"] != "size" || linked["cost_models_raw"] != "costs" || len(entries[1].Proposals) != 2 {
+		t.Fatalf("got %+v", entries[1])
+	}
+	for _, c := range entries[1].Changes {
+		if c.Parameter == "cost_models_raw" && (len(c.CostModels) != 1 || *c.CostModels[0].New != 4) {
+			t.Fatalf("got cost model changes %+v", c.CostModels)
+		}
+	}
+
+	// The parameters are fetched once.
+	if _, err := params.History(context.Background(), g, 505, 510, params.HistoryOptions{Cache: cache}); err != nil {
+		t.Fatal(err)
+	}
+	for epoch, n := range g.calls {
+		if n != 1 {
+			t.Fatalf("got %d requests for epoch %d", n, epoch)
+		}
+	}
+
+	if _, err := params.History(context.Background(), g, 509, 512, params.HistoryOptions{}); err == nil {
+		t.Fatal("expected error for future epochs")
+	}
+}
@@ -0,0 +1,195 @@
+// Package params compares the protocol parameters of epochs.
+//
+// Diff compares the parameters of two epochs, down to the parameters of
+// the Plutus cost models. History builds the change log of a range of
+// epochs, linked to the governance actions that enacted the changes:
+//
+//	entries, err := params.History(ctx, client, 500, 550, params.HistoryOptions{Proposals: true})
+//	for _, e := range entries {
+//		for _, c := range e.Changes {
+//			fmt.Println(e.Epoch, c.Parameter, c.Old, "->", c.New)
+//		}
+//	}
+package params
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+// Change is the change of a protocol parameter between two epochs.
+type Change struct {
+	// Parameter is the JSON name of the parameter, such as "min_fee_a".
+	Parameter string
+
+	// Old and New are the values of the parameter, dereferenced, or nil
+	// when it is not set. Fractional parameters are blockfrost.Rational
+	// values, exact when the parameters were decoded from the API.
+	Old, New interface{}
+
+	// CostModels are the changes of the cost models, when Parameter is
+	// "cost_models_raw".
+	CostModels []CostModelChange
+
+	// Proposal is the governance action that enacted the change, when it
+	// is known.
+	Proposal *blockfrost.ProposalDetails
+}
+
+// CostModelChange is the change of a cost model parameter, in the raw list
+// form of the cost models of a Plutus version.
+type CostModelChange struct {
+	// Language is the Plutus version, such as "PlutusV3".
+	Language string
+
+	// Index is the position of the parameter in the cost model.
+	Index int
+
+	// Old and New are the values of the parameter, or nil when the cost
+	// model did not have it, such as when a new version of the cost model
+	// adds parameters.
+	Old, New *int64
+}
+
+// ignored are the fields of EpochParameters that are not compared: the
+// epoch and its nonce, which change every epoch, and deprecated aliases of
+// other parameters.
+var ignored = map[string]bool{
+	"epoch":               true,
+	"nonce":               true,
+	"cost_models":         true, // cost_models_raw
+	"coins_per_utxo_word": true, // coins_per_utxo_size
+}
+
+// Diff returns the parameters that differ between prev and next, in the
+// order of the fields of EpochParameters.
+func Diff(prev, next blockfrost.EpochParameters) []Change {
+	ov, nv := reflect.ValueOf(prev), reflect.ValueOf(next)
+	t := ov.Type()
+	var changes []Change
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := jsonName(f)
+		if name == "-" || ignored[name] {
+			continue
+		}
+		o, n := value(ov, f), value(nv, f)
+		if equal(o, n) {
+			continue
+		}
+		c := Change{Parameter: name, Old: o, New: n}
+		if name == "cost_models_raw" {
+			c.CostModels = DiffCostModels(prev.CostModelsRaw, next.CostModelsRaw)
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name = strings.TrimSpace(name); name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// value returns the value of the field f of v. Fractional parameters are
+// read from v.Rational, the exact values decoded from the API, or through
+// their Rational accessor for parameters built in code.
+func value(v reflect.Value, f reflect.StructField) interface{} {
+	fv := v.FieldByIndex(f.Index)
+	if exact := v.FieldByName("Rational").FieldByName(f.Name); exact.IsValid() && !exact.IsNil() {
+		fv = exact
+	} else if m := v.MethodByName(f.Name + "Rational"); m.IsValid() {
+		fv = m.Call(nil)[0]
+	}
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	return fv.Interface()
+}
+
+func equal(a, b interface{}) bool {
+	ra, ok := a.(blockfrost.Rational)
+	if rb, ok2 := b.(blockfrost.Rational); ok && ok2 {
+		return ra.Cmp(rb) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// DiffCostModels returns the parameters that differ between the cost
+// models prev and next, in the raw form of EpochParameters.CostModelsRaw,
+// sorted by language and index. Cost models whose values are not lists of
+// integers are reported as a whole, with Index -1.
+func DiffCostModels(prev, next map[string]interface{}) []CostModelChange {
+	languages := map[string]bool{}
+	for lang := range prev {
+		languages[lang] = true
+	}
+	for lang := range next {
+		languages[lang] = true
+	}
+	var changes []CostModelChange
+	for lang := range languages {
+		o, oerr := costModel(prev[lang])
+		n, nerr := costModel(next[lang])
+		if oerr != nil || nerr != nil {
+			if !reflect.DeepEqual(prev[lang], next[lang]) {
+				changes = append(changes, CostModelChange{Language: lang, Index: -1})
+			}
+			continue
+		}
+		for i := 0; i < len(o) || i < len(n); i++ {
+			var c CostModelChange
+			if i < len(o) {
+				c.Old = &o[i]
+			}
+			if i < len(n) {
+				c.New = &n[i]
+			}
+			if c.Old == nil || c.New == nil || *c.Old != *c.New {
+				c.Language, c.Index = lang, i
+				changes = append(changes, c)
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Language != changes[j].Language {
+			return changes[i].Language < changes[j].Language
+		}
+		return changes[i].Index < changes[j].Index
+	})
+	return changes
+}
+
+// costModel returns the raw cost model v, a list of JSON numbers, or none
+// if v is nil.
+func costModel(v interface{}) ([]int64, error) {
+	if v == nil {
+		return nil, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cost model is a %T", v)
+	}
+	out := make([]int64, len(list))
+	for i, x := range list {
+		f, ok := x.(float64)
+		if !ok || f != float64(int64(f)) {
+			return nil, fmt.Errorf("cost model parameter %d is %v", i, x)
+		}
+		out[i] = int64(f)
+	}
+	return out, nil
+}
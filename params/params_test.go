@@ -0,0 +1,93 @@
+package params_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/params"
+)
+
+func int64Ptr(n int64) *int64 { return &n }
+
+func decode(t *testing.T, s string) blockfrost.EpochParameters {
+	t.Helper()
+	var p blockfrost.EpochParameters
+	if err := json.Unmarshal([]byte(s), &p); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestDiff(t *testing.T) {
+	prev := decode(t, `{"epoch":500,"nonce":"aa","min_fee_a":44,"price_mem":0.0577,"max_tx_ex_mem":"14000000","cost_models":{"PlutusV1":{"a":1}},"cost_models_raw":{"PlutusV1":[1,2]}}`)
+	next := decode(t, `{"epoch":501,"nonce":"bb","min_fee_a":45,"price_mem":0.0577,"collateral_percent":150,"cost_models":{"PlutusV1":{"a":2}},"cost_models_raw":{"PlutusV1":[1,3]}}`)
+
+	got := params.Diff(prev, next)
+	want := []params.Change{
+		{Parameter: "min_fee_a", Old: 44, New: 45},
+		{Parameter: "max_tx_ex_mem", Old: "14000000", New: nil},
+		{Parameter: "collateral_percent", Old: nil, New: 150},
+		{
+			Parameter:  "cost_models_raw",
+			Old:        map[string]interface{}{"PlutusV1": []interface{}{1.0, 2.0}},
+			New:        map[string]interface{}{"PlutusV1": []interface{}{1.0, 3.0}},
+			CostModels: []params.CostModelChange{{Language: "PlutusV1", Index: 1, Old: int64Ptr(2), New: int64Ptr(3)}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+
+	// Fractional parameters are compared exactly.
+	next = decode(t, `{"tau":0.3}`)
+	got = params.Diff(decode(t, `{"tau":0.2}`), next)
+	if len(got) != 1 || got[0].Parameter != "tau" || got[0].New.(blockfrost.Rational).Cmp(blockfrost.NewRational(3, 10)) != 0 {
+		t.Fatalf("got %+v", got)
+	}
+
+	// Changes beyond the precision of the float fields are found.
+	prev = decode(t, `{"price_step":0.000072100001}`)
+	next = decode(t, `{"price_step":0.000072100002}`)
+	if *prev.PriceStep != *next.PriceStep {
+		t.Fatal("expected equal float32 values")
+	}
+	got = params.Diff(prev, next)
+	if len(got) != 1 || got[0].Parameter != "price_step" ||
+		got[0].Old.(blockfrost.Rational).Rat().Cmp(big.NewRat(72100001, 1e12)) != 0 ||
+		got[0].New.(blockfrost.Rational).Rat().Cmp(big.NewRat(72100002, 1e12)) != 0 {
+		t.Fatalf("got %+v", got)
+	}
+
+	if changes := params.Diff(next, next); len(changes) != 0 {
+		t.Fatalf("got %+v, want no change", changes)
+	}
+}
+
+func TestDiffCostModels(t *testing.T) {
+	prev := map[string]interface{}{
+		"PlutusV1": []interface{}{100.0, 200.0},
+		"PlutusV2": []interface{}{1.0, 2.0, 3.0},
+	}
+	next := map[string]interface{}{
+		"PlutusV1": []interface{}{100.0, 200.0},
+		"PlutusV2": []interface{}{1.0, 5.0, 3.0, 10000000000.0},
+		"PlutusV3": []interface{}{7.0},
+		"Custom":   map[string]interface{}{"a": 1.0},
+	}
+	got := params.DiffCostModels(prev, next)
+	want := []params.CostModelChange{
+		{Language: "Custom", Index: -1},
+		{Language: "PlutusV2", Index: 1, Old: int64Ptr(2), New: int64Ptr(5)},
+		{Language: "PlutusV2", Index: 3, New: int64Ptr(10000000000)},
+		{Language: "PlutusV3", Index: 0, New: int64Ptr(7)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+	if got := params.DiffCostModels(next, next); len(got) != 0 {
+		t.Fatalf("got %+v, want no change", got)
+	}
+}
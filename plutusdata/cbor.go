@@ -0,0 +1,228 @@
+package plutusdata
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+const (
+	// Constructors 0 to 6 are encoded with tags 121 to 127, constructors 7
+	// to 127 with tags 1280 to 1400 and any other constructor with tag 102
+	// followed by [constructor, fields].
+	tagConstr0       = 121
+	tagConstr7       = 1280
+	tagConstrGeneral = 102
+
+	// Byte strings, including those of big integers, are encoded in chunks
+	// of at most 64 bytes.
+	chunkSize = 64
+
+	maxDepth = 256
+)
+
+var maxUint64 = new(big.Int).SetUint64(^uint64(0))
+
+// Decode decodes Plutus data from its CBOR encoding.
+func Decode(data []byte) (PlutusData, error) {
+	d := cbor.NewDecoder(data)
+	v, err := decode(d, 0)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	if err := d.Finish(); err != nil {
+		return nil, wrap(err)
+	}
+	return v, nil
+}
+
+// DecodeHex decodes Plutus data from the hex encoding of its CBOR encoding,
+// as found in ScriptDatumCBOR and inline datums.
+func DecodeHex(s string) (PlutusData, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	return Decode(data)
+}
+
+func wrap(err error) error {
+	return fmt.Errorf("%w: %v", ErrInvalidData, err)
+}
+
+func decode(d *cbor.Decoder, depth int) (PlutusData, error) {
+	if depth > maxDepth {
+		return nil, cbor.ErrTooDeep
+	}
+	major, err := d.PeekMajor()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cbor.MajorUint, cbor.MajorNegInt:
+		n, err := d.ReadBigInt()
+		return Int{n}, err
+	case cbor.MajorBytes:
+		b, err := d.ReadBytes()
+		return Bytes(append([]byte{}, b...)), err
+	case cbor.MajorArray:
+		l := List{}
+		err := d.ReadArray(func(int) error {
+			v, err := decode(d, depth+1)
+			l = append(l, v)
+			return err
+		})
+		return l, err
+	case cbor.MajorMap:
+		m := Map{}
+		err := d.ReadMap(func(int) error {
+			k, err := decode(d, depth+1)
+			if err != nil {
+				return err
+			}
+			v, err := decode(d, depth+1)
+			m = append(m, Pair{Key: k, Value: v})
+			return err
+		})
+		return m, err
+	case cbor.MajorTag:
+		tag, _ := d.PeekTag()
+		if tag == cbor.TagPositiveBigNum || tag == cbor.TagNegativeBigNum {
+			n, err := d.ReadBigInt()
+			return Int{n}, err
+		}
+		_, _ = d.ReadTag()
+		return decodeConstr(d, tag, depth)
+	}
+	return nil, fmt.Errorf("unexpected major type %d at offset %d", major, d.Pos())
+}
+
+func decodeConstr(d *cbor.Decoder, tag uint64, depth int) (PlutusData, error) {
+	c := Constr{Fields: []PlutusData{}}
+	switch {
+	case tag >= tagConstr0 && tag < tagConstr0+7:
+		c.Tag = tag - tagConstr0
+	case tag >= tagConstr7 && tag < tagConstr7+121:
+		c.Tag = tag - tagConstr7 + 7
+	case tag == tagConstrGeneral:
+		n, err := d.ReadArrayHeader()
+		if err != nil {
+			return nil, err
+		}
+		if n != 2 {
+			return nil, fmt.Errorf("general constructor: unexpected array length %d", n)
+		}
+		if c.Tag, err = d.ReadUint(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unexpected tag %d", tag)
+	}
+	err := d.ReadArray(func(int) error {
+		v, err := decode(d, depth+1)
+		c.Fields = append(c.Fields, v)
+		return err
+	})
+	return c, err
+}
+
+// Encode returns the CBOR encoding of v, in the encoding used by the
+// node: non-empty lists and constructor fields are indefinite length
+// arrays, maps are definite length, and byte strings longer than 64 bytes
+// are chunked.
+//
+// Datums must be hashed and witnessed with the exact bytes found on chain,
+// which may differ from the encoding returned by Encode.
+func Encode(v PlutusData) ([]byte, error) {
+	return appendData(nil, v)
+}
+
+// Hash returns the hash of v, as the datum hash of an output holding the
+// encoding returned by Encode.
+func Hash(v PlutusData) (ledger.Hash32, error) {
+	b, err := Encode(v)
+	if err != nil {
+		return ledger.Hash32{}, err
+	}
+	return ledger.Blake2b256(b), nil
+}
+
+// DatumHash returns the hash of a datum from its CBOR encoding.
+func DatumHash(data []byte) ledger.Hash32 {
+	return ledger.Blake2b256(data)
+}
+
+func appendList(b []byte, items []PlutusData) ([]byte, error) {
+	if len(items) == 0 {
+		return cbor.AppendArrayHeader(b, 0), nil
+	}
+	b = cbor.AppendIndefiniteArray(b)
+	for _, item := range items {
+		var err error
+		if b, err = appendData(b, item); err != nil {
+			return nil, err
+		}
+	}
+	return cbor.AppendBreak(b), nil
+}
+
+func appendData(b []byte, v PlutusData) ([]byte, error) {
+	switch v := v.(type) {
+	case Constr:
+		switch {
+		case v.Tag < 7:
+			b = cbor.AppendTag(b, tagConstr0+v.Tag)
+		case v.Tag < 128:
+			b = cbor.AppendTag(b, tagConstr7+v.Tag-7)
+		default:
+			b = cbor.AppendTag(b, tagConstrGeneral)
+			b = cbor.AppendArrayHeader(b, 2)
+			b = cbor.AppendUint(b, v.Tag)
+		}
+		return appendList(b, v.Fields)
+	case Map:
+		b = cbor.AppendMapHeader(b, len(v))
+		for _, p := range v {
+			var err error
+			if b, err = appendData(b, p.Key); err != nil {
+				return nil, err
+			}
+			if b, err = appendData(b, p.Value); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case List:
+		return appendList(b, v)
+	case Int:
+		if v.Int == nil {
+			return nil, fmt.Errorf("%w: nil integer", ErrInvalidData)
+		}
+		return appendInt(b, v.Int), nil
+	case Bytes:
+		return cbor.AppendBytesChunked(b, v, chunkSize), nil
+	case nil:
+		return nil, fmt.Errorf("%w: nil value", ErrInvalidData)
+	}
+	return nil, fmt.Errorf("%w: unexpected type %T", ErrInvalidData, v)
+}
+
+func appendInt(b []byte, n *big.Int) []byte {
+	if n.Sign() >= 0 {
+		if n.Cmp(maxUint64) <= 0 {
+			return cbor.AppendUint(b, n.Uint64())
+		}
+		b = cbor.AppendTag(b, cbor.TagPositiveBigNum)
+		return cbor.AppendBytesChunked(b, n.Bytes(), chunkSize)
+	}
+	m := new(big.Int).Neg(n)
+	m.Sub(m, big.NewInt(1))
+	if m.Cmp(maxUint64) <= 0 {
+		return cbor.AppendHead(b, cbor.MajorNegInt, m.Uint64())
+	}
+	b = cbor.AppendTag(b, cbor.TagNegativeBigNum)
+	return cbor.AppendBytesChunked(b, m.Bytes(), chunkSize)
+}
@@ -0,0 +1,210 @@
+package plutusdata
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// DecodeJSON decodes Plutus data from the detailed schema JSON, as used by
+// ScriptDatum:
+//
+//	{"constructor": 0, "fields": [...]}
+//	{"map": [{"k": ..., "v": ...}]}
+//	{"list": [...]}
+//	{"int": 42}
+//	{"bytes": "cafe"}
+func DecodeJSON(data []byte) (PlutusData, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, wrap(err)
+	}
+	return FromJSONValue(v)
+}
+
+// FromJSONValue converts a detailed schema JSON value, as decoded by
+// encoding/json into ScriptDatum.JSONValue, to Plutus data. Integers
+// decoded as float64 lose precision beyond 2^53; use DecodeJSON on the raw
+// JSON to decode larger integers exactly.
+func FromJSONValue(v interface{}) (PlutusData, error) {
+	d, err := fromJSONValue(v, 0)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	return d, nil
+}
+
+func fromJSONValue(v interface{}, depth int) (PlutusData, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("maximum nesting depth exceeded")
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected object, got %T", v)
+	}
+	if c, ok := obj["constructor"]; ok {
+		tag, err := jsonInt(c)
+		if err != nil {
+			return nil, err
+		}
+		if tag.Sign() < 0 || !tag.IsUint64() {
+			return nil, fmt.Errorf("invalid constructor %s", tag)
+		}
+		fields, err := jsonList(obj["fields"], depth)
+		if err != nil {
+			return nil, err
+		}
+		return Constr{Tag: tag.Uint64(), Fields: fields}, nil
+	}
+	if len(obj) != 1 {
+		return nil, fmt.Errorf("unexpected object with %d keys", len(obj))
+	}
+	for key, value := range obj {
+		switch key {
+		case "map":
+			entries, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected map entries, got %T", value)
+			}
+			m := Map{}
+			for _, e := range entries {
+				entry, ok := e.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("expected map entry, got %T", e)
+				}
+				k, err := fromJSONValue(entry["k"], depth+1)
+				if err != nil {
+					return nil, err
+				}
+				v, err := fromJSONValue(entry["v"], depth+1)
+				if err != nil {
+					return nil, err
+				}
+				m = append(m, Pair{Key: k, Value: v})
+			}
+			return m, nil
+		case "list":
+			l, err := jsonList(value, depth)
+			return List(l), err
+		case "int":
+			n, err := jsonInt(value)
+			return Int{n}, err
+		case "bytes":
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected hex string, got %T", value)
+			}
+			b, err := hex.DecodeString(s)
+			return Bytes(b), err
+		}
+		return nil, fmt.Errorf("unexpected key %q", key)
+	}
+	return nil, nil
+}
+
+func jsonList(v interface{}, depth int) ([]PlutusData, error) {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected list, got %T", v)
+	}
+	out := make([]PlutusData, 0, len(items))
+	for _, item := range items {
+		d, err := fromJSONValue(item, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func jsonInt(v interface{}) (*big.Int, error) {
+	switch v := v.(type) {
+	case json.Number:
+		n, ok := new(big.Int).SetString(strings.TrimSpace(v.String()), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %s", v)
+		}
+		return n, nil
+	case float64:
+		if v != math.Trunc(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("invalid integer %v", v)
+		}
+		n, _ := big.NewFloat(v).Int(nil)
+		return n, nil
+	}
+	return nil, fmt.Errorf("expected integer, got %T", v)
+}
+
+// EncodeJSON returns the detailed schema JSON encoding of v.
+func EncodeJSON(v PlutusData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeJSON(buf *bytes.Buffer, v PlutusData) error {
+	writeList := func(items []PlutusData) error {
+		buf.WriteByte('[')
+		for i, item := range items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+
+	switch v := v.(type) {
+	case Constr:
+		fmt.Fprintf(buf, `{"constructor":%d,"fields":`, v.Tag)
+		if err := writeList(v.Fields); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+	case Map:
+		buf.WriteString(`{"map":[`)
+		for i, p := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(`{"k":`)
+			if err := writeJSON(buf, p.Key); err != nil {
+				return err
+			}
+			buf.WriteString(`,"v":`)
+			if err := writeJSON(buf, p.Value); err != nil {
+				return err
+			}
+			buf.WriteByte('}')
+		}
+		buf.WriteString(`]}`)
+	case List:
+		buf.WriteString(`{"list":`)
+		if err := writeList(v); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+	case Int:
+		if v.Int == nil {
+			return fmt.Errorf("%w: nil integer", ErrInvalidData)
+		}
+		fmt.Fprintf(buf, `{"int":%s}`, v.String())
+	case Bytes:
+		fmt.Fprintf(buf, `{"bytes":"%s"}`, hex.EncodeToString(v))
+	default:
+		return fmt.Errorf("%w: unexpected type %T", ErrInvalidData, v)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Package plutusdata implements the Plutus data format used by datums and
+// redeemers.
+//
+// Plutus data is decoded from CBOR, as returned by ScriptDatumCBOR and in
+// inline datums, or from the detailed schema JSON returned by ScriptDatum.
+// Values are represented by an AST of Constr, Map, List, Int and Bytes, and
+// can be converted to and from Go structs with Marshal and Unmarshal.
+package plutusdata
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+)
+
+// ErrInvalidData is returned when decoding malformed Plutus data.
+var ErrInvalidData = errors.New("plutusdata: invalid data")
+
+// PlutusData is a Plutus data value: Constr, Map, List, Int or Bytes.
+type PlutusData interface {
+	plutusData()
+}
+
+// Constr is a constructor application, such as a variant of a sum type or
+// a record.
+type Constr struct {
+	Tag    uint64
+	Fields []PlutusData
+}
+
+// Pair is an entry of a Map.
+type Pair struct {
+	Key   PlutusData
+	Value PlutusData
+}
+
+// Map is an association list. Keys may repeat and their order is kept.
+type Map []Pair
+
+// List is a list of values.
+type List []PlutusData
+
+// Int is an integer of arbitrary size.
+type Int struct {
+	*big.Int
+}
+
+// Bytes is a byte string.
+type Bytes []byte
+
+func (Constr) plutusData() {}
+func (Map) plutusData()    {}
+func (List) plutusData()   {}
+func (Int) plutusData()    {}
+func (Bytes) plutusData()  {}
+
+// NewConstr returns a constructor application.
+func NewConstr(tag uint64, fields ...PlutusData) Constr {
+	if fields == nil {
+		fields = []PlutusData{}
+	}
+	return Constr{Tag: tag, Fields: fields}
+}
+
+// NewInt returns an Int holding v.
+func NewInt(v int64) Int {
+	return Int{big.NewInt(v)}
+}
+
+// Lookup returns the value of the first pair of m with the given key.
+func (m Map) Lookup(key PlutusData) (PlutusData, bool) {
+	for _, p := range m {
+		if Equal(p.Key, key) {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Equal reports whether a and b are the same value.
+func Equal(a, b PlutusData) bool {
+	switch a := a.(type) {
+	case Constr:
+		b, ok := b.(Constr)
+		if !ok || a.Tag != b.Tag || len(a.Fields) != len(b.Fields) {
+			return false
+		}
+		for i := range a.Fields {
+			if !Equal(a.Fields[i], b.Fields[i]) {
+				return false
+			}
+		}
+		return true
+	case Map:
+		b, ok := b.(Map)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !Equal(a[i].Key, b[i].Key) || !Equal(a[i].Value, b[i].Value) {
+				return false
+			}
+		}
+		return true
+	case List:
+		b, ok := b.(List)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !Equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case Int:
+		b, ok := b.(Int)
+		return ok && a.Int != nil && b.Int != nil && a.Cmp(b.Int) == 0
+	case Bytes:
+		b, ok := b.(Bytes)
+		return ok && bytes.Equal(a, b)
+	}
+	return false
+}
@@ -0,0 +1,300 @@
+package plutusdata_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/plutusdata"
+)
+
+const testdata = "../testdata"
+
+func mustDecodeHex(t *testing.T, s string) plutusdata.PlutusData {
+	t.Helper()
+	d, err := plutusdata.DecodeHex(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func mustEncodeHex(t *testing.T, d plutusdata.PlutusData) string {
+	t.Helper()
+	b, err := plutusdata.Encode(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func bigInt(s string) plutusdata.Int {
+	n, _ := new(big.Int).SetString(s, 10)
+	return plutusdata.Int{Int: n}
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		data plutusdata.PlutusData
+		want string
+	}{
+		{plutusdata.NewInt(42), "9e1199a988ba72ffd6e9c269cadb3b53b5f360ff99f112d9b2ee30c4d74ad88b"},
+		{plutusdata.NewConstr(0), "923918e403bf43c34b4ef6b48eb2ee04babed17320d8d1b9ff9ad086e86f44ec"},
+	}
+	for _, test := range tests {
+		got, err := plutusdata.Hash(test.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != test.want {
+			t.Fatalf("got %s, want %s", got, test.want)
+		}
+	}
+
+	b, _ := hex.DecodeString("182a")
+	if got := plutusdata.DatumHash(b).String(); got != tests[0].want {
+		t.Fatalf("got %s, want %s", got, tests[0].want)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		data plutusdata.PlutusData
+		want string
+	}{
+		{"int", plutusdata.NewInt(-1), "20"},
+		{"bignum", bigInt("18446744073709551616"), "c249010000000000000000"},
+		{"negative bignum", bigInt("-18446744073709551617"), "c349010000000000000000"},
+		{"empty list", plutusdata.List{}, "80"},
+		{"list", plutusdata.List{plutusdata.NewInt(1), plutusdata.NewInt(2)}, "9f0102ff"},
+		{"map", plutusdata.Map{{Key: plutusdata.Bytes{0xca}, Value: plutusdata.NewInt(1)}}, "a141ca01"},
+		{"constr 1", plutusdata.NewConstr(1, plutusdata.Bytes{}), "d87a9f40ff"},
+		{"constr 7", plutusdata.NewConstr(7), "d9050080"},
+		{"constr 127", plutusdata.NewConstr(127), "d9057880"},
+		{"constr 200", plutusdata.NewConstr(200), "d8668218c880"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := mustEncodeHex(t, test.data)
+			if got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+			if back := mustDecodeHex(t, got); !plutusdata.Equal(back, test.data) {
+				t.Fatalf("round trip: got %#v, want %#v", back, test.data)
+			}
+		})
+	}
+}
+
+func TestEncodeChunkedBytes(t *testing.T) {
+	b := bytes.Repeat([]byte{0xab}, 100)
+	enc, err := plutusdata.Encode(plutusdata.Bytes(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte{0x5f, 0x58, 64}, b[:64]...)
+	want = append(append(want, 0x58, 36), b[64:]...)
+	want = append(want, 0xff)
+	if !bytes.Equal(enc, want) {
+		t.Fatalf("got %x, want %x", enc, want)
+	}
+	got, err := plutusdata.Decode(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plutusdata.Equal(got, plutusdata.Bytes(b)) {
+		t.Fatalf("got %x", got)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, s := range []string{"", "zz", "f6", "d9ffff80", "9f01", "182a00", "d8668301020380"} {
+		if _, err := plutusdata.DecodeHex(s); !errors.Is(err, plutusdata.ErrInvalidData) {
+			t.Errorf("%q: got %v, want ErrInvalidData", s, err)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	in := `{"constructor":0,"fields":[{"bytes":"cafe"},{"int":340282366920938463463374607431768211456},{"list":[{"int":-1}]},{"map":[{"k":{"int":1},"v":{"bytes":""}}]}]}`
+	d, err := plutusdata.DecodeJSON([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := plutusdata.NewConstr(0,
+		plutusdata.Bytes{0xca, 0xfe},
+		bigInt("340282366920938463463374607431768211456"),
+		plutusdata.List{plutusdata.NewInt(-1)},
+		plutusdata.Map{{Key: plutusdata.NewInt(1), Value: plutusdata.Bytes{}}},
+	)
+	if !plutusdata.Equal(d, want) {
+		t.Fatalf("got %#v, want %#v", d, want)
+	}
+	out, err := plutusdata.EncodeJSON(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != in {
+		t.Fatalf("got %s, want %s", out, in)
+	}
+
+	for _, s := range []string{`[]`, `{"int":1.5}`, `{"bytes":"zz"}`, `{"foo":1}`, `{"int":1,"bytes":""}`} {
+		if _, err := plutusdata.DecodeJSON([]byte(s)); !errors.Is(err, plutusdata.ErrInvalidData) {
+			t.Errorf("%s: got %v, want ErrInvalidData", s, err)
+		}
+	}
+}
+
+func TestScriptDatumFixtures(t *testing.T) {
+	var sd blockfrost.ScriptDatum
+	var sdc blockfrost.ScriptDatumCBOR
+	for name, v := range map[string]interface{}{
+		"integrationresourcescriptdatum.golden":     &sd,
+		"integrationresourcescriptdatumcbor.golden": &sdc,
+	} {
+		data, err := os.ReadFile(filepath.Join(testdata, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fromJSON, err := sd.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromCBOR, err := sdc.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plutusdata.Equal(fromJSON, fromCBOR) {
+		t.Fatalf("got %#v and %#v", fromJSON, fromCBOR)
+	}
+	if got := mustEncodeHex(t, fromJSON); got != sdc.CBOR {
+		t.Fatalf("got %s, want %s", got, sdc.CBOR)
+	}
+}
+
+type assetClass struct {
+	Policy string `plutus:",hex"`
+	Name   []byte
+}
+
+type poolDatum struct {
+	_          struct{} `plutus:"constr=0"`
+	AssetA     assetClass
+	AssetB     assetClass
+	Shares     *big.Int
+	Fee        uint16
+	Open       bool
+	StakeKey   *[28]byte `plutus:",optional"`
+	Extra      plutusdata.PlutusData
+	Cache      string `plutus:"-"`
+	unexported int
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	// A pool datum as found on chain: constructor 0 holding two asset
+	// classes, the total shares, the fee, a flag, a Maybe and an extension.
+	raw := "d8799fd8799f40" + "40ff" +
+		"d8799f581c" + "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c" + "44746f6b65ff" +
+		"c249010000000000000000" + "1903e8" + "d87a80" + "d87a80" + "d87980ff"
+
+	d := mustDecodeHex(t, raw)
+	var got poolDatum
+	got.Cache = "kept"
+	if err := plutusdata.Unmarshal(d, &got); err != nil {
+		t.Fatal(err)
+	}
+	shares, _ := new(big.Int).SetString("18446744073709551616", 10)
+	want := poolDatum{
+		AssetA:   assetClass{Policy: "", Name: []byte{}},
+		AssetB:   assetClass{Policy: "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c", Name: []byte("toke")},
+		Shares:   shares,
+		Fee:      1000,
+		Open:     true,
+		StakeKey: nil,
+		Extra:    plutusdata.NewConstr(0),
+		Cache:    "kept",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	back, err := plutusdata.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc := mustEncodeHex(t, back); enc != raw {
+		t.Fatalf("got %s, want %s", enc, raw)
+	}
+
+	key := [28]byte{1}
+	got.StakeKey = &key
+	back, err = plutusdata.Marshal(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again poolDatum
+	if err := plutusdata.Unmarshal(back, &again); err != nil {
+		t.Fatal(err)
+	}
+	if again.StakeKey == nil || *again.StakeKey != key {
+		t.Fatalf("got %v, want %v", again.StakeKey, key)
+	}
+}
+
+func TestUnmarshalMismatch(t *testing.T) {
+	var pd poolDatum
+	tests := []struct {
+		name string
+		data plutusdata.PlutusData
+		v    interface{}
+	}{
+		{"wrong constructor", plutusdata.NewConstr(1), &pd},
+		{"wrong field count", plutusdata.NewConstr(0, plutusdata.NewInt(1)), &pd},
+		{"int overflow", plutusdata.NewInt(256), new(uint8)},
+		{"negative uint", plutusdata.NewInt(-1), new(uint64)},
+		{"bytes for int", plutusdata.Bytes{}, new(int)},
+		{"bool", plutusdata.NewConstr(2), new(bool)},
+		{"byte array length", plutusdata.Bytes{1}, new([2]byte)},
+		{"list", plutusdata.Map{}, new([]int)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := plutusdata.Unmarshal(test.data, test.v); !errors.Is(err, plutusdata.ErrMismatch) {
+				t.Fatalf("got %v, want ErrMismatch", err)
+			}
+		})
+	}
+
+	if err := plutusdata.Unmarshal(plutusdata.NewInt(1), 1); err == nil {
+		t.Fatal("expected error for non-pointer")
+	}
+}
+
+func TestMarshalMap(t *testing.T) {
+	d, err := plutusdata.Marshal(map[string]int64{"b": 2, "a": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustEncodeHex(t, d); got != "a2416101416202" {
+		t.Fatalf("got %s", got)
+	}
+	var m map[string]int64
+	if err := plutusdata.Unmarshal(d, &m); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, map[string]int64{"a": 1, "b": 2}) {
+		t.Fatalf("got %v", m)
+	}
+}
@@ -0,0 +1,443 @@
+package plutusdata
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrMismatch is returned by Unmarshal when Plutus data does not match the
+// shape of the Go value.
+var ErrMismatch = errors.New("plutusdata: value does not match type")
+
+var (
+	plutusDataType = reflect.TypeOf((*PlutusData)(nil)).Elem()
+	bigIntType     = reflect.TypeOf(big.Int{})
+)
+
+// options are the options of a `plutus` struct tag:
+//
+//	constr=N  the value is a constructor with tag N
+//	hex       a string holds the hex encoding of bytes
+//	optional  a pointer is a Maybe: Constr 0 [x] or Constr 1 []
+//	-         the field is ignored
+type options struct {
+	constr   *uint64
+	hex      bool
+	optional bool
+	skip     bool
+}
+
+func parseOptions(tag string) (options, error) {
+	var o options
+	if tag == "-" {
+		o.skip = true
+		return o, nil
+	}
+	for _, opt := range strings.Split(tag, ",") {
+		switch {
+		case opt == "":
+		case opt == "hex":
+			o.hex = true
+		case opt == "optional":
+			o.optional = true
+		case strings.HasPrefix(opt, "constr="):
+			n, err := strconv.ParseUint(strings.TrimPrefix(opt, "constr="), 10, 64)
+			if err != nil {
+				return o, fmt.Errorf("plutusdata: invalid tag %q", tag)
+			}
+			o.constr = &n
+		default:
+			return o, fmt.Errorf("plutusdata: unknown tag option %q", opt)
+		}
+	}
+	return o, nil
+}
+
+type structField struct {
+	index int
+	name  string
+	opts  options
+}
+
+// structInfo returns the fields of a struct mapped to constructor fields,
+// and the constructor tag declared by a blank field such as
+// `_ struct{} plutus:"constr=0"`.
+func structInfo(t reflect.Type) (fields []structField, constr *uint64, err error) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		opts, err := parseOptions(f.Tag.Get("plutus"))
+		if err != nil {
+			return nil, nil, err
+		}
+		if f.Name == "_" {
+			if opts.constr != nil {
+				constr = opts.constr
+			}
+			continue
+		}
+		if opts.skip || !f.IsExported() {
+			continue
+		}
+		fields = append(fields, structField{index: i, name: f.Name, opts: opts})
+	}
+	return fields, constr, nil
+}
+
+// Unmarshal stores Plutus data in the value pointed to by v. Go types map to
+// Plutus data as follows:
+//
+//   - PlutusData receives the value as is.
+//   - Integers and *big.Int are Int.
+//   - []byte, byte arrays and strings are Bytes. Strings hold the bytes as
+//     is, or their hex encoding with the "hex" option.
+//   - bool is Constr 0 [] for false and Constr 1 [] for true.
+//   - Slices are List and maps are Map.
+//   - Structs are Constr, with exported fields mapped in order. The
+//     constructor tag is checked when set with the "constr=N" option,
+//     either on the field holding the struct or on a blank field of the
+//     struct.
+//   - Pointers are allocated as needed. With the "optional" option they
+//     are Maybe values: Constr 0 [x] for x and Constr 1 [] for nil.
+func Unmarshal(data PlutusData, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("plutusdata: Unmarshal requires a non-nil pointer, got %T", v)
+	}
+	return unmarshal(data, rv.Elem(), options{}, "")
+}
+
+func mismatch(path string, format string, args ...interface{}) error {
+	if path == "" {
+		path = "value"
+	}
+	return fmt.Errorf("%w: %s: %s", ErrMismatch, path, fmt.Sprintf(format, args...))
+}
+
+func unmarshal(data PlutusData, rv reflect.Value, opts options, path string) error {
+	t := rv.Type()
+	if t == plutusDataType {
+		rv.Set(reflect.ValueOf(&data).Elem())
+		return nil
+	}
+	if t.Kind() != reflect.Pointer && t.Implements(plutusDataType) {
+		d := reflect.ValueOf(data)
+		if !d.IsValid() || d.Type() != t {
+			return mismatch(path, "expected %s, got %s", t.Name(), describe(data))
+		}
+		rv.Set(d)
+		return nil
+	}
+
+	if t.Kind() == reflect.Pointer {
+		if opts.optional {
+			c, ok := data.(Constr)
+			switch {
+			case ok && c.Tag == 1 && len(c.Fields) == 0:
+				rv.Set(reflect.Zero(t))
+				return nil
+			case ok && c.Tag == 0 && len(c.Fields) == 1:
+				data = c.Fields[0]
+			default:
+				return mismatch(path, "expected Maybe, got %s", describe(data))
+			}
+			opts.optional = false
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(t.Elem()))
+		}
+		if t.Elem() == bigIntType {
+			n, ok := data.(Int)
+			if !ok || n.Int == nil {
+				return mismatch(path, "expected Int, got %s", describe(data))
+			}
+			rv.Elem().Set(reflect.ValueOf(*new(big.Int).Set(n.Int)))
+			return nil
+		}
+		return unmarshal(data, rv.Elem(), opts, path)
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := data.(Int)
+		if !ok || n.Int == nil {
+			return mismatch(path, "expected Int, got %s", describe(data))
+		}
+		if !n.IsInt64() || rv.OverflowInt(n.Int64()) {
+			return mismatch(path, "%s overflows %s", n, t)
+		}
+		rv.SetInt(n.Int64())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := data.(Int)
+		if !ok || n.Int == nil {
+			return mismatch(path, "expected Int, got %s", describe(data))
+		}
+		if n.Sign() < 0 || !n.IsUint64() || rv.OverflowUint(n.Uint64()) {
+			return mismatch(path, "%s overflows %s", n, t)
+		}
+		rv.SetUint(n.Uint64())
+		return nil
+	case reflect.Bool:
+		c, ok := data.(Constr)
+		if !ok || c.Tag > 1 || len(c.Fields) != 0 {
+			return mismatch(path, "expected Bool, got %s", describe(data))
+		}
+		rv.SetBool(c.Tag == 1)
+		return nil
+	case reflect.String:
+		b, ok := data.(Bytes)
+		if !ok {
+			return mismatch(path, "expected Bytes, got %s", describe(data))
+		}
+		if opts.hex {
+			rv.SetString(hex.EncodeToString(b))
+		} else {
+			rv.SetString(string(b))
+		}
+		return nil
+	case reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		b, ok := data.(Bytes)
+		if !ok || len(b) != t.Len() {
+			return mismatch(path, "expected %d Bytes, got %s", t.Len(), describe(data))
+		}
+		reflect.Copy(rv, reflect.ValueOf([]byte(b)))
+		return nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b, ok := data.(Bytes)
+			if !ok {
+				return mismatch(path, "expected Bytes, got %s", describe(data))
+			}
+			rv.SetBytes(append([]byte{}, b...))
+			return nil
+		}
+		l, ok := data.(List)
+		if !ok {
+			return mismatch(path, "expected List, got %s", describe(data))
+		}
+		out := reflect.MakeSlice(t, len(l), len(l))
+		for i, item := range l {
+			if err := unmarshal(item, out.Index(i), options{}, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(out)
+		return nil
+	case reflect.Map:
+		m, ok := data.(Map)
+		if !ok {
+			return mismatch(path, "expected Map, got %s", describe(data))
+		}
+		out := reflect.MakeMapWithSize(t, len(m))
+		for i, p := range m {
+			k := reflect.New(t.Key()).Elem()
+			if err := unmarshal(p.Key, k, opts, fmt.Sprintf("%s{key %d}", path, i)); err != nil {
+				return err
+			}
+			v := reflect.New(t.Elem()).Elem()
+			if err := unmarshal(p.Value, v, options{}, fmt.Sprintf("%s{value %d}", path, i)); err != nil {
+				return err
+			}
+			out.SetMapIndex(k, v)
+		}
+		rv.Set(out)
+		return nil
+	case reflect.Struct:
+		return unmarshalStruct(data, rv, opts, path)
+	}
+	return fmt.Errorf("plutusdata: unsupported type %s", t)
+}
+
+func unmarshalStruct(data PlutusData, rv reflect.Value, opts options, path string) error {
+	fields, constr, err := structInfo(rv.Type())
+	if err != nil {
+		return err
+	}
+	if opts.constr != nil {
+		constr = opts.constr
+	}
+	c, ok := data.(Constr)
+	if !ok {
+		return mismatch(path, "expected Constr, got %s", describe(data))
+	}
+	if constr != nil && c.Tag != *constr {
+		return mismatch(path, "expected constructor %d, got %d", *constr, c.Tag)
+	}
+	if len(c.Fields) != len(fields) {
+		return mismatch(path, "expected %d fields, got %d", len(fields), len(c.Fields))
+	}
+	for i, f := range fields {
+		name := f.name
+		if path != "" {
+			name = path + "." + f.name
+		}
+		if err := unmarshal(c.Fields[i], rv.Field(f.index), f.opts, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func describe(data PlutusData) string {
+	switch v := data.(type) {
+	case Constr:
+		return fmt.Sprintf("Constr %d with %d fields", v.Tag, len(v.Fields))
+	case Map:
+		return "Map"
+	case List:
+		return "List"
+	case Int:
+		return "Int"
+	case Bytes:
+		return "Bytes"
+	}
+	return fmt.Sprintf("%T", data)
+}
+
+// Marshal returns the Plutus data representation of v, following the
+// mapping described in Unmarshal. Structs without a constructor tag are
+// encoded as constructor 0, and map entries are sorted by the CBOR
+// encoding of their keys.
+func Marshal(v interface{}) (PlutusData, error) {
+	return marshal(reflect.ValueOf(v), options{})
+}
+
+func marshal(rv reflect.Value, opts options) (PlutusData, error) {
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("plutusdata: cannot marshal nil")
+	}
+	t := rv.Type()
+	if t == plutusDataType || t.Implements(plutusDataType) && t.Kind() != reflect.Pointer {
+		if t.Kind() == reflect.Interface && rv.IsNil() {
+			return nil, fmt.Errorf("plutusdata: cannot marshal nil")
+		}
+		return rv.Interface().(PlutusData), nil
+	}
+
+	if t.Kind() == reflect.Pointer {
+		if opts.optional {
+			if rv.IsNil() {
+				return NewConstr(1), nil
+			}
+			opts.optional = false
+			d, err := marshal(rv, opts)
+			if err != nil {
+				return nil, err
+			}
+			return NewConstr(0, d), nil
+		}
+		if rv.IsNil() {
+			return nil, fmt.Errorf("plutusdata: cannot marshal nil %s", t)
+		}
+		if t.Elem() == bigIntType {
+			return Int{new(big.Int).Set(rv.Interface().(*big.Int))}, nil
+		}
+		return marshal(rv.Elem(), opts)
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Int{new(big.Int).SetUint64(rv.Uint())}, nil
+	case reflect.Bool:
+		if rv.Bool() {
+			return NewConstr(1), nil
+		}
+		return NewConstr(0), nil
+	case reflect.String:
+		if opts.hex {
+			b, err := hex.DecodeString(rv.String())
+			if err != nil {
+				return nil, fmt.Errorf("plutusdata: invalid hex string: %w", err)
+			}
+			return Bytes(b), nil
+		}
+		return Bytes(rv.String()), nil
+	case reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		b := make([]byte, t.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return Bytes(b), nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Bytes(append([]byte{}, rv.Bytes()...)), nil
+		}
+		l := make(List, rv.Len())
+		for i := range l {
+			d, err := marshal(rv.Index(i), options{})
+			if err != nil {
+				return nil, err
+			}
+			l[i] = d
+		}
+		return l, nil
+	case reflect.Map:
+		return marshalMap(rv, opts)
+	case reflect.Struct:
+		fields, constr, err := structInfo(t)
+		if err != nil {
+			return nil, err
+		}
+		if opts.constr != nil {
+			constr = opts.constr
+		}
+		c := NewConstr(0)
+		if constr != nil {
+			c.Tag = *constr
+		}
+		for _, f := range fields {
+			d, err := marshal(rv.Field(f.index), f.opts)
+			if err != nil {
+				return nil, err
+			}
+			c.Fields = append(c.Fields, d)
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("plutusdata: unsupported type %s", t)
+}
+
+func marshalMap(rv reflect.Value, opts options) (PlutusData, error) {
+	type entry struct {
+		key []byte
+		p   Pair
+	}
+	entries := make([]entry, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		k, err := marshal(iter.Key(), opts)
+		if err != nil {
+			return nil, err
+		}
+		v, err := marshal(iter.Value(), options{})
+		if err != nil {
+			return nil, err
+		}
+		enc, err := Encode(k)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{key: enc, p: Pair{Key: k, Value: v}})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	m := make(Map, len(entries))
+	for i, e := range entries {
+		m[i] = e.p
+	}
+	return m, nil
+}
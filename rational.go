@@ -0,0 +1,115 @@
+package blockfrost
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRational is returned when parsing a value that is not a
+// rational number.
+var ErrInvalidRational = errors.New("invalid rational")
+
+// Rational is an exact rational number. The zero value is 0.
+//
+// It is decoded from JSON numbers, such as 0.0000721, and from strings
+// holding a decimal or a fraction, such as "0.0000721" or "721/10000000",
+// without going through floating point.
+//
+// EpochParameters, Pool, PoolHistory and PoolExtended hold the exact values
+// of their fractional fields in their Rational field when decoded from JSON,
+// and their Rational accessors fall back to the shortest decimal rounding
+// to the float field for values built in code.
+type Rational struct {
+	rat *big.Rat
+}
+
+// NewRational returns the rational num/den. It panics if den is 0.
+func NewRational(num, den int64) Rational {
+	return Rational{big.NewRat(num, den)}
+}
+
+// ParseRational parses a decimal, in plain or scientific notation, or a
+// fraction.
+func ParseRational(s string) (Rational, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Rational{}, fmt.Errorf("%w: %q", ErrInvalidRational, s)
+	}
+	return Rational{r}, nil
+}
+
+// rationalFromFloat returns the shortest decimal that rounds to f at the
+// given precision, which is the value f was decoded from unless that one
+// had more significant digits than the precision holds.
+func rationalFromFloat(f float64, bitSize int) Rational {
+	r, _ := ParseRational(strconv.FormatFloat(f, 'g', -1, bitSize))
+	return r
+}
+
+// Rat returns r as a new big.Rat.
+func (r Rational) Rat() *big.Rat {
+	if r.rat == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(r.rat)
+}
+
+// Float64 returns the nearest float64 value of r.
+func (r Rational) Float64() float64 {
+	f, _ := r.Rat().Float64()
+	return f
+}
+
+// Cmp compares r and s and returns -1, 0 or +1.
+func (r Rational) Cmp(s Rational) int {
+	return r.Rat().Cmp(s.Rat())
+}
+
+// String returns r as a fraction "num/den", or as an integer if its
+// denominator is 1.
+func (r Rational) String() string {
+	return r.Rat().RatString()
+}
+
+// MarshalJSON encodes r as a string holding a fraction.
+func (r Rational) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON decodes r from a JSON number or string.
+func (r *Rational) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	v, err := ParseRational(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// rationalFromFloat32 returns *f as a Rational, or nil if f is nil.
+func rationalFromFloat32(f *float32) *Rational {
+	if f == nil {
+		return nil
+	}
+	r := rationalFromFloat(float64(*f), 32)
+	return &r
+}
+
+// rationalFromFloat64 returns *f as a Rational, or nil if f is nil.
+func rationalFromFloat64(f *float64) *Rational {
+	if f == nil {
+		return nil
+	}
+	r := rationalFromFloat(*f, 64)
+	return &r
+}
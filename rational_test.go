@@ -0,0 +1,202 @@
+package blockfrost_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+func TestParseRational(t *testing.T) {
+	tests := []struct {
+		in   string
+		want *big.Rat
+	}{
+		{"0.0000721", big.NewRat(721, 10000000)},
+		{"7.21e-05", big.NewRat(721, 10000000)},
+		{"721/10000000", big.NewRat(721, 10000000)},
+		{" 3 ", big.NewRat(3, 1)},
+		{"-0.5", big.NewRat(-1, 2)},
+	}
+	for _, test := range tests {
+		got, err := blockfrost.ParseRational(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Rat().Cmp(test.want) != 0 {
+			t.Fatalf("%q: got %s, want %s", test.in, got, test.want.RatString())
+		}
+	}
+
+	for _, in := range []string{"", "abc", "1/0"} {
+		if _, err := blockfrost.ParseRational(in); !errors.Is(err, blockfrost.ErrInvalidRational) {
+			t.Fatalf("%q: got %v, want ErrInvalidRational", in, err)
+		}
+	}
+
+	var zero blockfrost.Rational
+	if zero.String() != "0" || zero.Float64() != 0 {
+		t.Fatalf("got %s", zero)
+	}
+}
+
+func TestRationalJSON(t *testing.T) {
+	var v struct {
+		A blockfrost.Rational `json:"a"`
+		B blockfrost.Rational `json:"b"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":0.0000721,"b":"2/3"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A.Cmp(blockfrost.NewRational(721, 10000000)) != 0 || v.B.Cmp(blockfrost.NewRational(2, 3)) != 0 {
+		t.Fatalf("got %s and %s", v.A, v.B)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"a":"721/10000000","b":"2/3"}` {
+		t.Fatalf("got %s", out)
+	}
+	if err := json.Unmarshal([]byte(`{"a":true}`), &v); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestEpochParametersRationals(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(testdata, "epochparametersintegration.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p blockfrost.EpochParameters
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  blockfrost.Rational
+		want blockfrost.Rational
+	}{
+		{"a0", p.A0Rational(), blockfrost.NewRational(3, 10)},
+		{"rho", p.RhoRational(), blockfrost.NewRational(3, 1000)},
+		{"tau", p.TauRational(), blockfrost.NewRational(1, 5)},
+		{"decentralisation_param", p.DecentralisationParamRational(), blockfrost.NewRational(0, 1)},
+		{"price_mem", *p.PriceMemRational(), blockfrost.NewRational(577, 10000)},
+		{"price_step", *p.PriceStepRational(), blockfrost.NewRational(721, 10000000)},
+	}
+	for _, test := range tests {
+		if test.got.Cmp(test.want) != 0 {
+			t.Errorf("%s: got %s, want %s", test.name, test.got, test.want)
+		}
+	}
+	if p.MinFeeRefScriptCostPerByteRational() != nil || p.DvtTreasuryWithdrawalRational() != nil {
+		t.Fatal("expected unset parameters to be nil")
+	}
+
+	// Values beyond the precision of the float fields are decoded exactly.
+	if err := json.Unmarshal([]byte(`{"price_step":0.000072100001,"pvt_committee_normal":0.51}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.PriceStepRational().Rat(); got.Cmp(big.NewRat(72100001, 1e12)) != 0 {
+		t.Fatalf("got price_step %s", got.RatString())
+	}
+	if got := p.Rational.PvtCommitteeNormal; got == nil || got.Cmp(blockfrost.NewRational(51, 100)) != 0 {
+		t.Fatalf("got pvt_committee_normal %v", got)
+	}
+
+	// Values built in code are read from the float fields.
+	step := float32(0.0000577)
+	if got := *(blockfrost.EpochParameters{PriceStep: &step}).PriceStepRational(); got.Cmp(blockfrost.NewRational(577, 10000000)) != 0 {
+		t.Fatalf("got price_step %s", got)
+	}
+	if got := (blockfrost.EpochParameters{Rho: 0.003}).RhoRational(); got.Cmp(blockfrost.NewRational(3, 1000)) != 0 {
+		t.Fatalf("got rho %s", got)
+	}
+}
+
+func TestDecodeEpochParametersRational(t *testing.T) {
+	// Values beyond the precision of the float fields are decoded exactly.
+	epr, err := blockfrost.DecodeEpochParametersRational([]byte(`{"epoch":500,"a0":0.3,"price_step":0.000072100001,"pvt_committee_normal":"51/100","min_fee_ref_script_cost_per_byte":15,"tau":null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  *blockfrost.Rational
+		want blockfrost.Rational
+	}{
+		{"a0", epr.A0, blockfrost.NewRational(3, 10)},
+		{"price_step", epr.PriceStep, blockfrost.NewRational(72100001, 1000000000000)},
+		{"pvt_committee_normal", epr.PvtCommitteeNormal, blockfrost.NewRational(51, 100)},
+		{"min_fee_ref_script_cost_per_byte", epr.MinFeeRefScriptCostPerByte, blockfrost.NewRational(15, 1)},
+	}
+	for _, test := range tests {
+		if test.got == nil || test.got.Cmp(test.want) != 0 {
+			t.Errorf("%s: got %v, want %s", test.name, test.got, test.want)
+		}
+	}
+	if epr.Tau != nil || epr.PriceMem != nil {
+		t.Fatal("expected unset parameters to be nil")
+	}
+	if _, err := blockfrost.DecodeEpochParametersRational([]byte(`{"rho":"x"}`)); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestPoolRationals(t *testing.T) {
+	var pool blockfrost.Pool
+	if err := json.Unmarshal([]byte(`{"pool_id":"pool1","active_size":0.00109213419803437,"margin_cost":0.015}`), &pool); err != nil {
+		t.Fatal(err)
+	}
+	if pool.PoolID != "pool1" || pool.MarginCost != 0.015 {
+		t.Fatalf("unexpected pool %+v", pool)
+	}
+	if got := pool.MarginCostRational(); got.Cmp(blockfrost.NewRational(3, 200)) != 0 {
+		t.Fatalf("got margin_cost %s", got)
+	}
+	if got := pool.ActiveSizeRational(); got.Cmp(blockfrost.NewRational(109213419803437, 100000000000000000)) != 0 {
+		t.Fatalf("got active_size %s", got)
+	}
+
+	var history []blockfrost.PoolHistory
+	if err := json.Unmarshal([]byte(`[{"epoch":1,"active_size":0.25}]`), &history); err != nil {
+		t.Fatal(err)
+	}
+	if got := history[0].ActiveSizeRational(); history[0].Epoch != 1 || history[0].ActiveSize != 0.25 || got.Cmp(blockfrost.NewRational(1, 4)) != 0 {
+		t.Fatalf("got %+v, active_size %s", history[0], got)
+	}
+
+	var extended blockfrost.PoolExtended
+	if err := json.Unmarshal([]byte(`{"margin_cost":0.01}`), &extended); err != nil {
+		t.Fatal(err)
+	}
+	if got := extended.MarginCostRational(); got.Cmp(blockfrost.NewRational(1, 100)) != 0 {
+		t.Fatalf("got margin_cost %s", got)
+	}
+}
+
+func TestDecodePoolRational(t *testing.T) {
+	pr, err := blockfrost.DecodePoolRational([]byte(`{"pool_id":"pool1","active_size":0.001092134198034371,"margin_cost":0.015}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.ActiveSize == nil || pr.ActiveSize.Cmp(blockfrost.NewRational(1092134198034371, 1000000000000000000)) != 0 {
+		t.Fatalf("got active_size %v", pr.ActiveSize)
+	}
+	if pr.MarginCost == nil || pr.MarginCost.Cmp(blockfrost.NewRational(3, 200)) != 0 {
+		t.Fatalf("got margin_cost %v", pr.MarginCost)
+	}
+
+	pr, err = blockfrost.DecodePoolRational([]byte(`{"epoch":1,"active_size":0.25}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.MarginCost != nil || pr.ActiveSize.Cmp(blockfrost.NewRational(1, 4)) != 0 {
+		t.Fatalf("got %+v", pr)
+	}
+}
@@ -0,0 +1,207 @@
+package signing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"filippo.io/edwards25519"
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+// Signer signs transactions with an Ed25519 key.
+type Signer interface {
+	// PublicKey returns the public key of the signer.
+	PublicKey() ed25519.PublicKey
+
+	// Sign returns the Ed25519 signature of message, the hash of a
+	// transaction body.
+	Sign(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// KeyHash returns the hash of pub, as found in addresses and required
+// signers.
+func KeyHash(pub ed25519.PublicKey) ledger.Hash28 {
+	return ledger.Blake2b224(pub)
+}
+
+// Ed25519Key is an in-memory Ed25519 key.
+type Ed25519Key struct {
+	key ed25519.PrivateKey
+}
+
+// NewEd25519Key returns the key of a 32 bytes seed, as held by Cardano
+// signing keys that are not extended.
+func NewEd25519Key(seed []byte) (*Ed25519Key, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidKey, len(seed), ed25519.SeedSize)
+	}
+	return &Ed25519Key{ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+// PublicKey returns the public key of k.
+func (k *Ed25519Key) PublicKey() ed25519.PublicKey {
+	return k.key.Public().(ed25519.PublicKey)
+}
+
+// Sign signs message with k.
+func (k *Ed25519Key) Sign(_ context.Context, message []byte) ([]byte, error) {
+	return ed25519.Sign(k.key, message), nil
+}
+
+// ExtendedKey is an in-memory BIP32-Ed25519 extended key, as derived by
+// wallets from their recovery phrase.
+type ExtendedKey struct {
+	// kL || kR
+	key       [64]byte
+	chainCode []byte
+	pub       ed25519.PublicKey
+}
+
+// NewExtendedKey returns the extended key of the 64 bytes kL || kR,
+// optionally followed by a 32 bytes chain code.
+func NewExtendedKey(b []byte) (*ExtendedKey, error) {
+	if len(b) != 64 && len(b) != 96 {
+		return nil, fmt.Errorf("%w: got %d bytes, want 64 or 96", ErrInvalidKey, len(b))
+	}
+	if b[0]&7 != 0 || b[31]&0x80 != 0 {
+		return nil, fmt.Errorf("%w: not a BIP32-Ed25519 extended key", ErrInvalidKey)
+	}
+	k := &ExtendedKey{}
+	copy(k.key[:], b)
+	if len(b) == 96 {
+		k.chainCode = append([]byte{}, b[64:]...)
+	}
+	s := k.scalar()
+	k.pub = new(edwards25519.Point).ScalarBaseMult(s).Bytes()
+	return k, nil
+}
+
+// scalar returns kL reduced modulo the group order.
+func (k *ExtendedKey) scalar() *edwards25519.Scalar {
+	var wide [64]byte
+	copy(wide[:], k.key[:32])
+	s, _ := edwards25519.NewScalar().SetUniformBytes(wide[:])
+	return s
+}
+
+// PublicKey returns the public key of k.
+func (k *ExtendedKey) PublicKey() ed25519.PublicKey {
+	return append(ed25519.PublicKey{}, k.pub...)
+}
+
+// ChainCode returns the chain code of k, or nil if it was created without
+// one.
+func (k *ExtendedKey) ChainCode() []byte {
+	return append([]byte(nil), k.chainCode...)
+}
+
+// Sign signs message with k. Signatures are Ed25519 signatures, made with
+// kL as the secret scalar and kR as the nonce prefix.
+func (k *ExtendedKey) Sign(_ context.Context, message []byte) ([]byte, error) {
+	h := sha512.New()
+	h.Write(k.key[32:])
+	h.Write(message)
+	r, _ := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	R := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	h.Reset()
+	h.Write(R)
+	h.Write(k.pub)
+	h.Write(message)
+	c, _ := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	S := edwards25519.NewScalar().MultiplyAdd(c, k.scalar(), r)
+	return append(R, S.Bytes()...), nil
+}
+
+// SignFunc returns the Ed25519 signature of message, made by a remote key
+// such as one held by a hardware security module.
+type SignFunc func(ctx context.Context, message []byte) ([]byte, error)
+
+// RemoteSigner is a Signer whose signatures are made by a SignFunc.
+// Signatures are verified against the public key before being used.
+type RemoteSigner struct {
+	pub  ed25519.PublicKey
+	sign SignFunc
+}
+
+// NewRemoteSigner returns a signer for the public key pub, signing with
+// sign.
+func NewRemoteSigner(pub ed25519.PublicKey, sign SignFunc) (*RemoteSigner, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: got %d bytes of public key, want %d", ErrInvalidKey, len(pub), ed25519.PublicKeySize)
+	}
+	return &RemoteSigner{pub: append(ed25519.PublicKey{}, pub...), sign: sign}, nil
+}
+
+// PublicKey returns the public key of s.
+func (s *RemoteSigner) PublicKey() ed25519.PublicKey {
+	return append(ed25519.PublicKey{}, s.pub...)
+}
+
+// Sign signs message with the remote key.
+func (s *RemoteSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	sig, err := s.sign(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) != ed25519.SignatureSize || !ed25519.Verify(s.pub, message, sig) {
+		return nil, fmt.Errorf("%w: remote signature does not verify", ErrInvalidSignature)
+	}
+	return sig, nil
+}
+
+// textEnvelope is the JSON format of key files written by cardano-cli.
+type textEnvelope struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	CBORHex     string `json:"cborHex"`
+}
+
+// ParseTextEnvelope parses a signing key file written by cardano-cli, such
+// as payment.skey. Both normal keys, of types ending in "_ed25519", and
+// extended keys, of types ending in "_ed25519_bip32", are supported.
+func ParseTextEnvelope(data []byte) (Signer, error) {
+	var env textEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	raw, err := hex.DecodeString(env.CBORHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	d := cbor.NewDecoder(raw)
+	key, err := d.ReadBytes()
+	if err == nil {
+		err = d.Finish()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+
+	switch {
+	case !strings.Contains(env.Type, "SigningKey"):
+		return nil, fmt.Errorf("%w: %s is not a signing key", ErrInvalidKey, env.Type)
+	case strings.HasSuffix(env.Type, "_ed25519"):
+		return NewEd25519Key(key)
+	case strings.HasSuffix(env.Type, "_ed25519_bip32"):
+		// kL || kR || public key || chain code
+		if len(key) != 128 {
+			return nil, fmt.Errorf("%w: got %d bytes of extended key, want 128", ErrInvalidKey, len(key))
+		}
+		k, err := NewExtendedKey(append(key[:64:64], key[96:]...))
+		if err != nil {
+			return nil, err
+		}
+		if !k.pub.Equal(ed25519.PublicKey(key[64:96])) {
+			return nil, fmt.Errorf("%w: public key does not match", ErrInvalidKey)
+		}
+		return k, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported key type %s", ErrInvalidKey, env.Type)
+}
@@ -0,0 +1,128 @@
+package signing
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+// RequiredSigners returns the key hashes whose signatures tx requires,
+// sorted: the payment keys of the UTXOs spent by its inputs and
+// collateral, the keys of its certificates, withdrawals and votes, and its
+// required signers.
+//
+// utxos must hold the UTXOs spent by tx, as returned by AddressUTXOs or
+// TransactionUTXOs. Signatures required by native scripts are not included,
+// see package nativescript, and neither are the bootstrap witnesses of
+// Byron addresses.
+func RequiredSigners(tx *ledger.Transaction, utxos []blockfrost.AddressUTXO) ([]ledger.Hash28, error) {
+	set := map[ledger.Hash28]bool{}
+	addCredential := func(c address.Credential) {
+		if !c.IsZero() && c.Type == address.CredentialKey {
+			set[c.Hash] = true
+		}
+	}
+
+	body := &tx.Body
+	inputs := append(append([]ledger.TransactionInput{}, body.Inputs...), body.Collateral...)
+	for _, in := range inputs {
+		u, ok := findUTXO(utxos, in)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s#%d", ErrUnknownInput, in.TxID, in.Index)
+		}
+		addr, err := address.Parse(u.Address)
+		if err != nil {
+			return nil, fmt.Errorf("signing: utxo %s#%d: %w", in.TxID, in.Index, err)
+		}
+		if addr.Byron == nil {
+			addCredential(addr.Payment)
+		}
+	}
+
+	for _, c := range body.Certificates {
+		switch c.Type {
+		case ledger.CertStakeDeregistration, ledger.CertStakeDelegation,
+			ledger.CertRegistration, ledger.CertUnregistration,
+			ledger.CertVoteDelegation, ledger.CertStakeVoteDelegation,
+			ledger.CertStakeRegistrationDelegation, ledger.CertVoteRegistrationDelegation,
+			ledger.CertStakeVoteRegistrationDelegation,
+			ledger.CertCommitteeHotAuthorization, ledger.CertCommitteeColdResignation,
+			ledger.CertDRepRegistration, ledger.CertDRepUnregistration, ledger.CertDRepUpdate:
+			addCredential(c.Credential)
+		case ledger.CertPoolRegistration:
+			if c.PoolParams != nil {
+				set[c.PoolParams.Operator] = true
+				for _, owner := range c.PoolParams.Owners {
+					set[owner] = true
+				}
+			}
+		case ledger.CertPoolRetirement:
+			set[c.PoolKeyHash] = true
+		}
+	}
+
+	for _, w := range body.Withdrawals {
+		addr, err := address.FromBytes(w.RewardAccount)
+		if err != nil {
+			return nil, fmt.Errorf("signing: withdrawal: %w", err)
+		}
+		addCredential(addr.Stake)
+	}
+
+	for _, v := range body.VotingProcedures {
+		switch v.Voter.Type {
+		case ledger.VoterCommitteeHotKeyHash, ledger.VoterDRepKeyHash, ledger.VoterStakePoolKeyHash:
+			set[v.Voter.Hash] = true
+		}
+	}
+
+	for _, h := range body.RequiredSigners {
+		set[h] = true
+	}
+	return sortHashes(set), nil
+}
+
+// MissingSigners returns the key hashes whose signatures tx requires, as
+// returned by RequiredSigners, and which are not in its witness set yet.
+func MissingSigners(tx *ledger.Transaction, utxos []blockfrost.AddressUTXO) ([]ledger.Hash28, error) {
+	required, err := RequiredSigners(tx, utxos)
+	if err != nil {
+		return nil, err
+	}
+	signed := map[ledger.Hash28]bool{}
+	for _, w := range tx.WitnessSet.VKeyWitnesses {
+		signed[KeyHash(w.VKey[:])] = true
+	}
+	missing := []ledger.Hash28{}
+	for _, h := range required {
+		if !signed[h] {
+			missing = append(missing, h)
+		}
+	}
+	return missing, nil
+}
+
+func findUTXO(utxos []blockfrost.AddressUTXO, in ledger.TransactionInput) (blockfrost.AddressUTXO, bool) {
+	id := in.TxID.String()
+	for _, u := range utxos {
+		if u.TxHash == id && uint64(u.OutputIndex) == in.Index {
+			return u, true
+		}
+	}
+	return blockfrost.AddressUTXO{}, false
+}
+
+func sortHashes(set map[ledger.Hash28]bool) []ledger.Hash28 {
+	out := make([]ledger.Hash28, 0, len(set))
+	for h := range set {
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return bytes.Compare(out[i][:], out[j][:]) < 0
+	})
+	return out
+}
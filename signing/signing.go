@@ -0,0 +1,110 @@
+// Package signing signs transactions offline and reports the signatures
+// they still require.
+//
+// Signatures are added to the witness set of a decoded transaction, while
+// its body, and so its hash, keeps its original encoding:
+//
+//	tx, err := ledger.DecodeTransaction(unsigned)
+//	if err != nil {
+//		return err
+//	}
+//	if err := signing.Sign(ctx, tx, key); err != nil {
+//		return err
+//	}
+//	signed, err := tx.MarshalCBOR()
+package signing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+var (
+	// ErrInvalidKey is returned for keys that cannot be used to sign.
+	ErrInvalidKey = errors.New("signing: invalid key")
+
+	// ErrInvalidSignature is returned for signatures that do not verify.
+	ErrInvalidSignature = errors.New("signing: invalid signature")
+
+	// ErrUnknownInput is returned when the UTXO spent by an input is not
+	// known.
+	ErrUnknownInput = errors.New("signing: unknown input")
+)
+
+// Witness returns the witness of tx signed by s.
+func Witness(ctx context.Context, tx *ledger.Transaction, s Signer) (ledger.VKeyWitness, error) {
+	h := tx.Hash()
+	return witness(ctx, h[:], s)
+}
+
+func witness(ctx context.Context, hash []byte, s Signer) (ledger.VKeyWitness, error) {
+	var w ledger.VKeyWitness
+	pub := s.PublicKey()
+	if len(pub) != len(w.VKey) {
+		return w, fmt.Errorf("%w: got %d bytes of public key", ErrInvalidKey, len(pub))
+	}
+	sig, err := s.Sign(ctx, hash)
+	if err != nil {
+		return w, err
+	}
+	if len(sig) != len(w.Signature) {
+		return w, fmt.Errorf("%w: got %d bytes of signature", ErrInvalidSignature, len(sig))
+	}
+	copy(w.VKey[:], pub)
+	copy(w.Signature[:], sig)
+	return w, nil
+}
+
+// Sign signs tx with signers and adds their witnesses to its witness set.
+func Sign(ctx context.Context, tx *ledger.Transaction, signers ...Signer) error {
+	h := tx.Hash()
+	witnesses := make([]ledger.VKeyWitness, 0, len(signers))
+	for _, s := range signers {
+		w, err := witness(ctx, h[:], s)
+		if err != nil {
+			return err
+		}
+		witnesses = append(witnesses, w)
+	}
+	AddWitnesses(tx, witnesses...)
+	return nil
+}
+
+// SignCBOR signs the CBOR encoded transaction data with signers and returns
+// the encoding of the signed transaction. The encoding of the body and of
+// the existing witnesses is kept.
+func SignCBOR(ctx context.Context, data []byte, signers ...Signer) ([]byte, error) {
+	tx, err := ledger.DecodeTransaction(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := Sign(ctx, tx, signers...); err != nil {
+		return nil, err
+	}
+	return tx.MarshalCBOR()
+}
+
+// AddWitnesses adds witnesses to the witness set of tx, replacing the
+// witnesses of the same keys. The witnesses may have been made elsewhere,
+// for instance by other parties of a multi-signature transaction.
+func AddWitnesses(tx *ledger.Transaction, witnesses ...ledger.VKeyWitness) {
+	if len(witnesses) == 0 {
+		return
+	}
+	merged := append([]ledger.VKeyWitness{}, tx.WitnessSet.VKeyWitnesses...)
+	for _, w := range witnesses {
+		replaced := false
+		for i := range merged {
+			if merged[i].VKey == w.VKey {
+				merged[i], replaced = w, true
+			}
+		}
+		if !replaced {
+			merged = append(merged, w)
+		}
+	}
+	tx.WitnessSet.VKeyWitnesses = merged
+}
@@ -0,0 +1,309 @@
+package signing_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/ledger"
+	"github.com/blockfrost/blockfrost-go/signing"
+)
+
+const testdata = "../testdata"
+
+var ctx = context.Background()
+
+// RFC 8032, test 1
+const (
+	rfcSeed      = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+	rfcPublicKey = "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+)
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func newKey(t *testing.T, seed byte) *signing.Ed25519Key {
+	t.Helper()
+	k, err := signing.NewEd25519Key(bytes.Repeat([]byte{seed}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// extendedFromSeed returns the extended key of an Ed25519 seed, which signs
+// as the Ed25519 key of the seed.
+func extendedFromSeed(t *testing.T, seed []byte) *signing.ExtendedKey {
+	t.Helper()
+	h := sha512.Sum512(seed)
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	k, err := signing.NewExtendedKey(h[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestKeys(t *testing.T) {
+	seed := mustHex(rfcSeed)
+	k, err := signing.NewEd25519Key(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(k.PublicKey()) != rfcPublicKey {
+		t.Fatalf("got public key %x", k.PublicKey())
+	}
+
+	x := extendedFromSeed(t, seed)
+	if hex.EncodeToString(x.PublicKey()) != rfcPublicKey {
+		t.Fatalf("got extended public key %x", x.PublicKey())
+	}
+	for _, msg := range [][]byte{nil, []byte("message"), bytes.Repeat([]byte{7}, 32)} {
+		want, _ := k.Sign(ctx, msg)
+		got, err := x.Sign(ctx, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("got %x, want %x", got, want)
+		}
+	}
+	if x.ChainCode() != nil {
+		t.Fatal("expected no chain code")
+	}
+
+	for _, b := range [][]byte{make([]byte, 31), make([]byte, 63), append([]byte{1}, make([]byte, 63)...)} {
+		if _, err := signing.NewExtendedKey(b); !errors.Is(err, signing.ErrInvalidKey) {
+			t.Errorf("%x: got %v, want ErrInvalidKey", b, err)
+		}
+	}
+	if _, err := signing.NewEd25519Key(make([]byte, 64)); !errors.Is(err, signing.ErrInvalidKey) {
+		t.Fatalf("got %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	k := newKey(t, 1)
+	remote, err := signing.NewRemoteSigner(k.PublicKey(), func(ctx context.Context, msg []byte) ([]byte, error) {
+		return k.Sign(ctx, msg)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := remote.Sign(ctx, []byte("hash"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ed25519.Verify(k.PublicKey(), []byte("hash"), sig) {
+		t.Fatal("signature does not verify")
+	}
+
+	other := newKey(t, 2)
+	wrong, _ := signing.NewRemoteSigner(k.PublicKey(), func(ctx context.Context, msg []byte) ([]byte, error) {
+		return other.Sign(ctx, msg)
+	})
+	if _, err := wrong.Sign(ctx, []byte("hash")); !errors.Is(err, signing.ErrInvalidSignature) {
+		t.Fatalf("got %v, want ErrInvalidSignature", err)
+	}
+
+	failure := errors.New("device unavailable")
+	failing, _ := signing.NewRemoteSigner(k.PublicKey(), func(context.Context, []byte) ([]byte, error) {
+		return nil, failure
+	})
+	if _, err := failing.Sign(ctx, []byte("hash")); !errors.Is(err, failure) {
+		t.Fatalf("got %v, want %v", err, failure)
+	}
+
+	if _, err := signing.NewRemoteSigner(make([]byte, 31), nil); !errors.Is(err, signing.ErrInvalidKey) {
+		t.Fatalf("got %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestParseTextEnvelope(t *testing.T) {
+	envelope := func(typ, cborHex string) []byte {
+		b, _ := json.Marshal(map[string]string{"type": typ, "description": "", "cborHex": cborHex})
+		return b
+	}
+
+	s, err := signing.ParseTextEnvelope(envelope("PaymentSigningKeyShelley_ed25519", "5820"+rfcSeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(s.PublicKey()) != rfcPublicKey {
+		t.Fatalf("got public key %x", s.PublicKey())
+	}
+
+	x := extendedFromSeed(t, mustHex(rfcSeed))
+	h := sha512.Sum512(mustHex(rfcSeed))
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	chainCode := strings.Repeat("cc", 32)
+	extended := hex.EncodeToString(h[:]) + rfcPublicKey + chainCode
+	s, err = signing.ParseTextEnvelope(envelope("PaymentExtendedSigningKeyShelley_ed25519_bip32", "5880"+extended))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.PublicKey().Equal(x.PublicKey()) || hex.EncodeToString(s.(*signing.ExtendedKey).ChainCode()) != chainCode {
+		t.Fatalf("unexpected key %x", s.PublicKey())
+	}
+
+	for _, env := range [][]byte{
+		envelope("PaymentVerificationKeyShelley_ed25519", "5820"+rfcPublicKey),
+		envelope("PaymentSigningKeyShelley_ed25519", "5821"+rfcSeed+"00"),
+		envelope("PaymentExtendedSigningKeyShelley_ed25519_bip32", "5880"+hex.EncodeToString(h[:])+strings.Repeat("00", 32)+chainCode),
+		envelope("PaymentSigningKeyShelley_secp256k1", "5820"+rfcSeed),
+		[]byte("{"),
+	} {
+		if _, err := signing.ParseTextEnvelope(env); !errors.Is(err, signing.ErrInvalidKey) {
+			t.Errorf("%s: got %v, want ErrInvalidKey", env, err)
+		}
+	}
+}
+
+func loadTransaction(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(testdata, "json", "transactions", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := blockfrost.TransactionCBOR{}
+	if err := json.Unmarshal(data, &tc); err != nil {
+		t.Fatal(err)
+	}
+	return mustHex(tc.Cbor)
+}
+
+func TestSignCBOR(t *testing.T) {
+	data := loadTransaction(t, "tx_cbor_conway.json")
+	tx, err := ledger.DecodeTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := tx.Body.MarshalCBOR()
+	existing := len(tx.WitnessSet.VKeyWitnesses)
+
+	k1, k2 := newKey(t, 1), extendedFromSeed(t, bytes.Repeat([]byte{2}, 32))
+	signed, err := signing.SignCBOR(ctx, data, k1, k2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(signed, body) {
+		t.Fatal("the body was re-encoded")
+	}
+	out, err := ledger.DecodeTransaction(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Hash() != tx.Hash() {
+		t.Fatalf("got hash %s, want %s", out.Hash(), tx.Hash())
+	}
+	witnesses := out.WitnessSet.VKeyWitnesses
+	if len(witnesses) != existing+2 {
+		t.Fatalf("got %d witnesses, want %d", len(witnesses), existing+2)
+	}
+	if !reflect.DeepEqual(witnesses[:existing], tx.WitnessSet.VKeyWitnesses) {
+		t.Fatal("existing witnesses were modified")
+	}
+	h := tx.Hash()
+	for i, k := range []signing.Signer{k1, k2} {
+		w := witnesses[existing+i]
+		if !bytes.Equal(w.VKey[:], k.PublicKey()) || !ed25519.Verify(w.VKey[:], h[:], w.Signature[:]) {
+			t.Fatalf("witness %d does not verify", i)
+		}
+	}
+
+	// Signing again with the same key replaces its witness.
+	if err := signing.Sign(ctx, out, k1); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.WitnessSet.VKeyWitnesses) != existing+2 {
+		t.Fatalf("got %d witnesses", len(out.WitnessSet.VKeyWitnesses))
+	}
+
+	if _, err := signing.SignCBOR(ctx, []byte{0x80}, k1); !errors.Is(err, ledger.ErrInvalidTransaction) {
+		t.Fatalf("got %v, want ErrInvalidTransaction", err)
+	}
+}
+
+func TestMissingSigners(t *testing.T) {
+	k1, k2, k3 := newKey(t, 1), newKey(t, 2), newKey(t, 3)
+	h1, h2, h3 := signing.KeyHash(k1.PublicKey()), signing.KeyHash(k2.PublicKey()), signing.KeyHash(k3.PublicKey())
+	pool := ledger.Hash28{9}
+
+	payment, _ := address.NewKeyCredential(h1[:])
+	stake, _ := address.NewKeyCredential(h2[:])
+	script, _ := address.NewScriptCredential(bytes.Repeat([]byte{5}, 28))
+	keyAddr := address.NewBaseAddress(address.Testnet, payment, stake)
+	scriptAddr := address.NewEnterpriseAddress(address.Testnet, script)
+	reward := address.NewRewardAddress(address.Testnet, stake)
+
+	utxos := []blockfrost.AddressUTXO{
+		{Address: keyAddr.String(), TxHash: strings.Repeat("01", 32), OutputIndex: 0},
+		{Address: scriptAddr.String(), TxHash: strings.Repeat("01", 32), OutputIndex: 1},
+	}
+	txID, _ := ledger.Hash32FromHex(strings.Repeat("01", 32))
+	tx := &ledger.Transaction{Body: ledger.TransactionBody{
+		Inputs: []ledger.TransactionInput{{TxID: txID, Index: 0}, {TxID: txID, Index: 1}},
+		Certificates: []ledger.Certificate{
+			{Type: ledger.CertStakeRegistration, Credential: stake},
+			{Type: ledger.CertStakeDelegation, Credential: stake, PoolKeyHash: pool},
+			{Type: ledger.CertStakeDelegation, Credential: script, PoolKeyHash: pool},
+			{Type: ledger.CertPoolRetirement, PoolKeyHash: pool},
+		},
+		Withdrawals:     []ledger.Withdrawal{{RewardAccount: reward.Bytes(), Amount: 1}},
+		RequiredSigners: []ledger.Hash28{h3},
+	}}
+
+	required, err := signing.RequiredSigners(tx, utxos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ledger.Hash28{h1, h2, h3, pool}
+	for _, h := range want {
+		found := false
+		for _, r := range required {
+			found = found || r == h
+		}
+		if !found {
+			t.Fatalf("%s not required, got %v", h, required)
+		}
+	}
+	if len(required) != len(want) {
+		t.Fatalf("got %d required signers, want %d", len(required), len(want))
+	}
+
+	if err := signing.Sign(ctx, tx, k1, k3); err != nil {
+		t.Fatal(err)
+	}
+	missing, err := signing.MissingSigners(tx, utxos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 2 || (missing[0] != h2 && missing[1] != h2) {
+		t.Fatalf("got missing %v", missing)
+	}
+
+	tx.Body.Collateral = []ledger.TransactionInput{{TxID: txID, Index: 7}}
+	if _, err := signing.MissingSigners(tx, utxos); !errors.Is(err, signing.ErrUnknownInput) {
+		t.Fatalf("got %v, want ErrUnknownInput", err)
+	}
+}
@@ -0,0 +1,68 @@
+package timeline
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+// Era summaries of the public networks, as returned by NetworkEras, and
+// their system start.
+//
+//go:embed snapshots/*.json
+var snapshots embed.FS
+
+type snapshot struct {
+	SystemStart int64                   `json:"system_start"`
+	Eras        []blockfrost.NetworkEra `json:"eras"`
+}
+
+func mustSnapshot(name string) *Timeline {
+	data, err := snapshots.ReadFile("snapshots/" + name + ".json")
+	if err != nil {
+		panic(err)
+	}
+	s := snapshot{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		panic(fmt.Sprintf("timeline: invalid %s snapshot: %v", name, err))
+	}
+	tl, err := New(time.Unix(s.SystemStart, 0), s.Eras)
+	if err != nil {
+		panic(fmt.Sprintf("timeline: invalid %s snapshot: %v", name, err))
+	}
+	return tl
+}
+
+var (
+	mainnet = mustSnapshot("mainnet")
+	preprod = mustSnapshot("preprod")
+	preview = mustSnapshot("preview")
+)
+
+// Mainnet returns the Timeline of mainnet from an embedded snapshot.
+func Mainnet() *Timeline { return mainnet }
+
+// Preprod returns the Timeline of the preprod testnet from an embedded
+// snapshot.
+func Preprod() *Timeline { return preprod }
+
+// Preview returns the Timeline of the preview testnet from an embedded
+// snapshot.
+func Preview() *Timeline { return preview }
+
+// ForServer returns the embedded Timeline of the network served by one of
+// the CardanoMainNet, CardanoPreProd or CardanoPreview servers.
+func ForServer(server string) (*Timeline, bool) {
+	switch server {
+	case blockfrost.CardanoMainNet:
+		return mainnet, true
+	case blockfrost.CardanoPreProd:
+		return preprod, true
+	case blockfrost.CardanoPreview:
+		return preview, true
+	}
+	return nil, false
+}
@@ -0,0 +1,235 @@
+// Package timeline converts between slots, epochs and wall-clock time.
+//
+// A Timeline is built from the era summaries returned by NetworkEras and the
+// system start returned by Genesis, either by calling Load once or by using
+// the snapshots embedded for mainnet, preprod and preview. Conversions are
+// then done offline and are correct across era boundaries, where the slot
+// and epoch lengths change.
+//
+// Beyond the end of the last known era, conversions extrapolate with the
+// parameters of that era. Results past the safe zone of a snapshot are only
+// correct as long as no hard fork changes those parameters.
+package timeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+var (
+	// ErrInvalidEras is returned when era summaries are empty, not
+	// contiguous or have non positive parameters.
+	ErrInvalidEras = errors.New("timeline: invalid eras")
+
+	// ErrOutOfRange is returned for negative slots and epochs, and for
+	// times before the system start.
+	ErrOutOfRange = errors.New("timeline: out of range")
+)
+
+type bound struct {
+	time  time.Duration
+	slot  int
+	epoch int
+}
+
+type era struct {
+	start       bound
+	epochLength int
+	slotLength  time.Duration
+}
+
+// Timeline converts between slots, epochs and time for a network. It is
+// immutable and safe for concurrent use.
+type Timeline struct {
+	systemStart time.Time
+	eras        []era
+}
+
+// New returns a Timeline from the system start of the network, as returned
+// in GenesisBlock.SystemStart, and its era summaries, as returned by
+// NetworkEras.
+func New(systemStart time.Time, eras []blockfrost.NetworkEra) (*Timeline, error) {
+	if len(eras) == 0 {
+		return nil, fmt.Errorf("%w: no eras", ErrInvalidEras)
+	}
+	eras = append([]blockfrost.NetworkEra(nil), eras...)
+	sort.SliceStable(eras, func(i, j int) bool {
+		return eras[i].Start.Slot < eras[j].Start.Slot
+	})
+	if eras[0].Start != (blockfrost.NetworkEraTime{}) {
+		return nil, fmt.Errorf("%w: first era does not start at genesis", ErrInvalidEras)
+	}
+
+	tl := &Timeline{systemStart: systemStart.UTC()}
+	for i, e := range eras {
+		if e.Parameters.EpochLength <= 0 || e.Parameters.SlotLength <= 0 {
+			return nil, fmt.Errorf("%w: era %d has invalid parameters", ErrInvalidEras, i)
+		}
+		if i > 0 && e.Start != eras[i-1].End {
+			return nil, fmt.Errorf("%w: era %d does not start at the end of era %d", ErrInvalidEras, i, i-1)
+		}
+		tl.eras = append(tl.eras, era{
+			start: bound{
+				time:  time.Duration(e.Start.Time) * time.Second,
+				slot:  e.Start.Slot,
+				epoch: e.Start.Epoch,
+			},
+			epochLength: e.Parameters.EpochLength,
+			slotLength:  time.Duration(e.Parameters.SlotLength) * time.Second,
+		})
+	}
+	return tl, nil
+}
+
+// Load returns the Timeline of the network the client is connected to,
+// using one Genesis and one NetworkEras call.
+func Load(ctx context.Context, client blockfrost.APIClient) (*Timeline, error) {
+	genesis, err := client.Genesis(ctx)
+	if err != nil {
+		return nil, err
+	}
+	eras, err := client.NetworkEras(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return New(time.Unix(int64(genesis.SystemStart), 0), eras)
+}
+
+// SystemStart returns the time of slot 0.
+func (tl *Timeline) SystemStart() time.Time {
+	return tl.systemStart
+}
+
+// eraBy returns the last era whose start is not after the given bound,
+// skipping eras of zero length.
+func (tl *Timeline) eraBy(before func(b bound) bool) era {
+	i := sort.Search(len(tl.eras), func(i int) bool {
+		return !before(tl.eras[i].start)
+	})
+	if i == 0 {
+		return tl.eras[0]
+	}
+	return tl.eras[i-1]
+}
+
+func (tl *Timeline) eraOfSlot(slot int) era {
+	return tl.eraBy(func(b bound) bool { return b.slot <= slot })
+}
+
+func (tl *Timeline) eraOfEpoch(epoch int) era {
+	return tl.eraBy(func(b bound) bool { return b.epoch <= epoch })
+}
+
+// SlotToTime returns the time at which a slot starts.
+func (tl *Timeline) SlotToTime(slot int) (time.Time, error) {
+	if slot < 0 {
+		return time.Time{}, fmt.Errorf("%w: slot %d", ErrOutOfRange, slot)
+	}
+	e := tl.eraOfSlot(slot)
+	d := e.start.time + time.Duration(slot-e.start.slot)*e.slotLength
+	return tl.systemStart.Add(d), nil
+}
+
+// TimeToSlot returns the slot in progress at t.
+func (tl *Timeline) TimeToSlot(t time.Time) (int, error) {
+	d := t.Sub(tl.systemStart)
+	if d < 0 {
+		return 0, fmt.Errorf("%w: %s is before system start", ErrOutOfRange, t)
+	}
+	e := tl.eraBy(func(b bound) bool { return b.time <= d })
+	return e.start.slot + int((d-e.start.time)/e.slotLength), nil
+}
+
+// SlotToEpoch returns the epoch a slot belongs to.
+func (tl *Timeline) SlotToEpoch(slot int) (int, error) {
+	epoch, _, err := tl.EpochSlot(slot)
+	return epoch, err
+}
+
+// EpochSlot returns the epoch a slot belongs to and the index of the slot
+// within that epoch, as in Block.EpochSlot.
+func (tl *Timeline) EpochSlot(slot int) (epoch, epochSlot int, err error) {
+	if slot < 0 {
+		return 0, 0, fmt.Errorf("%w: slot %d", ErrOutOfRange, slot)
+	}
+	e := tl.eraOfSlot(slot)
+	n := slot - e.start.slot
+	return e.start.epoch + n/e.epochLength, n % e.epochLength, nil
+}
+
+// EpochLength returns the number of slots in an epoch.
+func (tl *Timeline) EpochLength(epoch int) (int, error) {
+	if epoch < 0 {
+		return 0, fmt.Errorf("%w: epoch %d", ErrOutOfRange, epoch)
+	}
+	return tl.eraOfEpoch(epoch).epochLength, nil
+}
+
+// EpochFirstSlot returns the first slot of an epoch.
+func (tl *Timeline) EpochFirstSlot(epoch int) (int, error) {
+	if epoch < 0 {
+		return 0, fmt.Errorf("%w: epoch %d", ErrOutOfRange, epoch)
+	}
+	e := tl.eraOfEpoch(epoch)
+	return e.start.slot + (epoch-e.start.epoch)*e.epochLength, nil
+}
+
+// EpochLastSlot returns the last slot of an epoch.
+func (tl *Timeline) EpochLastSlot(epoch int) (int, error) {
+	next, err := tl.EpochFirstSlot(epoch + 1)
+	if err != nil {
+		return 0, err
+	}
+	return next - 1, nil
+}
+
+// EpochStart returns the time at which an epoch starts, as in
+// Epoch.StartTime.
+func (tl *Timeline) EpochStart(epoch int) (time.Time, error) {
+	slot, err := tl.EpochFirstSlot(epoch)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return tl.SlotToTime(slot)
+}
+
+// EpochEnd returns the time at which an epoch ends, which is the start of
+// the next epoch, as in Epoch.EndTime.
+func (tl *Timeline) EpochEnd(epoch int) (time.Time, error) {
+	if epoch < 0 {
+		return time.Time{}, fmt.Errorf("%w: epoch %d", ErrOutOfRange, epoch)
+	}
+	return tl.EpochStart(epoch + 1)
+}
+
+// TimeToEpoch returns the epoch in progress at t.
+func (tl *Timeline) TimeToEpoch(t time.Time) (int, error) {
+	slot, err := tl.TimeToSlot(t)
+	if err != nil {
+		return 0, err
+	}
+	return tl.SlotToEpoch(slot)
+}
+
+// SlotAfter returns the first slot starting at or after t plus d, which can
+// be used as the invalid_hereafter bound of a transaction valid for d.
+func (tl *Timeline) SlotAfter(t time.Time, d time.Duration) (int, error) {
+	t = t.Add(d)
+	slot, err := tl.TimeToSlot(t)
+	if err != nil {
+		return 0, err
+	}
+	start, err := tl.SlotToTime(slot)
+	if err != nil {
+		return 0, err
+	}
+	if start.Before(t) {
+		slot++
+	}
+	return slot, nil
+}
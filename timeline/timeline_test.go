@@ -0,0 +1,233 @@
+package timeline_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/timeline"
+)
+
+const testdata = "../testdata"
+
+func TestMainnetSlots(t *testing.T) {
+	tl := timeline.Mainnet()
+	tests := []struct {
+		slot      int
+		time      int64
+		epoch     int
+		epochSlot int
+	}{
+		{0, 1506203091, 0, 0},
+		{21599, 1506203091 + 21599*20, 0, 21599},
+		{21600, 1506203091 + 21600*20, 1, 0},
+		// Last Byron slot
+		{4492799, 1596059071, 207, 21599},
+		// First Shelley slot
+		{4492800, 1596059091, 208, 0},
+		{4492801, 1596059092, 208, 1},
+		{16588800, 1608155091, 236, 0},
+		// Beyond the end of the snapshot
+		{200000000, 1791566291, 660, 243200},
+	}
+	for _, tt := range tests {
+		got, err := tl.SlotToTime(tt.slot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Unix() != tt.time {
+			t.Fatalf("slot %d: expected %d got %d", tt.slot, tt.time, got.Unix())
+		}
+		slot, err := tl.TimeToSlot(time.Unix(tt.time, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if slot != tt.slot {
+			t.Fatalf("time %d: expected slot %d got %d", tt.time, tt.slot, slot)
+		}
+		epoch, epochSlot, err := tl.EpochSlot(tt.slot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if epoch != tt.epoch || epochSlot != tt.epochSlot {
+			t.Fatalf("slot %d: expected epoch %d/%d got %d/%d", tt.slot, tt.epoch, tt.epochSlot, epoch, epochSlot)
+		}
+	}
+
+	// Byron slots last 20 seconds
+	slot, _ := tl.TimeToSlot(time.Unix(1506203091+39, 0))
+	if slot != 1 {
+		t.Fatalf("expected slot 1 got %d", slot)
+	}
+}
+
+func TestMainnetEpochs(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(testdata, "epochintegration.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := blockfrost.Epoch{}
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatal(err)
+	}
+
+	tl := timeline.Mainnet()
+	start, err := tl.EpochStart(want.Epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	end, err := tl.EpochEnd(want.Epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start.Unix() != int64(want.StartTime) || end.Unix() != int64(want.EndTime) {
+		t.Fatalf("expected %d-%d got %d-%d", want.StartTime, want.EndTime, start.Unix(), end.Unix())
+	}
+
+	tests := []struct {
+		epoch, first, last, length int
+	}{
+		{0, 0, 21599, 21600},
+		{207, 4471200, 4492799, 21600},
+		{208, 4492800, 4924799, 432000},
+		{507, 133660800, 134092799, 432000},
+	}
+	for _, tt := range tests {
+		first, _ := tl.EpochFirstSlot(tt.epoch)
+		last, _ := tl.EpochLastSlot(tt.epoch)
+		length, _ := tl.EpochLength(tt.epoch)
+		if first != tt.first || last != tt.last || length != tt.length {
+			t.Fatalf("epoch %d: expected %d-%d (%d) got %d-%d (%d)", tt.epoch, tt.first, tt.last, tt.length, first, last, length)
+		}
+	}
+
+	epoch, err := tl.TimeToEpoch(time.Unix(int64(want.StartTime), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if epoch != want.Epoch {
+		t.Fatalf("expected %d got %d", want.Epoch, epoch)
+	}
+}
+
+func TestTestnets(t *testing.T) {
+	tests := []struct {
+		tl    *timeline.Timeline
+		epoch int
+		slot  int
+		time  int64
+	}{
+		{timeline.Preprod(), 0, 0, 1654041600},
+		{timeline.Preprod(), 4, 86400, 1655769600},
+		{timeline.Preprod(), 100, 41558400, 1697241600},
+		{timeline.Preview(), 0, 0, 1666656000},
+		{timeline.Preview(), 500, 43200000, 1709856000},
+	}
+	for _, tt := range tests {
+		slot, err := tt.tl.EpochFirstSlot(tt.epoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start, err := tt.tl.EpochStart(tt.epoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if slot != tt.slot || start.Unix() != tt.time {
+			t.Fatalf("epoch %d: expected %d at %d got %d at %d", tt.epoch, tt.slot, tt.time, slot, start.Unix())
+		}
+	}
+
+	for server, want := range map[string]*timeline.Timeline{
+		blockfrost.CardanoMainNet: timeline.Mainnet(),
+		blockfrost.CardanoPreProd: timeline.Preprod(),
+		blockfrost.CardanoPreview: timeline.Preview(),
+	} {
+		if got, ok := timeline.ForServer(server); !ok || got != want {
+			t.Fatalf("unexpected timeline for %s", server)
+		}
+	}
+	if _, ok := timeline.ForServer(blockfrost.CardanoTestNet); ok {
+		t.Fatal("expected no timeline for the legacy testnet")
+	}
+}
+
+func TestSlotAfter(t *testing.T) {
+	tl := timeline.Mainnet()
+	now := time.Unix(1596059091, 500_000_000)
+	slot, err := tl.SlotAfter(now, 2*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 4492800 + 7201; slot != want {
+		t.Fatalf("expected %d got %d", want, slot)
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	tl := timeline.Mainnet()
+	if _, err := tl.SlotToTime(-1); !errors.Is(err, timeline.ErrOutOfRange) {
+		t.Fatalf("expected %v got %v", timeline.ErrOutOfRange, err)
+	}
+	if _, err := tl.TimeToSlot(tl.SystemStart().Add(-time.Second)); !errors.Is(err, timeline.ErrOutOfRange) {
+		t.Fatalf("expected %v got %v", timeline.ErrOutOfRange, err)
+	}
+	if _, err := tl.EpochStart(-1); !errors.Is(err, timeline.ErrOutOfRange) {
+		t.Fatalf("expected %v got %v", timeline.ErrOutOfRange, err)
+	}
+}
+
+func TestNewInvalidEras(t *testing.T) {
+	params := blockfrost.NetworkEraParameters{EpochLength: 10, SlotLength: 1}
+	tests := [][]blockfrost.NetworkEra{
+		nil,
+		{{Start: blockfrost.NetworkEraTime{Slot: 10}, Parameters: params}},
+		{{Parameters: blockfrost.NetworkEraParameters{EpochLength: 10}}},
+		{
+			{End: blockfrost.NetworkEraTime{Time: 10, Slot: 10, Epoch: 1}, Parameters: params},
+			{Start: blockfrost.NetworkEraTime{Time: 20, Slot: 20, Epoch: 2}, Parameters: params},
+		},
+	}
+	for i, eras := range tests {
+		if _, err := timeline.New(time.Unix(0, 0), eras); !errors.Is(err, timeline.ErrInvalidEras) {
+			t.Fatalf("%d: expected %v got %v", i, timeline.ErrInvalidEras, err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	eras, err := os.ReadFile(filepath.Join(testdata, "resourcenetworkerasintegration.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/genesis":
+			w.Write([]byte(`{"system_start":1506203091}`))
+		case "/network/eras":
+			w.Write(eras)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer s.Close()
+
+	api := blockfrost.NewAPIClient(blockfrost.APIClientOptions{ProjectID: "test", Server: s.URL})
+	tl, err := timeline.Load(context.TODO(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slot := range []int{0, 4492799, 4492800, 133660800} {
+		got, _ := tl.SlotToTime(slot)
+		want, _ := timeline.Mainnet().SlotToTime(slot)
+		if !got.Equal(want) {
+			t.Fatalf("slot %d: expected %s got %s", slot, want, got)
+		}
+	}
+}
@@ -0,0 +1,616 @@
+// Package tracker follows submitted transactions until they are confirmed.
+//
+// A TxTracker follows any number of transactions from a single poll loop,
+// and reports their state transitions on its Events channel:
+//
+//	t := tracker.New(client, tracker.Options{Confirmations: 15})
+//	go t.Run(ctx)
+//	hash, err := t.Submit(ctx, signed)
+//	if err != nil {
+//		return err
+//	}
+//	for ev := range t.Events() {
+//		if ev.Hash == hash && ev.State.Final() {
+//			fmt.Println(hash, ev.State)
+//		}
+//	}
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/internal/apierr"
+	"github.com/blockfrost/blockfrost-go/ledger"
+)
+
+// State is the state of a tracked transaction.
+type State int
+
+const (
+	// StateSubmitted is the state of transactions submitted, or resubmitted,
+	// by the tracker.
+	StateSubmitted State = iota
+	// StateInMempool is the state of transactions seen in the mempool.
+	StateInMempool
+	// StateInBlock is the state of transactions included in a block that
+	// does not have enough confirmations yet.
+	StateInBlock
+	// StateConfirmed is the final state of transactions included in a block
+	// with enough confirmations.
+	StateConfirmed
+	// StateRolledBack is the state of transactions whose block was rolled
+	// back. They are tracked again as if they had just been submitted.
+	StateRolledBack
+	// StateExpired is the final state of transactions that were not
+	// included in a block before their invalid_hereafter slot.
+	StateExpired
+)
+
+var stateNames = [...]string{
+	StateSubmitted:  "submitted",
+	StateInMempool:  "in mempool",
+	StateInBlock:    "in block",
+	StateConfirmed:  "confirmed",
+	StateRolledBack: "rolled back",
+	StateExpired:    "expired",
+}
+
+func (s State) String() string {
+	if s < 0 || int(s) >= len(stateNames) {
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+	return stateNames[s]
+}
+
+// Final reports whether s is a final state, after which the transaction is
+// no longer tracked.
+func (s State) Final() bool {
+	return s == StateConfirmed || s == StateExpired
+}
+
+// Event is a state transition of a tracked transaction.
+type Event struct {
+	Hash  string
+	State State
+
+	// Block, Height and Slot locate the block including the transaction,
+	// from StateInBlock on. For StateRolledBack they locate the block that
+	// was rolled back.
+	Block  string
+	Height int
+	Slot   int
+
+	// Confirmations is the number of blocks on top of Block.
+	Confirmations int
+}
+
+// Options configures a TxTracker.
+type Options struct {
+	// Interval between polls. Default: 20s, the block time of Cardano.
+	Interval time.Duration
+
+	// Confirmations is the depth at which transactions are confirmed.
+	// Default: 10.
+	Confirmations int
+
+	// Concurrency is the maximum number of requests made concurrently when
+	// transactions are looked up one by one. Default: 10.
+	Concurrency int
+
+	// EventBuffer is the capacity of the Events channel. The poll loop
+	// blocks while the channel is full. Default: 256.
+	EventBuffer int
+
+	// OnError is called with the errors of polls and resubmissions, which
+	// are otherwise ignored and retried at the next poll.
+	OnError func(error)
+}
+
+const (
+	// maxScannedBlocks is the number of new blocks scanned for tracked
+	// transactions in a poll, past which they are looked up one by one.
+	maxScannedBlocks = 1000
+	// mempoolScanThreshold is the number of pending transactions past which
+	// the whole mempool is fetched rather than looking them up one by one.
+	mempoolScanThreshold = 50
+)
+
+// entry is a tracked transaction.
+type entry struct {
+	hash string
+	cbor []byte
+	ttl  *uint64
+
+	state      State
+	announce   bool
+	seen       bool
+	resubmit   bool
+	lookup     bool
+	block      string
+	height     int
+	slot       int
+	untrackNow bool
+}
+
+// TxTracker follows transactions, from their submission to their
+// confirmation or expiry, with a single poll loop shared by all of them.
+type TxTracker struct {
+	client blockfrost.APIClient
+	opts   Options
+	events chan Event
+
+	mu      sync.Mutex
+	entries map[string]*entry
+
+	// tip is the latest block seen by the poll loop.
+	tip *blockfrost.Block
+}
+
+// New returns a TxTracker polling client. Call Run to start it.
+func New(client blockfrost.APIClient, opts Options) *TxTracker {
+	if opts.Interval <= 0 {
+		opts.Interval = 20 * time.Second
+	}
+	if opts.Confirmations <= 0 {
+		opts.Confirmations = 10
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 10
+	}
+	if opts.EventBuffer <= 0 {
+		opts.EventBuffer = 256
+	}
+	return &TxTracker{
+		client:  client,
+		opts:    opts,
+		events:  make(chan Event, opts.EventBuffer),
+		entries: map[string]*entry{},
+	}
+}
+
+// Events returns the channel of state transitions. It is closed when Run
+// returns.
+func (t *TxTracker) Events() <-chan Event {
+	return t.events
+}
+
+// Track tracks the transaction hash. Transactions tracked by hash cannot be
+// resubmitted and do not expire. Tracking a hash that is already tracked
+// has no effect.
+func (t *TxTracker) Track(hash string) {
+	t.add(&entry{hash: hash, lookup: true})
+}
+
+// TrackCBOR tracks the CBOR encoded transaction, resubmitting it if it is
+// missing from both the mempool and the chain before its invalid_hereafter
+// slot, after which it expires. It returns the hash of the transaction.
+func (t *TxTracker) TrackCBOR(cbor []byte) (string, error) {
+	e, err := newEntry(cbor)
+	if err != nil {
+		return "", err
+	}
+	// The transaction may have been evicted from the mempool before the
+	// first poll.
+	e.resubmit = true
+	t.add(e)
+	return e.hash, nil
+}
+
+// Submit submits the CBOR encoded transaction with
+// TransactionSubmitIdempotent and tracks it as TrackCBOR does. Transactions
+// rejected by the node are not tracked, while transactions whose
+// submission failed otherwise are, since they may have reached the node.
+func (t *TxTracker) Submit(ctx context.Context, cbor []byte) (string, error) {
+	e, err := newEntry(cbor)
+	if err != nil {
+		return "", err
+	}
+	_, err = t.client.TransactionSubmitIdempotent(ctx, cbor)
+	var apiErr *blockfrost.APIError
+	if errors.As(err, &apiErr) {
+		if _, ok := apiErr.Response.(blockfrost.BadRequest); ok {
+			return e.hash, err
+		}
+	}
+	// The transaction may not have reached the node, in which case it is
+	// announced once resubmitted.
+	e.announce, e.resubmit = err == nil, err != nil
+	t.add(e)
+	return e.hash, err
+}
+
+func newEntry(cbor []byte) (*entry, error) {
+	tx, err := ledger.DecodeTransaction(cbor)
+	if err != nil {
+		return nil, fmt.Errorf("tracker: %w", err)
+	}
+	return &entry{
+		hash:   tx.Hash().String(),
+		cbor:   append([]byte{}, cbor...),
+		ttl:    tx.Body.TTL,
+		lookup: true,
+	}, nil
+}
+
+func (t *TxTracker) add(e *entry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[e.hash]; !ok {
+		t.entries[e.hash] = e
+	}
+}
+
+// Untrack stops tracking the transaction hash.
+func (t *TxTracker) Untrack(hash string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, hash)
+}
+
+// Len returns the number of tracked transactions.
+func (t *TxTracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.entries)
+}
+
+// Run polls the tracked transactions every Interval until ctx is done, and
+// closes the Events channel when it returns. It returns the error of ctx.
+func (t *TxTracker) Run(ctx context.Context) error {
+	defer close(t.events)
+	ticker := time.NewTicker(t.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if err := t.poll(ctx); err != nil && ctx.Err() == nil {
+			t.onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *TxTracker) onError(err error) {
+	if t.opts.OnError != nil {
+		t.opts.OnError(err)
+	}
+}
+
+func (t *TxTracker) snapshot() []*entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]*entry, 0, len(t.entries))
+	for _, e := range t.entries {
+		out = append(out, e)
+	}
+	return out
+}
+
+// poll updates the state of every tracked transaction.
+func (t *TxTracker) poll(ctx context.Context) error {
+	tip, err := t.client.BlockLatest(ctx)
+	if err != nil {
+		return fmt.Errorf("tracker: latest block: %w", err)
+	}
+	entries := t.snapshot()
+	for _, e := range entries {
+		if e.announce {
+			e.announce = false
+			if err := t.emit(ctx, e, StateSubmitted, 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Transactions whose block was rolled back are pending again.
+	if err := t.checkBlocks(ctx, entries, tip); err != nil {
+		return err
+	}
+
+	pending := map[string]*entry{}
+	for _, e := range entries {
+		if e.state != StateInBlock && !e.untrackNow {
+			pending[e.hash] = e
+		}
+	}
+	if len(pending) > 0 {
+		included, err := t.findIncluded(ctx, pending, tip)
+		if err != nil {
+			return err
+		}
+		var inMempool map[string]bool
+		if len(included) < len(pending) {
+			if inMempool, err = t.mempool(ctx, pending, included); err != nil {
+				return err
+			}
+		}
+		for _, e := range pending {
+			if err := t.update(ctx, e, tip, included[e.hash], inMempool[e.hash]); err != nil {
+				return err
+			}
+		}
+	}
+	t.tip = &tip
+
+	t.mu.Lock()
+	for _, e := range entries {
+		if e.untrackNow && t.entries[e.hash] == e {
+			delete(t.entries, e.hash)
+		}
+	}
+	t.mu.Unlock()
+	return nil
+}
+
+// checkBlocks confirms the transactions included in blocks deep enough,
+// and marks those whose block was rolled back.
+func (t *TxTracker) checkBlocks(ctx context.Context, entries []*entry, tip blockfrost.Block) error {
+	blocks := map[string]*blockfrost.Block{}
+	for _, e := range entries {
+		if e.state != StateInBlock {
+			continue
+		}
+		b, ok := blocks[e.block]
+		if !ok {
+			block, err := t.client.Block(ctx, e.block)
+			switch {
+			case apierr.IsNotFound(err):
+			case err != nil:
+				return fmt.Errorf("tracker: block %s: %w", e.block, err)
+			default:
+				b = &block
+			}
+			blocks[e.block] = b
+		}
+		if err := t.updateIncluded(ctx, e, b, tip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateIncluded updates e, included in block b, or in a block that was
+// rolled back if b is nil.
+func (t *TxTracker) updateIncluded(ctx context.Context, e *entry, b *blockfrost.Block, tip blockfrost.Block) error {
+	if b == nil {
+		if err := t.emit(ctx, e, StateRolledBack, 0); err != nil {
+			return err
+		}
+		// Rolled back transactions usually return to the mempool, and are
+		// resubmitted if they do not.
+		e.state, e.seen, e.lookup = StateSubmitted, true, true
+		e.block, e.height, e.slot = "", 0, 0
+		return nil
+	}
+	confirmations := b.Confirmations
+	if tip.Height-b.Height > confirmations {
+		confirmations = tip.Height - b.Height
+	}
+	if e.state != StateInBlock || e.block != b.Hash {
+		e.block, e.height, e.slot = b.Hash, b.Height, b.Slot
+		if err := t.emit(ctx, e, StateInBlock, confirmations); err != nil {
+			return err
+		}
+	}
+	if confirmations >= t.opts.Confirmations {
+		e.untrackNow = true
+		return t.emit(ctx, e, StateConfirmed, confirmations)
+	}
+	return nil
+}
+
+// findIncluded returns the blocks including pending transactions. The
+// blocks added since the previous poll are scanned, and the transactions
+// tracked since then are looked up one by one, as are all of them on the
+// first poll or after a rollback of the previous tip.
+func (t *TxTracker) findIncluded(ctx context.Context, pending map[string]*entry, tip blockfrost.Block) (map[string]*blockfrost.Block, error) {
+	included := map[string]*blockfrost.Block{}
+	scanned := false
+	if t.tip != nil {
+		if t.tip.Hash == tip.Hash {
+			scanned = true
+		} else {
+			var err error
+			if scanned, err = t.scan(ctx, pending, tip, included); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var lookups []*entry
+	for _, e := range pending {
+		if (!scanned || e.lookup) && included[e.hash] == nil {
+			lookups = append(lookups, e)
+		}
+	}
+	var mu sync.Mutex
+	err := t.each(ctx, lookups, func(e *entry) error {
+		tx, err := t.client.Transaction(ctx, e.hash)
+		if apierr.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("tracker: transaction %s: %w", e.hash, err)
+		}
+		mu.Lock()
+		included[e.hash] = &blockfrost.Block{Hash: tx.Block, Height: tx.BlockHeight, Slot: tx.Slot}
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range lookups {
+		e.lookup = false
+	}
+	return included, nil
+}
+
+// scan scans the blocks added since the previous tip for pending
+// transactions. It reports false if they could not be scanned, because
+// the previous tip was rolled back or is too far behind.
+func (t *TxTracker) scan(ctx context.Context, pending map[string]*entry, tip blockfrost.Block, included map[string]*blockfrost.Block) (bool, error) {
+	if tip.Height-t.tip.Height > maxScannedBlocks {
+		return false, nil
+	}
+	from := t.tip.Hash
+	for {
+		blocks, err := t.client.BlocksNext(ctx, from)
+		if apierr.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("tracker: blocks after %s: %w", from, err)
+		}
+		for i := range blocks {
+			b := &blocks[i]
+			if b.Height > tip.Height {
+				return true, nil
+			}
+			if b.TxCount == 0 {
+				continue
+			}
+			for res := range t.client.BlockTransactionsAll(ctx, b.Hash) {
+				if res.Err != nil {
+					return false, fmt.Errorf("tracker: transactions of block %s: %w", b.Hash, res.Err)
+				}
+				for _, hash := range res.Res {
+					if pending[string(hash)] != nil {
+						included[string(hash)] = b
+					}
+				}
+			}
+		}
+		if len(blocks) == 0 || blocks[len(blocks)-1].Height >= tip.Height {
+			return true, nil
+		}
+		from = blocks[len(blocks)-1].Hash
+	}
+}
+
+// mempool returns the pending transactions that are not included in a
+// block and are in the mempool.
+func (t *TxTracker) mempool(ctx context.Context, pending map[string]*entry, included map[string]*blockfrost.Block) (map[string]bool, error) {
+	inMempool := map[string]bool{}
+	if len(pending)-len(included) > mempoolScanThreshold {
+		for res := range t.client.MempoolAll(ctx) {
+			if res.Err != nil {
+				return nil, fmt.Errorf("tracker: mempool: %w", res.Err)
+			}
+			for _, tx := range res.Res {
+				if pending[tx.TxHash] != nil {
+					inMempool[tx.TxHash] = true
+				}
+			}
+		}
+		return inMempool, nil
+	}
+
+	var lookups []*entry
+	for _, e := range pending {
+		if included[e.hash] == nil {
+			lookups = append(lookups, e)
+		}
+	}
+	var mu sync.Mutex
+	err := t.each(ctx, lookups, func(e *entry) error {
+		_, err := t.client.MempoolTx(ctx, e.hash)
+		if apierr.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("tracker: mempool transaction %s: %w", e.hash, err)
+		}
+		mu.Lock()
+		inMempool[e.hash] = true
+		mu.Unlock()
+		return nil
+	})
+	return inMempool, err
+}
+
+// update updates the pending transaction e, included in block b if it is
+// not nil.
+func (t *TxTracker) update(ctx context.Context, e *entry, tip blockfrost.Block, b *blockfrost.Block, inMempool bool) error {
+	switch {
+	case b != nil:
+		return t.updateIncluded(ctx, e, b, tip)
+	case inMempool:
+		e.seen = true
+		if e.state != StateInMempool {
+			return t.emit(ctx, e, StateInMempool, 0)
+		}
+	case e.ttl != nil && uint64(tip.Slot) >= *e.ttl:
+		e.untrackNow = true
+		return t.emit(ctx, e, StateExpired, 0)
+	case (e.seen || e.resubmit) && e.cbor != nil:
+		// The transaction disappeared from the mempool before its expiry, or
+		// never reached it.
+		e.seen, e.resubmit = false, false
+		if _, err := t.client.TransactionSubmitIdempotent(ctx, e.cbor); err != nil {
+			e.resubmit = true
+			t.onError(fmt.Errorf("tracker: resubmit %s: %w", e.hash, err))
+			return nil
+		}
+		return t.emit(ctx, e, StateSubmitted, 0)
+	}
+	return nil
+}
+
+func (t *TxTracker) emit(ctx context.Context, e *entry, s State, confirmations int) error {
+	if s != StateRolledBack {
+		e.state = s
+	}
+	ev := Event{
+		Hash:          e.hash,
+		State:         s,
+		Block:         e.block,
+		Height:        e.height,
+		Slot:          e.slot,
+		Confirmations: confirmations,
+	}
+	select {
+	case t.events <- ev:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// each calls fn for entries, with at most Concurrency calls at a time, and
+// returns the first error.
+func (t *TxTracker) each(ctx context.Context, entries []*entry, fn func(*entry) error) error {
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, t.opts.Concurrency)
+	for _, e := range entries {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func(e *entry) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(e); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}(e)
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,372 @@
+package tracker_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/tracker"
+)
+
+const testdata = "../testdata"
+
+// ttl is the invalid_hereafter slot of the test transaction.
+const ttl = 150000000
+
+func loadTransaction(t *testing.T) ([]byte, string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(testdata, "json", "transactions", "tx_cbor_conway.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tc struct {
+		Cbor string `json:"cbor"`
+	}
+	if err := json.Unmarshal(data, &tc); err != nil {
+		t.Fatal(err)
+	}
+	cbor, err := hex.DecodeString(tc.Cbor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := blockfrost.TransactionHash(cbor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cbor, hash
+}
+
+var errNotFound = &blockfrost.APIError{Response: blockfrost.NotFound{StatusCode: 404, Error: "Not Found"}}
+
+// chain is a fake client serving a chain of blocks and a mempool.
+type chain struct {
+	blockfrost.APIClient
+
+	mu        sync.Mutex
+	blocks    []blockfrost.Block
+	txs       map[string][]blockfrost.Transaction
+	mempool   map[string]bool
+	slot      int
+	next      int
+	submits   int
+	reject    bool
+	fail      int
+	mempoolTx int
+}
+
+func newChain(slot int) *chain {
+	c := &chain{txs: map[string][]blockfrost.Transaction{}, mempool: map[string]bool{}, slot: slot}
+	c.addBlock()
+	return c
+}
+
+// addBlock adds a block including hashes, which leave the mempool.
+func (c *chain) addBlock(hashes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next++
+	b := blockfrost.Block{
+		Hash:    fmt.Sprintf("block%d", c.next),
+		Height:  len(c.blocks) + 1,
+		Slot:    c.slot + 20*c.next,
+		TxCount: len(hashes),
+	}
+	c.blocks = append(c.blocks, b)
+	for _, h := range hashes {
+		c.txs[b.Hash] = append(c.txs[b.Hash], blockfrost.Transaction(h))
+		delete(c.mempool, h)
+	}
+}
+
+func (c *chain) rollback(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blocks = c.blocks[:len(c.blocks)-n]
+}
+
+func (c *chain) find(hash string) (blockfrost.Block, int, bool) {
+	for i, b := range c.blocks {
+		if b.Hash == hash {
+			b.Confirmations = len(c.blocks) - 1 - i
+			return b, i, true
+		}
+	}
+	return blockfrost.Block{}, 0, false
+}
+
+func (c *chain) BlockLatest(ctx context.Context) (blockfrost.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.blocks[len(c.blocks)-1], nil
+}
+
+func (c *chain) Block(ctx context.Context, hash string) (blockfrost.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if b, _, ok := c.find(hash); ok {
+		return b, nil
+	}
+	return blockfrost.Block{}, errNotFound
+}
+
+func (c *chain) BlocksNext(ctx context.Context, hash string) ([]blockfrost.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, i, ok := c.find(hash)
+	if !ok {
+		return nil, errNotFound
+	}
+	return append([]blockfrost.Block{}, c.blocks[i+1:]...), nil
+}
+
+func (c *chain) BlockTransactionsAll(ctx context.Context, hash string) <-chan blockfrost.BlockTransactionResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan blockfrost.BlockTransactionResult, 1)
+	ch <- blockfrost.BlockTransactionResult{Res: c.txs[hash]}
+	close(ch)
+	return ch
+}
+
+func (c *chain) Transaction(ctx context.Context, hash string) (blockfrost.TransactionContent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, b := range c.blocks {
+		for _, h := range c.txs[b.Hash] {
+			if string(h) == hash {
+				return blockfrost.TransactionContent{Hash: hash, Block: b.Hash, BlockHeight: b.Height, Slot: b.Slot}, nil
+			}
+		}
+	}
+	return blockfrost.TransactionContent{}, errNotFound
+}
+
+func (c *chain) MempoolTx(ctx context.Context, hash string) (blockfrost.MempoolTransactionContent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mempoolTx++
+	if !c.mempool[hash] {
+		return blockfrost.MempoolTransactionContent{}, errNotFound
+	}
+	return blockfrost.MempoolTransactionContent{}, nil
+}
+
+func (c *chain) MempoolAll(ctx context.Context) <-chan blockfrost.MempoolResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res := blockfrost.MempoolResult{}
+	for h := range c.mempool {
+		res.Res = append(res.Res, blockfrost.Mempool{TxHash: h})
+	}
+	ch := make(chan blockfrost.MempoolResult, 1)
+	ch <- res
+	close(ch)
+	return ch
+}
+
+func (c *chain) TransactionSubmitIdempotent(ctx context.Context, cbor []byte) (blockfrost.SubmitResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hash, err := blockfrost.TransactionHash(cbor)
+	if err != nil {
+		return blockfrost.SubmitResult{}, err
+	}
+	if c.reject {
+		return blockfrost.SubmitResult{Hash: hash}, &blockfrost.APIError{Response: blockfrost.BadRequest{StatusCode: 400}}
+	}
+	if c.fail > 0 {
+		c.fail--
+		return blockfrost.SubmitResult{}, errors.New("connection reset")
+	}
+	c.submits++
+	c.mempool[hash] = true
+	return blockfrost.SubmitResult{Hash: hash, Accepted: true}, nil
+}
+
+func start(t *testing.T, tr *tracker.TxTracker) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- tr.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", err)
+		}
+	})
+}
+
+func expect(t *testing.T, tr *tracker.TxTracker, hash string, states ...tracker.State) tracker.Event {
+	t.Helper()
+	var ev tracker.Event
+	for _, want := range states {
+		select {
+		case ev = <-tr.Events():
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+		if ev.Hash != hash || ev.State != want {
+			t.Fatalf("got %s %s, want %s %s", ev.Hash, ev.State, hash, want)
+		}
+	}
+	return ev
+}
+
+func TestTxTracker(t *testing.T) {
+	cbor, hash := loadTransaction(t)
+	c := newChain(0)
+	tr := tracker.New(c, tracker.Options{Interval: time.Millisecond, Confirmations: 2})
+	got, err := tr.Submit(context.Background(), cbor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != hash || tr.Len() != 1 {
+		t.Fatalf("got hash %s and %d tracked transactions", got, tr.Len())
+	}
+	start(t, tr)
+	expect(t, tr, hash, tracker.StateSubmitted, tracker.StateInMempool)
+
+	c.addBlock(hash)
+	ev := expect(t, tr, hash, tracker.StateInBlock)
+	if ev.Block != "block2" || ev.Height != 2 || ev.Confirmations != 0 {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	c.addBlock()
+	c.addBlock()
+	ev = expect(t, tr, hash, tracker.StateConfirmed)
+	if ev.Block != "block2" || ev.Confirmations != 2 || !ev.State.Final() {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+}
+
+func TestTxTrackerRollback(t *testing.T) {
+	cbor, hash := loadTransaction(t)
+	c := newChain(0)
+	c.addBlock(hash)
+	tr := tracker.New(c, tracker.Options{Interval: time.Millisecond})
+	if _, err := tr.TrackCBOR(cbor); err != nil {
+		t.Fatal(err)
+	}
+	start(t, tr)
+	expect(t, tr, hash, tracker.StateInBlock)
+
+	// The block is replaced, and the transaction, which did not return to
+	// the mempool, is resubmitted.
+	c.rollback(1)
+	c.addBlock()
+	ev := expect(t, tr, hash, tracker.StateRolledBack)
+	if ev.Block != "block2" {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+	expect(t, tr, hash, tracker.StateSubmitted, tracker.StateInMempool)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.submits != 1 {
+		t.Fatalf("got %d submissions, want 1", c.submits)
+	}
+}
+
+func TestTxTrackerExpired(t *testing.T) {
+	cbor, hash := loadTransaction(t)
+	c := newChain(ttl)
+	tr := tracker.New(c, tracker.Options{Interval: time.Millisecond})
+	if _, err := tr.TrackCBOR(cbor); err != nil {
+		t.Fatal(err)
+	}
+	start(t, tr)
+	if ev := expect(t, tr, hash, tracker.StateExpired); !ev.State.Final() {
+		t.Fatal("expired is not final")
+	}
+}
+
+func TestTxTrackerMany(t *testing.T) {
+	c := newChain(0)
+	tr := tracker.New(c, tracker.Options{Interval: time.Millisecond})
+	want := map[string]bool{}
+	for i := 0; i < 500; i++ {
+		hash := fmt.Sprintf("%064x", i)
+		want[hash] = true
+		c.mempool[hash] = true
+		tr.Track(hash)
+	}
+	start(t, tr)
+	for range want {
+		select {
+		case ev := <-tr.Events():
+			if !want[ev.Hash] || ev.State != tracker.StateInMempool {
+				t.Fatalf("unexpected event %+v", ev)
+			}
+			delete(want, ev.Hash)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out")
+		}
+	}
+
+	// The mempool is fetched at once rather than transaction by transaction.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mempoolTx != 0 {
+		t.Fatalf("got %d mempool transaction requests", c.mempoolTx)
+	}
+}
+
+func TestTxTrackerSubmitFailed(t *testing.T) {
+	cbor, hash := loadTransaction(t)
+	c := newChain(0)
+	c.fail = 2
+	tr := tracker.New(c, tracker.Options{Interval: time.Millisecond})
+	if _, err := tr.Submit(context.Background(), cbor); err == nil {
+		t.Fatal("expected error")
+	}
+	if tr.Len() != 1 {
+		t.Fatalf("got %d tracked transactions", tr.Len())
+	}
+
+	// The transaction never reached the mempool, so it is resubmitted, again
+	// after a failed resubmission.
+	start(t, tr)
+	expect(t, tr, hash, tracker.StateSubmitted, tracker.StateInMempool)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.submits != 1 || c.fail != 0 {
+		t.Fatalf("got %d submissions, %d failures left", c.submits, c.fail)
+	}
+}
+
+func TestTxTrackerSubmitRejected(t *testing.T) {
+	cbor, hash := loadTransaction(t)
+	c := newChain(0)
+	c.reject = true
+	tr := tracker.New(c, tracker.Options{})
+	got, err := tr.Submit(context.Background(), cbor)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got != hash || tr.Len() != 0 {
+		t.Fatalf("got hash %s and %d tracked transactions", got, tr.Len())
+	}
+
+	if _, err := tr.TrackCBOR([]byte{0x80}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestStateString(t *testing.T) {
+	if got := tracker.StateInMempool.String(); got != "in mempool" {
+		t.Fatalf("got %q", got)
+	}
+	if got := tracker.State(42).String(); got != "State(42)" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,132 @@
+package txbuilder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/blockfrost/blockfrost-go/internal/cbor"
+)
+
+// ErrInvalidMetadata is returned for values that cannot be encoded as
+// transaction metadata.
+var ErrInvalidMetadata = errors.New("txbuilder: invalid metadata")
+
+// maxMetadataString is the maximum size in bytes of metadata strings and
+// byte strings.
+const maxMetadataString = 64
+
+var (
+	minMetadataInt = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 64))
+	maxMetadataInt = new(big.Int).SetUint64(math.MaxUint64)
+)
+
+// EncodeMetadatum returns the CBOR encoding of v as a transaction metadatum.
+//
+// Strings and byte slices are encoded as text and byte strings of at most 64
+// bytes, integers, *big.Int and json.Number as integers, and slices and maps
+// as lists and maps of metadata. Floats are accepted when they are integral,
+// so that values decoded from JSON can be attached as they are. Map entries
+// are sorted by the encoding of their key.
+func EncodeMetadatum(v interface{}) ([]byte, error) {
+	return appendMetadatum(nil, reflect.ValueOf(v), 0)
+}
+
+func appendMetadatum(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	if depth > 64 {
+		return nil, fmt.Errorf("%w: nested too deeply", ErrInvalidMetadata)
+	}
+	if !v.IsValid() {
+		return nil, fmt.Errorf("%w: nil value", ErrInvalidMetadata)
+	}
+	switch x := v.Interface().(type) {
+	case *big.Int:
+		if x == nil {
+			return nil, fmt.Errorf("%w: nil value", ErrInvalidMetadata)
+		}
+		return appendMetadataInt(b, x)
+	case json.Number:
+		n, ok := new(big.Int).SetString(string(x), 10)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not an integer", ErrInvalidMetadata, x)
+		}
+		return appendMetadataInt(b, n)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil, fmt.Errorf("%w: nil value", ErrInvalidMetadata)
+		}
+		return appendMetadatum(b, v.Elem(), depth)
+	case reflect.String:
+		if len(v.String()) > maxMetadataString {
+			return nil, fmt.Errorf("%w: string longer than %d bytes", ErrInvalidMetadata, maxMetadataString)
+		}
+		return cbor.AppendText(b, v.String()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cbor.AppendInt(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cbor.AppendUint(b, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f != math.Trunc(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("%w: %v is not an integer", ErrInvalidMetadata, f)
+		}
+		n, _ := big.NewFloat(f).Int(nil)
+		return appendMetadataInt(b, n)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(data), v)
+			if len(data) > maxMetadataString {
+				return nil, fmt.Errorf("%w: byte string longer than %d bytes", ErrInvalidMetadata, maxMetadataString)
+			}
+			return cbor.AppendBytes(b, data), nil
+		}
+		b = cbor.AppendArrayHeader(b, v.Len())
+		var err error
+		for i := 0; i < v.Len(); i++ {
+			if b, err = appendMetadatum(b, v.Index(i), depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case reflect.Map:
+		type entry struct{ key, value []byte }
+		entries := make([]entry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, err := appendMetadatum(nil, iter.Key(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			val, err := appendMetadatum(nil, iter.Value(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry{k, val})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].key, entries[j].key) < 0
+		})
+		b = cbor.AppendMapHeader(b, len(entries))
+		for _, e := range entries {
+			b = append(append(b, e.key...), e.value...)
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported type %s", ErrInvalidMetadata, v.Type())
+}
+
+func appendMetadataInt(b []byte, n *big.Int) ([]byte, error) {
+	if n.Cmp(minMetadataInt) < 0 || n.Cmp(maxMetadataInt) > 0 {
+		return nil, fmt.Errorf("%w: integer %s out of range", ErrInvalidMetadata, n)
+	}
+	return cbor.AppendBigInt(b, n), nil
+}
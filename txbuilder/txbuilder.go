@@ -0,0 +1,586 @@
+// Package txbuilder builds unsigned transactions from the UTXOs returned by
+// AddressUTXOs and AddressUTXOsAll.
+//
+// A Builder selects inputs covering the outputs and the fee, computes the fee
+// until it is stable, and returns the remaining value to a change address,
+// split over several outputs when it holds too many assets. Everything is
+// computed offline from the UTXOs and protocol parameters passed in.
+//
+//	b, err := txbuilder.New(params)
+//	if err != nil {
+//		return err
+//	}
+//	res, err := b.AddUTXOs(utxos...).
+//		PayTo(receiver, value.New(5000000)).
+//		SetChangeAddress(sender).
+//		ValidFor(tip, 7200).
+//		Build()
+package txbuilder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/coinselection"
+	"github.com/blockfrost/blockfrost-go/fees"
+	"github.com/blockfrost/blockfrost-go/ledger"
+	"github.com/blockfrost/blockfrost-go/plutusdata"
+	"github.com/blockfrost/blockfrost-go/value"
+)
+
+var (
+	// ErrInsufficientFunds is returned when the UTXOs do not cover the
+	// outputs and the fee. It is the error returned by coin selection.
+	ErrInsufficientFunds = coinselection.ErrInsufficientFunds
+
+	// ErrTooManyInputs is returned when covering the outputs and the fee
+	// needs more inputs than fit in a transaction. It is the error returned
+	// by coin selection.
+	ErrTooManyInputs = coinselection.ErrTooManyInputs
+
+	// ErrChangeTooSmall is returned when the change holds less than the
+	// minimum amount of lovelace of an output and no UTXO is left to add to
+	// it, unless BurnDust was called.
+	ErrChangeTooSmall = errors.New("txbuilder: change below minimum output value")
+
+	// ErrInvalidOutput is returned for outputs that cannot be paid, such as
+	// outputs holding less than the minimum amount of lovelace.
+	ErrInvalidOutput = errors.New("txbuilder: invalid output")
+
+	// ErrNoChangeAddress is returned by Build when no change address is set.
+	ErrNoChangeAddress = errors.New("txbuilder: no change address")
+
+	// ErrTxTooLarge is returned when the signed transaction would exceed the
+	// maximum transaction size.
+	ErrTxTooLarge = errors.New("txbuilder: transaction too large")
+)
+
+const (
+	// defaultMaxValSize is the max_val_size protocol parameter, used when
+	// it is not set in the parameters.
+	defaultMaxValSize = 5000
+
+	maxIterations = 32
+)
+
+// Output is a payment made by a transaction.
+type Output struct {
+	// Bech32 or Base58 address
+	Address string
+
+	// Value paid to the address. Outputs without lovelace are paid the
+	// minimum amount of lovelace.
+	Value value.Value
+
+	// Hash of the datum attached to the output, if any
+	DatumHash *ledger.Hash32
+
+	// Inline datum attached to the output, if any
+	Datum plutusdata.PlutusData
+
+	// Reference script attached to the output, if any
+	ScriptRef *ledger.Script
+}
+
+type utxo struct {
+	src     blockfrost.AddressUTXO
+	input   ledger.TransactionInput
+	keyHash ledger.Hash28
+}
+
+// Builder builds a transaction. Its methods record errors, which are
+// returned by Build, so that calls can be chained.
+type Builder struct {
+	params     *fees.Params
+	maxValSize int
+
+	utxos      []utxo
+	outputs    []Output
+	change     string
+	metadata   map[uint64][]byte
+	validFrom  *uint64
+	validUntil *uint64
+	signers    []ledger.Hash28
+	burnDust   bool
+
+	err error
+}
+
+// Result is a built transaction.
+type Result struct {
+	// Unsigned transaction
+	Transaction *ledger.Transaction
+
+	// UTXOs spent by the transaction, in the order of its inputs
+	Inputs []blockfrost.AddressUTXO
+
+	// Fee paid by the transaction
+	Fee uint64
+
+	// Key hashes expected to sign the transaction, sorted
+	Signers []ledger.Hash28
+}
+
+// CBOR returns the CBOR encoding of the unsigned transaction.
+func (r *Result) CBOR() ([]byte, error) {
+	return r.Transaction.MarshalCBOR()
+}
+
+// New returns a Builder using the protocol parameters of the current epoch,
+// as returned by LatestEpochParameters.
+func New(params blockfrost.EpochParameters) (*Builder, error) {
+	p, err := fees.FromEpochParameters(params)
+	if err != nil {
+		return nil, err
+	}
+	b := &Builder{params: p, maxValSize: defaultMaxValSize}
+	if params.MaxValSize != nil && *params.MaxValSize != "" {
+		if b.maxValSize, err = strconv.Atoi(*params.MaxValSize); err != nil {
+			return nil, fmt.Errorf("%w: max_val_size: %v", fees.ErrInvalidParameters, err)
+		}
+	}
+	return b, nil
+}
+
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// AddUTXOs adds UTXOs that may be spent by the transaction. UTXOs locked by
+// scripts or held by Byron addresses cannot be spent by the builder and are
+// ignored.
+func (b *Builder) AddUTXOs(utxos ...blockfrost.AddressUTXO) *Builder {
+	for _, u := range utxos {
+		addr, err := address.Parse(u.Address)
+		if err != nil {
+			return b.fail(fmt.Errorf("txbuilder: utxo %s#%d: %w", u.TxHash, u.OutputIndex, err))
+		}
+		if addr.Byron != nil || addr.Payment.IsZero() || addr.Payment.Type != address.CredentialKey {
+			continue
+		}
+		txID, err := ledger.Hash32FromHex(u.TxHash)
+		if err != nil {
+			return b.fail(fmt.Errorf("txbuilder: utxo %s#%d: %w", u.TxHash, u.OutputIndex, err))
+		}
+		if _, err := value.FromAddressAmounts(u.Amount); err != nil {
+			return b.fail(fmt.Errorf("txbuilder: utxo %s#%d: %w", u.TxHash, u.OutputIndex, err))
+		}
+		b.utxos = append(b.utxos, utxo{
+			src:     u,
+			input:   ledger.TransactionInput{TxID: txID, Index: uint64(u.OutputIndex)},
+			keyHash: addr.Payment.Hash,
+		})
+	}
+	return b
+}
+
+// AddOutput adds an output to the transaction.
+func (b *Builder) AddOutput(out Output) *Builder {
+	b.outputs = append(b.outputs, out)
+	return b
+}
+
+// PayTo adds an output paying v to addr.
+func (b *Builder) PayTo(addr string, v value.Value) *Builder {
+	return b.AddOutput(Output{Address: addr, Value: v})
+}
+
+// SetChangeAddress sets the address receiving the change of the
+// transaction.
+func (b *Builder) SetChangeAddress(addr string) *Builder {
+	b.change = addr
+	return b
+}
+
+// SetMetadata attaches metadata to the transaction under label. v is
+// converted as described in EncodeMetadatum.
+func (b *Builder) SetMetadata(label uint64, v interface{}) *Builder {
+	data, err := EncodeMetadatum(v)
+	if err != nil {
+		return b.fail(fmt.Errorf("txbuilder: metadata label %d: %w", label, err))
+	}
+	return b.SetMetadataCBOR(label, data)
+}
+
+// SetMetadataCBOR attaches the CBOR encoding of a metadatum to the
+// transaction under label.
+func (b *Builder) SetMetadataCBOR(label uint64, data []byte) *Builder {
+	if b.metadata == nil {
+		b.metadata = map[uint64][]byte{}
+	}
+	b.metadata[label] = data
+	return b
+}
+
+// ValidFrom makes the transaction invalid before slot.
+func (b *Builder) ValidFrom(slot uint64) *Builder {
+	b.validFrom = &slot
+	return b
+}
+
+// ValidUntil makes the transaction invalid from slot onwards.
+func (b *Builder) ValidUntil(slot uint64) *Builder {
+	b.validUntil = &slot
+	return b
+}
+
+// ValidFor makes the transaction valid for slots slots after tip, the latest
+// block as returned by BlockLatest.
+func (b *Builder) ValidFor(tip blockfrost.Block, slots uint64) *Builder {
+	if tip.Slot < 0 {
+		return b.fail(fmt.Errorf("txbuilder: invalid tip slot %d", tip.Slot))
+	}
+	return b.ValidUntil(uint64(tip.Slot) + slots)
+}
+
+// BurnDust lets Build leave to the fee a change holding no assets and less
+// than the minimum amount of lovelace of an output, when no UTXO is left to
+// add to it. Build fails with ErrChangeTooSmall otherwise.
+func (b *Builder) BurnDust() *Builder {
+	b.burnDust = true
+	return b
+}
+
+// AddRequiredSigner requires the transaction to be signed by keyHash, in
+// addition to the keys of the spent UTXOs.
+func (b *Builder) AddRequiredSigner(keyHash ledger.Hash28) *Builder {
+	b.signers = append(b.signers, keyHash)
+	return b
+}
+
+// Build selects inputs and returns the unsigned transaction.
+func (b *Builder) Build() (*Result, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.change == "" {
+		return nil, ErrNoChangeAddress
+	}
+	changeAddr, err := address.Parse(b.change)
+	if err != nil {
+		return nil, fmt.Errorf("txbuilder: change address: %w", err)
+	}
+	outputs, required, err := b.buildOutputs()
+	if err != nil {
+		return nil, err
+	}
+	var aux *ledger.AuxiliaryData
+	if len(b.metadata) > 0 {
+		aux = &ledger.AuxiliaryData{Metadata: b.metadata}
+	}
+
+	sources := make([]blockfrost.AddressUTXO, len(b.utxos))
+	for i, u := range b.utxos {
+		sources[i] = u.src
+	}
+	selParams := coinselection.Params{MaxTxSize: int(b.params.MaxTxSize)}
+	if selParams.MaxTxSize > 0 {
+		if selParams.ReservedSize, err = b.reservedSize(changeAddr, outputs, aux); err != nil {
+			return nil, err
+		}
+	}
+
+	// The fee is the minimum fee of the transaction it is part of, which
+	// depends on the fee itself through the change. tried records the fees
+	// already used, to stop when lowering the fee would cycle.
+	var (
+		fee, dust uint64
+		tried     = map[uint64]bool{}
+		extra     = new(big.Int)
+	)
+	for i := 0; i < maxIterations; i++ {
+		target := required.Add(value.Value{Lovelace: new(big.Int).Add(new(big.Int).SetUint64(fee), extra)})
+		sel, err := coinselection.LargestFirst(sources, []value.Value{target}, selParams)
+		if err != nil {
+			return nil, err
+		}
+		selected := b.lookup(sel.Inputs)
+		change := sel.Total.Sub(required).Sub(value.Value{Lovelace: new(big.Int).SetUint64(fee)})
+		changeOuts, deficit, err := b.buildChange(changeAddr, change)
+		if err != nil {
+			return nil, err
+		}
+		if deficit > 0 {
+			if len(sel.Remaining) == 0 && len(change.Assets) == 0 {
+				// The change is too small for an output: leave it to the
+				// fee, which fails below unless BurnDust was called.
+				dust += change.Coin().Uint64()
+				fee += change.Coin().Uint64()
+				continue
+			}
+			extra.Add(extra, new(big.Int).SetUint64(deficit))
+			continue
+		}
+
+		tx := b.assemble(selected, append(append([]ledger.TransactionOutput{}, outputs...), changeOuts...), fee, aux)
+		signers := b.expectedSigners(selected)
+		signed := *tx
+		signed.WitnessSet = ledger.WitnessSet{VKeyWitnesses: make([]ledger.VKeyWitness, len(signers))}
+		minFee, err := b.params.MinFee(&signed, 0)
+		if err != nil {
+			return nil, err
+		}
+		tried[fee] = true
+		if minFee > fee || minFee < fee && dust == 0 && !tried[minFee] {
+			fee = minFee
+			continue
+		}
+		if dust > 0 && !b.burnDust {
+			return nil, fmt.Errorf("%w: fee would be %d lovelace, minimum is %d", ErrChangeTooSmall, fee, minFee)
+		}
+
+		size, err := signed.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+		if b.params.MaxTxSize > 0 && uint64(len(size)) > b.params.MaxTxSize {
+			return nil, fmt.Errorf("%w: %d bytes, maximum is %d", ErrTxTooLarge, len(size), b.params.MaxTxSize)
+		}
+		res := &Result{Transaction: tx, Fee: fee, Signers: signers}
+		for _, in := range tx.Body.Inputs {
+			for _, u := range selected {
+				if u.input == in {
+					res.Inputs = append(res.Inputs, u.src)
+				}
+			}
+		}
+		return res, nil
+	}
+	return nil, errors.New("txbuilder: fee did not converge")
+}
+
+// reservedSize returns the size of the transaction apart from its inputs
+// and witnesses, with a single change output holding no assets, for coin
+// selection to limit the number of inputs.
+func (b *Builder) reservedSize(changeAddr address.Address, outputs []ledger.TransactionOutput, aux *ledger.AuxiliaryData) (int, error) {
+	change := ledger.NewTransactionOutput(changeAddr, ledger.Value{Coin: ^uint64(0)})
+	tx := b.assemble(nil, append(append([]ledger.TransactionOutput{}, outputs...), change), ^uint64(0), aux)
+	data, err := tx.MarshalCBOR()
+	if err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// buildOutputs converts the outputs of the transaction and returns their
+// total value.
+func (b *Builder) buildOutputs() ([]ledger.TransactionOutput, value.Value, error) {
+	var outputs []ledger.TransactionOutput
+	var total value.Value
+	for i, o := range b.outputs {
+		addr, err := address.Parse(o.Address)
+		if err != nil {
+			return nil, value.Value{}, fmt.Errorf("%w: output %d: %v", ErrInvalidOutput, i, err)
+		}
+		amount, err := toLedgerValue(o.Value)
+		if err != nil {
+			return nil, value.Value{}, fmt.Errorf("%w: output %d: %v", ErrInvalidOutput, i, err)
+		}
+		out := ledger.NewTransactionOutput(addr, amount)
+		out.DatumHash = o.DatumHash
+		out.ScriptRef = o.ScriptRef
+		if o.Datum != nil {
+			if out.Datum, err = plutusdata.Encode(o.Datum); err != nil {
+				return nil, value.Value{}, fmt.Errorf("%w: output %d: %v", ErrInvalidOutput, i, err)
+			}
+		}
+		min, err := b.params.MinLovelace(out)
+		if err != nil {
+			return nil, value.Value{}, err
+		}
+		switch {
+		case out.Amount.Coin == 0:
+			out.Amount.Coin = min
+		case out.Amount.Coin < min:
+			return nil, value.Value{}, fmt.Errorf("%w: output %d holds %d lovelace, minimum is %d", ErrInvalidOutput, i, out.Amount.Coin, min)
+		}
+		outputs = append(outputs, out)
+		paid := o.Value.Clone()
+		paid.Lovelace = new(big.Int).SetUint64(out.Amount.Coin)
+		total = total.Add(paid)
+	}
+	return outputs, total, nil
+}
+
+// buildChange returns the outputs returning change to addr, and the amount
+// of lovelace missing for them to hold their minimum amount.
+func (b *Builder) buildChange(addr address.Address, change value.Value) ([]ledger.TransactionOutput, uint64, error) {
+	if change.IsZero() {
+		return nil, 0, nil
+	}
+	if change.IsNegative() {
+		return nil, 0, fmt.Errorf("%w: negative change %s", ErrInsufficientFunds, change)
+	}
+	bundles, err := b.splitAssets(addr, change)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	coin := change.Coin()
+	outs := make([]ledger.TransactionOutput, len(bundles))
+	needed := new(big.Int)
+	for i, bundle := range bundles {
+		outs[i] = ledger.NewTransactionOutput(addr, bundle)
+		min, err := b.params.MinLovelace(outs[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		outs[i].Amount.Coin = min
+		needed.Add(needed, new(big.Int).SetUint64(min))
+	}
+	if coin.Cmp(needed) < 0 {
+		return nil, new(big.Int).Sub(needed, coin).Uint64(), nil
+	}
+	last := &outs[len(outs)-1]
+	last.Amount.Coin += new(big.Int).Sub(coin, needed).Uint64()
+	return outs, 0, nil
+}
+
+// splitAssets groups the assets of change into bundles, each fitting in an
+// output of at most max_val_size bytes. A change without assets is a single
+// empty bundle.
+func (b *Builder) splitAssets(addr address.Address, change value.Value) ([]ledger.Value, error) {
+	ids := change.AssetIDs()
+	if len(ids) == 0 {
+		return []ledger.Value{{}}, nil
+	}
+	var bundles []ledger.Value
+	cur := ledger.Value{Assets: ledger.MultiAsset{}}
+	for _, id := range ids {
+		q := change.Assets[id]
+		if !q.IsUint64() {
+			return nil, fmt.Errorf("%w: quantity %s of %s out of range", ErrInvalidOutput, q, id)
+		}
+		policy, name := assetKey(id)
+		next := cloneMultiAsset(cur.Assets)
+		if next[policy] == nil {
+			next[policy] = map[ledger.AssetName]uint64{}
+		}
+		next[policy][name] = q.Uint64()
+
+		// Measure with the largest lovelace amount a change output may hold.
+		out := ledger.NewTransactionOutput(addr, ledger.Value{Coin: ^uint64(0), Assets: next})
+		enc, _ := out.MarshalCBOR()
+		if len(enc) > b.maxValSize && len(cur.Assets) > 0 {
+			bundles = append(bundles, cur)
+			next = ledger.MultiAsset{policy: {name: q.Uint64()}}
+		}
+		cur = ledger.Value{Assets: next}
+	}
+	return append(bundles, cur), nil
+}
+
+func cloneMultiAsset(m ledger.MultiAsset) ledger.MultiAsset {
+	out := make(ledger.MultiAsset, len(m))
+	for policy, assets := range m {
+		out[policy] = make(map[ledger.AssetName]uint64, len(assets))
+		for name, q := range assets {
+			out[policy][name] = q
+		}
+	}
+	return out
+}
+
+func assetKey(id blockfrost.AssetID) (ledger.Hash28, ledger.AssetName) {
+	var policy ledger.Hash28
+	copy(policy[:], id.PolicyIDBytes())
+	return policy, ledger.AssetName(id.NameBytes())
+}
+
+// toLedgerValue converts v, which must not be negative.
+func toLedgerValue(v value.Value) (ledger.Value, error) {
+	if v.IsNegative() {
+		return ledger.Value{}, fmt.Errorf("negative value %s", v)
+	}
+	coin := v.Coin()
+	if !coin.IsUint64() {
+		return ledger.Value{}, fmt.Errorf("lovelace %s out of range", coin)
+	}
+	out := ledger.Value{Coin: coin.Uint64()}
+	for _, id := range v.AssetIDs() {
+		q := v.Assets[id]
+		if !q.IsUint64() {
+			return ledger.Value{}, fmt.Errorf("quantity %s of %s out of range", q, id)
+		}
+		if out.Assets == nil {
+			out.Assets = ledger.MultiAsset{}
+		}
+		policy, name := assetKey(id)
+		if out.Assets[policy] == nil {
+			out.Assets[policy] = map[ledger.AssetName]uint64{}
+		}
+		out.Assets[policy][name] = q.Uint64()
+	}
+	return out, nil
+}
+
+// lookup returns the UTXOs of the builder spent by inputs.
+func (b *Builder) lookup(inputs []blockfrost.AddressUTXO) []utxo {
+	out := make([]utxo, 0, len(inputs))
+	for _, in := range inputs {
+		for _, u := range b.utxos {
+			if u.src.TxHash == in.TxHash && u.src.OutputIndex == in.OutputIndex {
+				out = append(out, u)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// expectedSigners returns the key hashes expected to sign a transaction
+// spending utxos.
+func (b *Builder) expectedSigners(utxos []utxo) []ledger.Hash28 {
+	seen := map[ledger.Hash28]bool{}
+	var out []ledger.Hash28
+	add := func(h ledger.Hash28) {
+		if !seen[h] {
+			seen[h] = true
+			out = append(out, h)
+		}
+	}
+	for _, u := range utxos {
+		add(u.keyHash)
+	}
+	for _, h := range b.signers {
+		add(h)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return bytes.Compare(out[i][:], out[j][:]) < 0
+	})
+	return out
+}
+
+func (b *Builder) assemble(inputs []utxo, outputs []ledger.TransactionOutput, fee uint64, aux *ledger.AuxiliaryData) *ledger.Transaction {
+	tx := &ledger.Transaction{AuxiliaryData: aux}
+	body := &tx.Body
+	for _, u := range inputs {
+		body.Inputs = append(body.Inputs, u.input)
+	}
+	sort.Slice(body.Inputs, func(i, j int) bool {
+		a, c := body.Inputs[i], body.Inputs[j]
+		if a.TxID != c.TxID {
+			return bytes.Compare(a.TxID[:], c.TxID[:]) < 0
+		}
+		return a.Index < c.Index
+	})
+	body.Outputs = outputs
+	body.Fee = fee
+	body.TTL = b.validUntil
+	body.ValidityIntervalStart = b.validFrom
+	body.RequiredSigners = b.signers
+	if aux != nil {
+		h := aux.Hash()
+		body.AuxiliaryDataHash = &h
+	}
+	return tx
+}
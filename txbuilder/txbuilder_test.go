@@ -0,0 +1,449 @@
+package txbuilder_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/address"
+	"github.com/blockfrost/blockfrost-go/fees"
+	"github.com/blockfrost/blockfrost-go/ledger"
+	"github.com/blockfrost/blockfrost-go/txbuilder"
+	"github.com/blockfrost/blockfrost-go/value"
+)
+
+const testdata = "../testdata"
+
+var policy = strings.Repeat("ab", 28)
+
+func loadParams(t *testing.T) blockfrost.EpochParameters {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(testdata, "epochparametersintegration.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ep := blockfrost.EpochParameters{}
+	if err := json.Unmarshal(data, &ep); err != nil {
+		t.Fatal(err)
+	}
+	return ep
+}
+
+func newBuilder(t *testing.T) *txbuilder.Builder {
+	t.Helper()
+	b, err := txbuilder.New(loadParams(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func keyAddress(t *testing.T, key byte) string {
+	t.Helper()
+	payment, err := address.NewKeyCredential(bytes.Repeat([]byte{key}, 28))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stake, _ := address.NewKeyCredential(bytes.Repeat([]byte{0xee}, 28))
+	return address.NewBaseAddress(address.Testnet, payment, stake).String()
+}
+
+// utxo returns a UTXO holding lovelace and the given quantities of assets,
+// keyed by unit.
+func utxo(addr string, tx byte, lovelace uint64, assets ...string) blockfrost.AddressUTXO {
+	u := blockfrost.AddressUTXO{
+		Address: addr,
+		TxHash:  strings.Repeat(fmt.Sprintf("%02x", tx), 32),
+		Amount:  []blockfrost.AddressAmount{{Unit: "lovelace", Quantity: strconv.FormatUint(lovelace, 10)}},
+	}
+	for i := 0; i+1 < len(assets); i += 2 {
+		u.Amount = append(u.Amount, blockfrost.AddressAmount{Unit: assets[i], Quantity: assets[i+1]})
+	}
+	return u
+}
+
+func token(name string) string {
+	return policy + hex.EncodeToString([]byte(name))
+}
+
+// checkBalance checks that res spends as much as it pays, and that its fee
+// is the minimum fee once signed.
+func checkBalance(t *testing.T, res *txbuilder.Result) {
+	t.Helper()
+	in, err := value.SumAddressUTXOs(res.Inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := value.New(int64(res.Fee))
+	for _, o := range res.Transaction.Body.Outputs {
+		v := value.NewFromBig(new(big.Int).SetUint64(o.Amount.Coin))
+		for p, assets := range o.Amount.Assets {
+			for name, q := range assets {
+				id, err := blockfrost.NewAssetIDFromBytes(p[:], []byte(name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				v = v.WithAsset(id, new(big.Int).SetUint64(q))
+			}
+		}
+		out = out.Add(v)
+	}
+	if !in.Equal(out) {
+		t.Fatalf("inputs %s, outputs and fee %s", in, out)
+	}
+	if res.Transaction.Body.Fee != res.Fee {
+		t.Fatalf("got body fee %d, want %d", res.Transaction.Body.Fee, res.Fee)
+	}
+
+	p, err := fees.FromEpochParameters(loadParams(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := *res.Transaction
+	signed.WitnessSet = ledger.WitnessSet{VKeyWitnesses: make([]ledger.VKeyWitness, len(res.Signers))}
+	min, err := p.MinFee(&signed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Fee != min {
+		t.Fatalf("got fee %d, minimum is %d", res.Fee, min)
+	}
+
+	// The transaction round trips through its encoding.
+	data, err := res.CBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ledger.DecodeTransaction(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	sender := keyAddress(t, 1)
+	receiver := keyAddress(t, 2)
+	res, err := newBuilder(t).
+		AddUTXOs(
+			utxo(sender, 1, 5000000),
+			utxo(sender, 2, 10000000),
+			utxo(sender, 3, 2000000),
+		).
+		PayTo(receiver, value.New(7000000)).
+		SetChangeAddress(sender).
+		ValidFrom(900).
+		ValidFor(blockfrost.Block{Slot: 1000}, 100).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, res)
+
+	// The largest UTXO covers the payment on its own.
+	if len(res.Inputs) != 1 || res.Inputs[0].TxHash != strings.Repeat("02", 32) {
+		t.Fatalf("unexpected inputs %+v", res.Inputs)
+	}
+	body := res.Transaction.Body
+	if len(body.Outputs) != 2 || body.Outputs[0].Amount.Coin != 7000000 {
+		t.Fatalf("unexpected outputs %+v", body.Outputs)
+	}
+	if addr, _ := body.Outputs[1].ParseAddress(); addr.String() != sender {
+		t.Fatalf("got change address %s", addr)
+	}
+	if *body.TTL != 1100 || *body.ValidityIntervalStart != 900 {
+		t.Fatalf("got validity interval [%d, %d)", *body.ValidityIntervalStart, *body.TTL)
+	}
+	if len(res.Signers) != 1 || res.Signers[0] != (ledger.Hash28{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}) {
+		t.Fatalf("unexpected signers %v", res.Signers)
+	}
+	if len(res.Transaction.WitnessSet.VKeyWitnesses) != 0 {
+		t.Fatal("expected an unsigned transaction")
+	}
+}
+
+func TestBuildMultiAsset(t *testing.T) {
+	sender := keyAddress(t, 1)
+	receiver := keyAddress(t, 2)
+	id, err := blockfrost.ParseAssetID(token("coin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := newBuilder(t).
+		AddUTXOs(
+			utxo(sender, 1, 20000000),
+			utxo(sender, 2, 1500000, token("coin"), "10", token("other"), "1"),
+		).
+		PayTo(receiver, value.Value{}.WithAsset(id, big.NewInt(4))).
+		SetChangeAddress(sender).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, res)
+
+	out := res.Transaction.Body.Outputs[0]
+	p, _ := fees.FromEpochParameters(loadParams(t))
+	if min, _ := p.MinLovelace(out); out.Amount.Coin != min {
+		t.Fatalf("got %d lovelace, want the minimum %d", out.Amount.Coin, min)
+	}
+	// The token UTXO does not hold enough lovelace for the payment and the
+	// change, so both UTXOs are spent.
+	if len(res.Inputs) != 2 {
+		t.Fatalf("got %d inputs", len(res.Inputs))
+	}
+	var h ledger.Hash28
+	copy(h[:], id.PolicyIDBytes())
+	change := res.Transaction.Body.Outputs[1].Amount.Assets[h]
+	if change["coin"] != 6 || change["other"] != 1 {
+		t.Fatalf("unexpected change %v", change)
+	}
+}
+
+func TestBuildInsufficientFunds(t *testing.T) {
+	sender := keyAddress(t, 1)
+	receiver := keyAddress(t, 2)
+	id, _ := blockfrost.ParseAssetID(token("coin"))
+
+	for name, v := range map[string]value.Value{
+		"lovelace": value.New(5000000),
+		"asset":    value.Value{}.WithAsset(id, big.NewInt(1)),
+		"fee":      value.New(4900000),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := newBuilder(t).
+				AddUTXOs(utxo(sender, 1, 3000000), utxo(sender, 2, 2000000)).
+				PayTo(receiver, v).
+				SetChangeAddress(sender).
+				Build()
+			if !errors.Is(err, txbuilder.ErrInsufficientFunds) {
+				t.Fatalf("got %v, want ErrInsufficientFunds", err)
+			}
+		})
+	}
+}
+
+func TestBuildScriptUTXOsIgnored(t *testing.T) {
+	script, err := address.NewScriptCredential(bytes.Repeat([]byte{3}, 28))
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked := address.NewEnterpriseAddress(address.Testnet, script).String()
+	_, err = newBuilder(t).
+		AddUTXOs(utxo(locked, 1, 100000000)).
+		PayTo(keyAddress(t, 2), value.New(2000000)).
+		SetChangeAddress(keyAddress(t, 1)).
+		Build()
+	if !errors.Is(err, txbuilder.ErrInsufficientFunds) {
+		t.Fatalf("got %v, want ErrInsufficientFunds", err)
+	}
+}
+
+func TestBuildSmallChange(t *testing.T) {
+	sender := keyAddress(t, 1)
+	build := func(b *txbuilder.Builder) (*txbuilder.Result, error) {
+		return b.AddUTXOs(utxo(sender, 1, 2000000)).
+			PayTo(keyAddress(t, 2), value.New(1700000)).
+			SetChangeAddress(sender).
+			Build()
+	}
+
+	// The change is below the minimum output and no UTXO is left.
+	if _, err := build(newBuilder(t)); !errors.Is(err, txbuilder.ErrChangeTooSmall) {
+		t.Fatalf("got %v, want ErrChangeTooSmall", err)
+	}
+
+	res, err := build(newBuilder(t).BurnDust())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Transaction.Body.Outputs) != 1 || res.Fee != 300000 || res.Transaction.Body.Fee != res.Fee {
+		t.Fatalf("got %d outputs and fee %d", len(res.Transaction.Body.Outputs), res.Fee)
+	}
+}
+
+func TestBuildFeeDecreases(t *testing.T) {
+	// With these parameters the fee is encoded on 3 bytes, while the change
+	// is encoded on 9 bytes before the fee is deducted and on 5 bytes after:
+	// the transaction shrinks as the fee is set, and so does its fee.
+	ep := loadParams(t)
+	ep.MinFeeA = 1
+	ep.MinFeeB = 0
+	b, err := txbuilder.New(ep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := keyAddress(t, 1)
+	res, err := b.
+		AddUTXOs(utxo(sender, 1, 2000000+1<<32+100)).
+		PayTo(keyAddress(t, 2), value.New(2000000)).
+		SetChangeAddress(sender).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := fees.FromEpochParameters(ep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := *res.Transaction
+	signed.WitnessSet = ledger.WitnessSet{VKeyWitnesses: make([]ledger.VKeyWitness, len(res.Signers))}
+	min, err := p.MinFee(&signed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	change := res.Transaction.Body.Outputs[1].Amount.Coin
+	if res.Fee != min || change+res.Fee != 1<<32+100 {
+		t.Fatalf("got fee %d and change %d, minimum fee is %d", res.Fee, change, min)
+	}
+}
+
+func TestBuildSplitsChange(t *testing.T) {
+	ep := loadParams(t)
+	size := "300"
+	ep.MaxValSize = &size
+	b, err := txbuilder.New(ep)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender := keyAddress(t, 1)
+	var assets []string
+	for i := 0; i < 20; i++ {
+		assets = append(assets, token(fmt.Sprintf("token%02d", i)), "1000")
+	}
+	res, err := b.
+		AddUTXOs(utxo(sender, 1, 50000000, assets...)).
+		PayTo(keyAddress(t, 2), value.New(2000000)).
+		SetChangeAddress(sender).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, res)
+
+	change := res.Transaction.Body.Outputs[1:]
+	if len(change) < 2 {
+		t.Fatalf("got %d change outputs, expected the change to be split", len(change))
+	}
+	n := 0
+	for _, out := range change {
+		data, _ := out.MarshalCBOR()
+		if len(data) > 300 {
+			t.Fatalf("got change output of %d bytes", len(data))
+		}
+		for _, assets := range out.Amount.Assets {
+			n += len(assets)
+		}
+	}
+	if n != 20 {
+		t.Fatalf("got %d assets in change", n)
+	}
+}
+
+func TestBuildMetadata(t *testing.T) {
+	sender := keyAddress(t, 1)
+	res, err := newBuilder(t).
+		AddUTXOs(utxo(sender, 1, 10000000)).
+		PayTo(keyAddress(t, 2), value.New(2000000)).
+		SetChangeAddress(sender).
+		SetMetadata(674, map[string]interface{}{"msg": []interface{}{"hello", 1.0}}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, res)
+
+	aux := res.Transaction.AuxiliaryData
+	if aux == nil || hex.EncodeToString(aux.Metadata[674]) != "a1636d7367826568656c6c6f01" {
+		t.Fatalf("unexpected auxiliary data %+v", aux)
+	}
+	if h := aux.Hash(); res.Transaction.Body.AuxiliaryDataHash == nil || *res.Transaction.Body.AuxiliaryDataHash != h {
+		t.Fatal("auxiliary data hash mismatch")
+	}
+}
+
+func TestEncodeMetadatum(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{"abc", "63616263"},
+		{[]byte{1, 2}, "420102"},
+		{-1, "20"},
+		{uint64(1 << 63), "1b8000000000000000"},
+		{json.Number("18446744073709551615"), "1bffffffffffffffff"},
+		{map[int]string{2: "b", 1: "a"}, "a2016161026162"},
+		{[]int{}, "80"},
+	}
+	for _, test := range tests {
+		got, err := txbuilder.EncodeMetadatum(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != test.want {
+			t.Errorf("%v: got %x, want %s", test.in, got, test.want)
+		}
+	}
+
+	for _, in := range []interface{}{
+		strings.Repeat("a", 65),
+		make([]byte, 65),
+		1.5,
+		json.Number("18446744073709551616"),
+		nil,
+		true,
+	} {
+		if _, err := txbuilder.EncodeMetadatum(in); !errors.Is(err, txbuilder.ErrInvalidMetadata) {
+			t.Errorf("%v: got %v, want ErrInvalidMetadata", in, err)
+		}
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	sender := keyAddress(t, 1)
+	utxos := []blockfrost.AddressUTXO{utxo(sender, 1, 10000000)}
+
+	_, err := newBuilder(t).AddUTXOs(utxos...).PayTo(keyAddress(t, 2), value.New(2000000)).Build()
+	if !errors.Is(err, txbuilder.ErrNoChangeAddress) {
+		t.Fatalf("got %v, want ErrNoChangeAddress", err)
+	}
+
+	_, err = newBuilder(t).AddUTXOs(utxos...).PayTo(keyAddress(t, 2), value.New(100000)).SetChangeAddress(sender).Build()
+	if !errors.Is(err, txbuilder.ErrInvalidOutput) {
+		t.Fatalf("got %v, want ErrInvalidOutput", err)
+	}
+
+	_, err = newBuilder(t).AddUTXOs(utxos...).SetMetadata(1, 1.5).SetChangeAddress(sender).Build()
+	if !errors.Is(err, txbuilder.ErrInvalidMetadata) {
+		t.Fatalf("got %v, want ErrInvalidMetadata", err)
+	}
+
+	ep := loadParams(t)
+	ep.MaxTxSize = 200
+	b, _ := txbuilder.New(ep)
+	_, err = b.AddUTXOs(utxos...).PayTo(keyAddress(t, 2), value.New(2000000)).SetChangeAddress(sender).Build()
+	if !errors.Is(err, txbuilder.ErrTxTooLarge) {
+		t.Fatalf("got %v, want ErrTxTooLarge", err)
+	}
+
+	// Coin selection is limited by the maximum transaction size.
+	ep.MaxTxSize = 700
+	var small []blockfrost.AddressUTXO
+	for i := 0; i < 20; i++ {
+		small = append(small, utxo(sender, byte(i+1), 1000000))
+	}
+	b, _ = txbuilder.New(ep)
+	_, err = b.AddUTXOs(small...).PayTo(keyAddress(t, 2), value.New(10000000)).SetChangeAddress(sender).Build()
+	if !errors.Is(err, txbuilder.ErrTooManyInputs) {
+		t.Fatalf("got %v, want ErrTooManyInputs", err)
+	}
+}
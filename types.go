@@ -12,15 +12,16 @@ const (
 
 // resource paths
 const (
-	resourceHealth                   = "health"
-	resourceHealthClock              = "health/clock"
-	resourceMetrics                  = "metrics"
-	resourceMetricsEndpoint          = "metrics/endpoints"
-	resourceBlock                    = "blocks"
-	resourceBlocksLatest             = "blocks/latest"
-	resourceBlocksLatestTransactions = "blocks/latest/txs"
-	resourceBlocksSlot               = "blocks/slot"
-	resourceBlocksEpoch              = "blocks/epoch"
+	resourceHealth                       = "health"
+	resourceHealthClock                  = "health/clock"
+	resourceMetrics                      = "metrics"
+	resourceMetricsEndpoint              = "metrics/endpoints"
+	resourceBlock                        = "blocks"
+	resourceBlocksLatest                 = "blocks/latest"
+	resourceBlocksLatestTransactions     = "blocks/latest/txs"
+	resourceBlocksLatestTransactionsCBOR = "blocks/latest/txs/cbor"
+	resourceBlocksSlot                   = "blocks/slot"
+	resourceBlocksEpoch                  = "blocks/epoch"
 )
 
 // APIError is used to describe errors from the API.
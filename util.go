@@ -2,6 +2,7 @@ package blockfrost
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -125,3 +126,13 @@ func (ip *ipfsClient) handleRequest(req *http.Request) (res *http.Response, err
 
 	return res, nil
 }
+
+// isNotFound reports whether err is a 404 response.
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	_, ok := apiErr.Response.(NotFound)
+	return ok
+}
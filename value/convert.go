@@ -0,0 +1,145 @@
+package value
+
+import (
+	"github.com/blockfrost/blockfrost-go"
+)
+
+// fromUnits builds a Value from parallel unit and quantity accessors, which
+// lets the exported helpers share the parsing of every amount type.
+func fromUnits(n int, unit func(i int) string, quantity func(i int) string) (Value, error) {
+	out := Value{}
+	for i := 0; i < n; i++ {
+		id, err := blockfrost.ParseAssetID(unit(i))
+		if err != nil {
+			return Value{}, err
+		}
+		q, err := ParseQuantity(quantity(i))
+		if err != nil {
+			return Value{}, err
+		}
+		out.add(id, q)
+	}
+	return out, nil
+}
+
+// FromAddressAmounts converts amounts such as Address.Amount or
+// AddressUTXO.Amount to a Value.
+func FromAddressAmounts(amounts []blockfrost.AddressAmount) (Value, error) {
+	return fromUnits(len(amounts),
+		func(i int) string { return amounts[i].Unit },
+		func(i int) string { return amounts[i].Quantity })
+}
+
+// FromAddressAmountsExtended converts AddressExtended.Amount to a Value.
+func FromAddressAmountsExtended(amounts []blockfrost.AddressAmountExtended) (Value, error) {
+	return fromUnits(len(amounts),
+		func(i int) string { return amounts[i].Unit },
+		func(i int) string { return amounts[i].Quantity })
+}
+
+// FromTxAmounts converts amounts of transaction inputs and outputs to a Value.
+func FromTxAmounts(amounts []blockfrost.TxAmount) (Value, error) {
+	return fromUnits(len(amounts),
+		func(i int) string { return amounts[i].Unit },
+		func(i int) string { return amounts[i].Quantity })
+}
+
+// FromAccountAssociatedAssets converts the assets associated with an account
+// to a Value.
+func FromAccountAssociatedAssets(assets []blockfrost.AccountAssociatedAsset) (Value, error) {
+	return fromUnits(len(assets),
+		func(i int) string { return assets[i].Unit },
+		func(i int) string { return assets[i].Quantity })
+}
+
+// FromAssetsByPolicy converts assets minted under a policy to a Value.
+func FromAssetsByPolicy(assets []blockfrost.AssetByPolicy) (Value, error) {
+	return fromUnits(len(assets),
+		func(i int) string { return assets[i].Asset },
+		func(i int) string { return assets[i].Quantity })
+}
+
+// FromTransactionContent returns the output amount of a transaction.
+func FromTransactionContent(tx blockfrost.TransactionContent) (Value, error) {
+	return fromUnits(len(tx.OutputAmount),
+		func(i int) string { return tx.OutputAmount[i].Unit },
+		func(i int) string { return tx.OutputAmount[i].Quantity })
+}
+
+// FromMempoolTransaction returns the output amount of a mempool transaction.
+func FromMempoolTransaction(tx blockfrost.MempoolTransaction) (Value, error) {
+	return fromUnits(len(tx.OutputAmount),
+		func(i int) string { return tx.OutputAmount[i].Unit },
+		func(i int) string { return tx.OutputAmount[i].Quantity })
+}
+
+// FromAccountAddressesTotal returns the received and sent sums of an account.
+func FromAccountAddressesTotal(t blockfrost.AccountAddressesTotal) (received, sent Value, err error) {
+	received, err = fromUnits(len(t.ReceivedSum),
+		func(i int) string { return t.ReceivedSum[i].Unit },
+		func(i int) string { return t.ReceivedSum[i].Quantity })
+	if err != nil {
+		return
+	}
+	sent, err = fromUnits(len(t.SentSum),
+		func(i int) string { return t.SentSum[i].Unit },
+		func(i int) string { return t.SentSum[i].Quantity })
+	return
+}
+
+// SumAddressUTXOs returns the total value held by utxos.
+func SumAddressUTXOs(utxos []blockfrost.AddressUTXO) (Value, error) {
+	total := Value{}
+	for _, u := range utxos {
+		v, err := FromAddressAmounts(u.Amount)
+		if err != nil {
+			return Value{}, err
+		}
+		total = total.Add(v)
+	}
+	return total, nil
+}
+
+// SumTransactionInputs returns the total value of the inputs of a
+// transaction, skipping collateral and reference inputs.
+func SumTransactionInputs(utxos blockfrost.TransactionUTXOs) (Value, error) {
+	total := Value{}
+	for _, in := range utxos.Inputs {
+		if in.Collateral || (in.Reference != nil && *in.Reference) {
+			continue
+		}
+		v, err := FromTxAmounts(in.Amount)
+		if err != nil {
+			return Value{}, err
+		}
+		total = total.Add(v)
+	}
+	return total, nil
+}
+
+// SumTransactionOutputs returns the total value of the outputs of a
+// transaction, skipping collateral outputs.
+func SumTransactionOutputs(utxos blockfrost.TransactionUTXOs) (Value, error) {
+	total := Value{}
+	for _, out := range utxos.Outputs {
+		if out.Collateral {
+			continue
+		}
+		v, err := FromTxAmounts(out.Amount)
+		if err != nil {
+			return Value{}, err
+		}
+		total = total.Add(v)
+	}
+	return total, nil
+}
+
+// ToTxAmounts converts v to a list of amounts, starting with lovelace.
+func (v Value) ToTxAmounts() []blockfrost.TxAmount {
+	out := make([]blockfrost.TxAmount, 0, len(v.Assets)+1)
+	out = append(out, blockfrost.TxAmount{Unit: blockfrost.LovelaceUnit, Quantity: v.Coin().String()})
+	for _, id := range v.AssetIDs() {
+		out = append(out, blockfrost.TxAmount{Unit: id.Unit(), Quantity: v.Assets[id].String()})
+	}
+	return out
+}
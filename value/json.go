@@ -0,0 +1,187 @@
+package value
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+type amountJSON struct {
+	Unit     string `json:"unit"`
+	Quantity string `json:"quantity"`
+}
+
+// MarshalJSON marshals v in the Blockfrost format, a list of
+// {"unit", "quantity"} objects starting with lovelace.
+func (v Value) MarshalJSON() ([]byte, error) {
+	amounts := make([]amountJSON, 0, len(v.Assets)+1)
+	amounts = append(amounts, amountJSON{Unit: blockfrost.LovelaceUnit, Quantity: v.Coin().String()})
+	for _, id := range v.AssetIDs() {
+		amounts = append(amounts, amountJSON{Unit: id.Unit(), Quantity: v.Assets[id].String()})
+	}
+	return json.Marshal(amounts)
+}
+
+// UnmarshalJSON unmarshals v from the Blockfrost format, a list of
+// {"unit", "quantity"} objects, or from the Ogmios formats:
+//
+//	v5: {"coins": 1, "assets": {"<policy>.<name>": 1}}
+//	v6: {"ada": {"lovelace": 1}, "<policy>": {"<name>": 1}}
+//
+// Quantities may be JSON numbers or strings.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	out := Value{}
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*v = out
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		var amounts []struct {
+			Unit     string          `json:"unit"`
+			Quantity json.RawMessage `json:"quantity"`
+		}
+		if err := json.Unmarshal(data, &amounts); err != nil {
+			return err
+		}
+		for _, a := range amounts {
+			id, err := blockfrost.ParseAssetID(a.Unit)
+			if err != nil {
+				return err
+			}
+			q, err := parseJSONQuantity(a.Quantity)
+			if err != nil {
+				return err
+			}
+			out.add(id, q)
+		}
+	default:
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		var err error
+		if _, ok := obj["coins"]; ok {
+			err = out.unmarshalOgmiosV5(obj)
+		} else {
+			err = out.unmarshalOgmiosV6(obj)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	*v = out
+	return nil
+}
+
+func (v *Value) unmarshalOgmiosV5(obj map[string]json.RawMessage) error {
+	coins, err := parseJSONQuantity(obj["coins"])
+	if err != nil {
+		return err
+	}
+	v.add(blockfrost.Lovelace, coins)
+	raw, ok := obj["assets"]
+	if !ok {
+		return nil
+	}
+	var assets map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &assets); err != nil {
+		return err
+	}
+	for key, rawQ := range assets {
+		id, err := blockfrost.ParseAssetID(strings.Replace(key, ".", "", 1))
+		if err != nil {
+			return err
+		}
+		q, err := parseJSONQuantity(rawQ)
+		if err != nil {
+			return err
+		}
+		v.add(id, q)
+	}
+	return nil
+}
+
+func (v *Value) unmarshalOgmiosV6(obj map[string]json.RawMessage) error {
+	for policy, raw := range obj {
+		var names map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &names); err != nil {
+			return err
+		}
+		for name, rawQ := range names {
+			q, err := parseJSONQuantity(rawQ)
+			if err != nil {
+				return err
+			}
+			if policy == "ada" && name == "lovelace" {
+				v.add(blockfrost.Lovelace, q)
+				continue
+			}
+			id, err := blockfrost.NewAssetID(policy, name)
+			if err != nil {
+				return err
+			}
+			v.add(id, q)
+		}
+	}
+	return nil
+}
+
+func parseJSONQuantity(raw json.RawMessage) (*big.Int, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w: missing quantity", ErrInvalidQuantity)
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return ParseQuantity(s)
+	}
+	return ParseQuantity(string(raw))
+}
+
+// ToOgmios converts v to the Value type used in AdditionalUtxoSet.
+func (v Value) ToOgmios() blockfrost.Value {
+	out := blockfrost.Value{Coins: blockfrost.Quantity(v.Coin().String())}
+	for _, id := range v.AssetIDs() {
+		if out.Assets == nil {
+			out.Assets = map[string]blockfrost.Quantity{}
+		}
+		key := id.PolicyID()
+		if name := id.NameHex(); name != "" {
+			key += "." + name
+		}
+		out.Assets[key] = blockfrost.Quantity(v.Assets[id].String())
+	}
+	return out
+}
+
+// FromOgmios converts the Value type used in AdditionalUtxoSet to a Value.
+func FromOgmios(o blockfrost.Value) (Value, error) {
+	out := Value{}
+	if o.Coins != "" {
+		q, err := ParseQuantity(string(o.Coins))
+		if err != nil {
+			return Value{}, err
+		}
+		out.add(blockfrost.Lovelace, q)
+	}
+	for key, quantity := range o.Assets {
+		id, err := blockfrost.ParseAssetID(strings.Replace(key, ".", "", 1))
+		if err != nil {
+			return Value{}, err
+		}
+		q, err := ParseQuantity(string(quantity))
+		if err != nil {
+			return Value{}, err
+		}
+		out.add(id, q)
+	}
+	return out, nil
+}
@@ -0,0 +1,231 @@
+// Package value implements exact arithmetic on Cardano multi-asset values.
+//
+// Quantities are returned as strings by the API. Value parses them into
+// math/big integers so balances can be summed and compared without loss of
+// precision, and converts from every amount type of the SDK.
+package value
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+// ErrInvalidQuantity is returned when a quantity is not a base 10 integer.
+var ErrInvalidQuantity = errors.New("value: invalid quantity")
+
+// Value holds an amount of lovelace and of native assets. The zero Value is
+// empty and ready to use. Operations never modify their operands.
+type Value struct {
+	// Amount of lovelace, nil means zero
+	Lovelace *big.Int
+
+	// Amounts of native assets, keyed by AssetID. Lovelace must not appear
+	// in this map.
+	Assets map[blockfrost.AssetID]*big.Int
+}
+
+// New returns a Value holding only lovelace.
+func New(lovelace int64) Value {
+	return Value{Lovelace: big.NewInt(lovelace)}
+}
+
+// NewFromBig returns a Value holding only lovelace.
+func NewFromBig(lovelace *big.Int) Value {
+	return Value{Lovelace: new(big.Int).Set(lovelace)}
+}
+
+// ParseQuantity parses a quantity as returned by the API.
+func ParseQuantity(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidQuantity, s)
+	}
+	return n, nil
+}
+
+// ParseLovelace parses a lovelace quantity, such as Account.ControlledAmount
+// or PoolHistory.Rewards, into a Value.
+func ParseLovelace(s string) (Value, error) {
+	n, err := ParseQuantity(s)
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{Lovelace: n}, nil
+}
+
+// Quantity returns the quantity of an asset, which is zero when absent.
+func (v Value) Quantity(id blockfrost.AssetID) *big.Int {
+	if id.IsLovelace() {
+		return v.Coin()
+	}
+	if q, ok := v.Assets[id]; ok {
+		return new(big.Int).Set(q)
+	}
+	return new(big.Int)
+}
+
+// Coin returns the amount of lovelace.
+func (v Value) Coin() *big.Int {
+	if v.Lovelace == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(v.Lovelace)
+}
+
+// WithAsset returns a copy of v with quantity q of asset id added.
+func (v Value) WithAsset(id blockfrost.AssetID, q *big.Int) Value {
+	out := v.Clone()
+	out.add(id, q)
+	return out
+}
+
+// AssetIDs returns the native assets held by v, sorted by unit.
+func (v Value) AssetIDs() []blockfrost.AssetID {
+	ids := make([]blockfrost.AssetID, 0, len(v.Assets))
+	for id, q := range v.Assets {
+		if q.Sign() != 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].Unit() < ids[j].Unit()
+	})
+	return ids
+}
+
+// Clone returns a deep copy of v.
+func (v Value) Clone() Value {
+	out := Value{Lovelace: v.Coin()}
+	if len(v.Assets) > 0 {
+		out.Assets = make(map[blockfrost.AssetID]*big.Int, len(v.Assets))
+		for id, q := range v.Assets {
+			out.Assets[id] = new(big.Int).Set(q)
+		}
+	}
+	return out
+}
+
+func (v *Value) add(id blockfrost.AssetID, q *big.Int) {
+	if id.IsLovelace() {
+		if v.Lovelace == nil {
+			v.Lovelace = new(big.Int)
+		}
+		v.Lovelace.Add(v.Lovelace, q)
+		return
+	}
+	if v.Assets == nil {
+		v.Assets = map[blockfrost.AssetID]*big.Int{}
+	}
+	cur, ok := v.Assets[id]
+	if !ok {
+		cur = new(big.Int)
+		v.Assets[id] = cur
+	}
+	cur.Add(cur, q)
+	if cur.Sign() == 0 {
+		delete(v.Assets, id)
+	}
+}
+
+// Add returns v + o.
+func (v Value) Add(o Value) Value {
+	out := v.Clone()
+	out.add(blockfrost.Lovelace, o.Coin())
+	for id, q := range o.Assets {
+		out.add(id, q)
+	}
+	return out
+}
+
+// Sub returns v - o. Quantities of the result may be negative, which can be
+// checked with IsNegative.
+func (v Value) Sub(o Value) Value {
+	out := v.Clone()
+	out.add(blockfrost.Lovelace, new(big.Int).Neg(o.Coin()))
+	for id, q := range o.Assets {
+		out.add(id, new(big.Int).Neg(q))
+	}
+	return out
+}
+
+// IsZero reports whether v holds no lovelace and no assets.
+func (v Value) IsZero() bool {
+	if v.Lovelace != nil && v.Lovelace.Sign() != 0 {
+		return false
+	}
+	for _, q := range v.Assets {
+		if q.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsNegative reports whether any quantity of v is negative.
+func (v Value) IsNegative() bool {
+	if v.Lovelace != nil && v.Lovelace.Sign() < 0 {
+		return true
+	}
+	for _, q := range v.Assets {
+		if q.Sign() < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers reports whether v holds at least the quantity of every asset, and
+// of lovelace, held by o.
+func (v Value) Covers(o Value) bool {
+	return !v.Sub(o).IsNegative()
+}
+
+// Equal reports whether v and o hold the same quantities.
+func (v Value) Equal(o Value) bool {
+	return v.Sub(o).IsZero()
+}
+
+// Cmp compares v and o. Multi-asset values are only partially ordered: Cmp
+// returns -1, 0 or +1 with ok set when o covers v, both are equal or v covers
+// o, and ok is false when neither value covers the other.
+func (v Value) Cmp(o Value) (c int, ok bool) {
+	switch d := v.Sub(o); {
+	case d.IsZero():
+		return 0, true
+	case !d.IsNegative():
+		return 1, true
+	case !d.Neg().IsNegative():
+		return -1, true
+	}
+	return 0, false
+}
+
+// Neg returns -v.
+func (v Value) Neg() Value {
+	return Value{}.Sub(v)
+}
+
+// Policies returns the native assets of v grouped by hex encoded policy ID.
+func (v Value) Policies() map[string][]blockfrost.AssetID {
+	out := map[string][]blockfrost.AssetID{}
+	for _, id := range v.AssetIDs() {
+		out[id.PolicyID()] = append(out[id.PolicyID()], id)
+	}
+	return out
+}
+
+// String returns a human readable representation of v.
+func (v Value) String() string {
+	var sb strings.Builder
+	sb.WriteString(v.Coin().String())
+	sb.WriteString(" lovelace")
+	for _, id := range v.AssetIDs() {
+		fmt.Fprintf(&sb, " + %s %s", v.Assets[id], id.Unit())
+	}
+	return sb.String()
+}
@@ -0,0 +1,218 @@
+package value_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/value"
+)
+
+const testdata = "../testdata"
+
+const nutcoin = "b0d07d45fe9514f80213f4020e5a61241458be626841cde717cb38a76e7574636f696e"
+
+func mustAsset(t *testing.T, unit string) blockfrost.AssetID {
+	t.Helper()
+	id, err := blockfrost.ParseAssetID(unit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func mustBig(t *testing.T, s string) *big.Int {
+	t.Helper()
+	n, err := value.ParseQuantity(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestValueArithmetic(t *testing.T) {
+	nut := mustAsset(t, nutcoin)
+	// Larger than an int64
+	huge := mustBig(t, "45000000000000000000000")
+
+	a := value.New(2_000_000).WithAsset(nut, huge)
+	b := value.New(500_000).WithAsset(nut, big.NewInt(1))
+
+	sum := a.Add(b)
+	if sum.Coin().Int64() != 2_500_000 {
+		t.Fatalf("expected 2500000 got %s", sum.Coin())
+	}
+	if want := mustBig(t, "45000000000000000000001"); sum.Quantity(nut).Cmp(want) != 0 {
+		t.Fatalf("expected %s got %s", want, sum.Quantity(nut))
+	}
+	if a.Coin().Int64() != 2_000_000 {
+		t.Fatal("Add modified its operand")
+	}
+
+	if !sum.Sub(b).Equal(a) {
+		t.Fatalf("expected %s got %s", a, sum.Sub(b))
+	}
+	if !a.Sub(a).IsZero() || len(a.Sub(a).Assets) != 0 {
+		t.Fatalf("expected zero got %s", a.Sub(a))
+	}
+	if !b.Sub(a).IsNegative() {
+		t.Fatal("expected negative value")
+	}
+	if !a.Covers(b) || b.Covers(a) {
+		t.Fatal("unexpected Covers result")
+	}
+	if !(value.Value{}).IsZero() {
+		t.Fatal("expected zero value to be zero")
+	}
+}
+
+func TestValueCmp(t *testing.T) {
+	nut := mustAsset(t, nutcoin)
+	tests := []struct {
+		a, b   value.Value
+		want   int
+		wantOk bool
+	}{
+		{value.New(1), value.New(1), 0, true},
+		{value.New(2), value.New(1), 1, true},
+		{value.New(1), value.New(2), -1, true},
+		{value.New(1).WithAsset(nut, big.NewInt(1)), value.New(1), 1, true},
+		{value.New(2), value.New(1).WithAsset(nut, big.NewInt(1)), 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.a.Cmp(tt.b)
+		if got != tt.want || ok != tt.wantOk {
+			t.Fatalf("%s cmp %s: expected %d %v got %d %v", tt.a, tt.b, tt.want, tt.wantOk, got, ok)
+		}
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	for _, s := range []string{"", "1.5", "0x10", "abc"} {
+		if _, err := value.ParseQuantity(s); !errors.Is(err, value.ErrInvalidQuantity) {
+			t.Fatalf("%q: expected %v got %v", s, value.ErrInvalidQuantity, err)
+		}
+	}
+	v, err := value.ParseLovelace("18446744073709551616")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Coin().String() != "18446744073709551616" {
+		t.Fatalf("expected 18446744073709551616 got %s", v.Coin())
+	}
+}
+
+func TestValueJSON(t *testing.T) {
+	nut := mustAsset(t, nutcoin)
+	want := value.New(42_000_000).WithAsset(nut, big.NewInt(12))
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"blockfrost", `[{"unit":"lovelace","quantity":"42000000"},{"unit":"` + nutcoin + `","quantity":"12"}]`},
+		{"ogmios v5", `{"coins":42000000,"assets":{"b0d07d45fe9514f80213f4020e5a61241458be626841cde717cb38a7.6e7574636f696e":12}}`},
+		{"ogmios v6", `{"ada":{"lovelace":42000000},"b0d07d45fe9514f80213f4020e5a61241458be626841cde717cb38a7":{"6e7574636f696e":"12"}}`},
+	}
+	for _, tt := range tests {
+		var got value.Value
+		if err := json.Unmarshal([]byte(tt.data), &got); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("%s: expected %s got %s", tt.name, want, got)
+		}
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != tests[0].data {
+		t.Fatalf("expected %s got %s", tests[0].data, data)
+	}
+
+	var got value.Value
+	if err := json.Unmarshal([]byte(`[{"unit":"lovelace","quantity":"1.5"}]`), &got); !errors.Is(err, value.ErrInvalidQuantity) {
+		t.Fatalf("expected %v got %v", value.ErrInvalidQuantity, err)
+	}
+}
+
+func TestValueOgmios(t *testing.T) {
+	nut := mustAsset(t, nutcoin)
+	policy := mustAsset(t, nut.PolicyID())
+	want := value.New(7).WithAsset(nut, big.NewInt(3)).WithAsset(policy, big.NewInt(1))
+
+	o := want.ToOgmios()
+	if o.Coins != "7" || o.Assets[nut.PolicyID()+"."+nut.NameHex()] != "3" || o.Assets[nut.PolicyID()] != "1" {
+		t.Fatalf("unexpected ogmios value %+v", o)
+	}
+	got, err := value.FromOgmios(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Fatalf("expected %s got %s", want, got)
+	}
+}
+
+func TestValueFromTransactionUTXOs(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(testdata, "json", "transactions", "transaction_utxo.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxos := blockfrost.TransactionUTXOs{}
+	if err := json.Unmarshal(data, &utxos); err != nil {
+		t.Fatal(err)
+	}
+	in, err := value.SumTransactionInputs(utxos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := value.SumTransactionOutputs(utxos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := value.New(42_000_000).WithAsset(mustAsset(t, nutcoin), big.NewInt(12))
+	if !in.Equal(want) || !out.Equal(want) {
+		t.Fatalf("expected %s got %s and %s", want, in, out)
+	}
+
+	amounts := want.ToTxAmounts()
+	back, err := value.FromTxAmounts(amounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !back.Equal(want) || amounts[0].Unit != blockfrost.LovelaceUnit {
+		t.Fatalf("expected %s got %s", want, back)
+	}
+
+	if _, err := value.FromTxAmounts([]blockfrost.TxAmount{{Unit: "", Quantity: "1"}}); !errors.Is(err, blockfrost.ErrInvalidAssetUnit) {
+		t.Fatalf("expected %v got %v", blockfrost.ErrInvalidAssetUnit, err)
+	}
+}
+
+func TestValueFromAccountAssociatedAssets(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(testdata, "json", "account", "account_associated_assets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var assets []blockfrost.AccountAssociatedAsset
+	if err := json.Unmarshal(data, &assets); err != nil {
+		t.Fatal(err)
+	}
+	got, err := value.FromAccountAssociatedAssets(assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Coin().Sign() != 0 || len(got.AssetIDs()) != 2 {
+		t.Fatalf("unexpected value %s", got)
+	}
+	if q := got.Quantity(mustAsset(t, nutcoin)); q.Int64() != 125 {
+		t.Fatalf("expected 125 got %s", q)
+	}
+}
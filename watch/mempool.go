@@ -0,0 +1,314 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/internal/apierr"
+)
+
+// RemovalReason is the reason a transaction left the mempool.
+type RemovalReason int
+
+const (
+	// RemovedConfirmed is the reason of transactions included in a block.
+	RemovedConfirmed RemovalReason = iota
+	// RemovedDropped is the reason of transactions evicted from the
+	// mempool without being included in a block, such as when they
+	// conflict with another transaction or expire, and still not found
+	// once MempoolOptions.DropGracePeriod has passed.
+	RemovedDropped
+)
+
+func (r RemovalReason) String() string {
+	switch r {
+	case RemovedConfirmed:
+		return "confirmed"
+	case RemovedDropped:
+		return "dropped"
+	}
+	return fmt.Sprintf("RemovalReason(%d)", int(r))
+}
+
+// MempoolHandler handles the changes of the mempool. Returning an error
+// stops the MempoolMonitor.
+type MempoolHandler interface {
+	// Added is called with each transaction entering the mempool, and with
+	// the transactions already in it when the monitor starts.
+	Added(ctx context.Context, tx blockfrost.MempoolTransactionContent) error
+
+	// Removed is called with each transaction leaving the mempool.
+	Removed(ctx context.Context, hash string, reason RemovalReason) error
+}
+
+// MempoolOptions configures a MempoolMonitor.
+type MempoolOptions struct {
+	// Addresses restricts the monitor to the transactions of these
+	// addresses. Default: the whole mempool.
+	Addresses []string
+
+	// Interval between polls. Default: 20s.
+	Interval time.Duration
+
+	// RequestsPerSecond limits the requests for the details of added and
+	// removed transactions. Default: 10.
+	RequestsPerSecond float64
+
+	// Concurrency is the maximum number of concurrent requests for the
+	// details of transactions. Default: 10.
+	Concurrency int
+
+	// DropGracePeriod is how long transactions that left the mempool but
+	// are not found on chain are looked up again, as the chain may lag
+	// behind the mempool, before being reported as dropped. Default: 1m.
+	DropGracePeriod time.Duration
+
+	// OnError is called with the errors of requests. Transactions whose
+	// details could not be fetched are looked up again at the next poll.
+	OnError func(error)
+}
+
+// MempoolMonitor polls the mempool, and reports the transactions added to
+// and removed from it.
+type MempoolMonitor struct {
+	client blockfrost.APIClient
+	opts   MempoolOptions
+
+	// known are the transactions reported as added.
+	known map[string]bool
+	// gone are the times known transactions were first found missing from
+	// the mempool.
+	gone map[string]time.Time
+}
+
+// NewMempoolMonitor returns a MempoolMonitor polling client.
+func NewMempoolMonitor(client blockfrost.APIClient, opts MempoolOptions) *MempoolMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = 20 * time.Second
+	}
+	if opts.RequestsPerSecond <= 0 {
+		opts.RequestsPerSecond = 10
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 10
+	}
+	if opts.DropGracePeriod <= 0 {
+		opts.DropGracePeriod = time.Minute
+	}
+	return &MempoolMonitor{client: client, opts: opts, known: map[string]bool{}, gone: map[string]time.Time{}}
+}
+
+// Run polls the mempool every Interval until ctx is done or h returns an
+// error, which Run returns.
+func (m *MempoolMonitor) Run(ctx context.Context, h MempoolHandler) error {
+	for {
+		err := m.Poll(ctx, h)
+		var fatal *fatalError
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.As(err, &fatal):
+			return fatal.err
+		case err != nil && m.opts.OnError != nil:
+			m.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.opts.Interval):
+		}
+	}
+}
+
+// Poll compares the mempool with the previous poll, and calls h with the
+// transactions added and removed. It must not be called concurrently with
+// Run.
+func (m *MempoolMonitor) Poll(ctx context.Context, h MempoolHandler) error {
+	snapshot, err := m.snapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	var added, removed []string
+	for _, hash := range snapshot {
+		if !m.known[hash] {
+			added = append(added, hash)
+		}
+	}
+	in := make(map[string]bool, len(snapshot))
+	for _, hash := range snapshot {
+		in[hash] = true
+	}
+	now := time.Now()
+	for hash := range m.known {
+		switch {
+		case !in[hash]:
+			removed = append(removed, hash)
+			if _, ok := m.gone[hash]; !ok {
+				m.gone[hash] = now
+			}
+		default:
+			// The transaction is back in the mempool.
+			delete(m.gone, hash)
+		}
+	}
+
+	lookups := make([]lookup, 0, len(added)+len(removed))
+	for _, hash := range removed {
+		lookups = append(lookups, lookup{hash: hash, removed: true, gone: m.gone[hash]})
+	}
+	for _, hash := range added {
+		lookups = append(lookups, lookup{hash: hash})
+	}
+	errs := m.lookup(ctx, lookups)
+
+	for _, l := range lookups {
+		switch {
+		case !l.done:
+			continue
+		case l.removed:
+			if err := h.Removed(ctx, l.hash, l.reason); err != nil {
+				return &fatalError{err}
+			}
+			delete(m.known, l.hash)
+			delete(m.gone, l.hash)
+		case l.tx != nil:
+			if err := h.Added(ctx, *l.tx); err != nil {
+				return &fatalError{err}
+			}
+			m.known[l.hash] = true
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// snapshot returns the hashes of the transactions in the mempool, or of
+// the monitored addresses.
+func (m *MempoolMonitor) snapshot(ctx context.Context) ([]string, error) {
+	var (
+		hashes []string
+		seen   = map[string]bool{}
+		first  error
+	)
+	collect := func(ch <-chan blockfrost.MempoolResult, what string) {
+		// The channel is drained even after an error, to let its
+		// goroutines return.
+		for res := range ch {
+			if res.Err != nil && !apierr.IsNotFound(res.Err) && first == nil {
+				first = fmt.Errorf("watch: mempool%s: %w", what, res.Err)
+			}
+			for _, tx := range res.Res {
+				if !seen[tx.TxHash] {
+					seen[tx.TxHash] = true
+					hashes = append(hashes, tx.TxHash)
+				}
+			}
+		}
+	}
+	if len(m.opts.Addresses) == 0 {
+		collect(m.client.MempoolAll(ctx), "")
+	}
+	for _, addr := range m.opts.Addresses {
+		collect(m.client.MempoolByAddressAll(ctx, addr), " of "+addr)
+	}
+	if first != nil {
+		// A partial snapshot would report missing transactions as removed.
+		return nil, first
+	}
+	return hashes, nil
+}
+
+// lookup is the lookup of the details of a transaction added to or
+// removed from the mempool.
+type lookup struct {
+	hash    string
+	removed bool
+	gone    time.Time
+
+	done   bool
+	tx     *blockfrost.MempoolTransactionContent
+	reason RemovalReason
+}
+
+// lookup fetches the details of the transactions, at RequestsPerSecond.
+// Transactions added and removed between polls are never reported. Once a
+// request is rate limited, the remaining lookups wait for the next poll.
+func (m *MempoolMonitor) lookup(ctx context.Context, lookups []lookup) []error {
+	if len(lookups) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tick := time.NewTicker(time.Duration(float64(time.Second) / m.opts.RequestsPerSecond))
+	defer tick.Stop()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	jobs := make(chan *lookup)
+	for i := 0; i < m.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for l := range jobs {
+				select {
+				case <-tick.C:
+				case <-ctx.Done():
+				}
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := m.fetch(ctx, l); err != nil {
+					if apierr.IsRateLimited(err) {
+						cancel()
+					}
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range lookups {
+		jobs <- &lookups[i]
+	}
+	close(jobs)
+	wg.Wait()
+	return errs
+}
+
+func (m *MempoolMonitor) fetch(ctx context.Context, l *lookup) error {
+	if l.removed {
+		// Transactions leaving the mempool were either included in a
+		// block, or dropped.
+		_, err := m.client.Transaction(ctx, l.hash)
+		switch {
+		case err == nil:
+			l.reason, l.done = RemovedConfirmed, true
+		case apierr.IsNotFound(err) && time.Since(l.gone) < m.opts.DropGracePeriod:
+			// The transaction may not be indexed yet, and is looked up
+			// again at the next poll.
+		case apierr.IsNotFound(err):
+			l.reason, l.done = RemovedDropped, true
+		default:
+			return fmt.Errorf("watch: transaction %s: %w", l.hash, err)
+		}
+		return nil
+	}
+	tx, err := m.client.MempoolTx(ctx, l.hash)
+	switch {
+	case err == nil:
+		l.tx, l.done = &tx, true
+	case apierr.IsNotFound(err):
+		l.done = true
+	default:
+		return fmt.Errorf("watch: mempool transaction %s: %w", l.hash, err)
+	}
+	return nil
+}
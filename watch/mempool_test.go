@@ -0,0 +1,211 @@
+package watch_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/watch"
+)
+
+var errRateLimited = &blockfrost.APIError{Response: blockfrost.OverusageLimit{StatusCode: 429, Error: "Project Over Limit"}}
+
+// mempool is a fake client serving a mempool.
+type mempool struct {
+	blockfrost.APIClient
+
+	mu        sync.Mutex
+	pending   map[string]string // hash to address
+	confirmed map[string]bool
+	limited   map[string]bool
+	calls     map[string]int
+}
+
+func newMempool() *mempool {
+	return &mempool{pending: map[string]string{}, confirmed: map[string]bool{}, limited: map[string]bool{}, calls: map[string]int{}}
+}
+
+func (m *mempool) add(hash, addr string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending[hash] = addr
+}
+
+func (m *mempool) remove(hash string, confirmed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pending, hash)
+	m.confirmed[hash] = confirmed
+}
+
+func (m *mempool) result(addr string) <-chan blockfrost.MempoolResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var res []blockfrost.Mempool
+	for hash, a := range m.pending {
+		if addr == "" || a == addr {
+			res = append(res, blockfrost.Mempool{TxHash: hash})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].TxHash < res[j].TxHash })
+	ch := make(chan blockfrost.MempoolResult, 1)
+	ch <- blockfrost.MempoolResult{Res: res}
+	close(ch)
+	return ch
+}
+
+func (m *mempool) MempoolAll(ctx context.Context) <-chan blockfrost.MempoolResult {
+	return m.result("")
+}
+
+func (m *mempool) MempoolByAddressAll(ctx context.Context, addr string) <-chan blockfrost.MempoolResult {
+	return m.result(addr)
+}
+
+func (m *mempool) MempoolTx(ctx context.Context, hash string) (blockfrost.MempoolTransactionContent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls["MempoolTx"]++
+	if m.limited[hash] {
+		delete(m.limited, hash)
+		return blockfrost.MempoolTransactionContent{}, errRateLimited
+	}
+	addr, ok := m.pending[hash]
+	if !ok {
+		return blockfrost.MempoolTransactionContent{}, errNotFound
+	}
+	return blockfrost.MempoolTransactionContent{
+		Tx:      blockfrost.MempoolTransaction{Hash: hash},
+		Outputs: []blockfrost.MempoolTransactionOutput{{Address: addr}},
+	}, nil
+}
+
+func (m *mempool) Transaction(ctx context.Context, hash string) (blockfrost.TransactionContent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.confirmed[hash] {
+		return blockfrost.TransactionContent{}, errNotFound
+	}
+	return blockfrost.TransactionContent{Hash: hash}, nil
+}
+
+type changes struct {
+	added   []string
+	removed map[string]watch.RemovalReason
+}
+
+func (c *changes) Added(ctx context.Context, tx blockfrost.MempoolTransactionContent) error {
+	c.added = append(c.added, tx.Tx.Hash)
+	return nil
+}
+
+func (c *changes) Removed(ctx context.Context, hash string, reason watch.RemovalReason) error {
+	if c.removed == nil {
+		c.removed = map[string]watch.RemovalReason{}
+	}
+	c.removed[hash] = reason
+	return nil
+}
+
+func pollMempool(t *testing.T, m *watch.MempoolMonitor) *changes {
+	t.Helper()
+	c := &changes{}
+	if err := m.Poll(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(c.added)
+	return c
+}
+
+func TestMempoolMonitor(t *testing.T) {
+	mp := newMempool()
+	mp.add("a", "alice")
+	mp.add("b", "bob")
+	m := watch.NewMempoolMonitor(mp, watch.MempoolOptions{RequestsPerSecond: 1000, DropGracePeriod: time.Nanosecond})
+
+	// Transactions already in the mempool are added first.
+	if c := pollMempool(t, m); !reflect.DeepEqual(c.added, []string{"a", "b"}) || c.removed != nil {
+		t.Fatalf("got %+v", c)
+	}
+	if c := pollMempool(t, m); c.added != nil || c.removed != nil {
+		t.Fatalf("got %+v", c)
+	}
+
+	mp.remove("a", true)
+	mp.remove("b", false)
+	mp.add("c", "carol")
+	c := pollMempool(t, m)
+	want := map[string]watch.RemovalReason{"a": watch.RemovedConfirmed, "b": watch.RemovedDropped}
+	if !reflect.DeepEqual(c.added, []string{"c"}) || !reflect.DeepEqual(c.removed, want) {
+		t.Fatalf("got %+v", c)
+	}
+	if got := watch.RemovedDropped.String(); got != "dropped" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestMempoolMonitorDropGracePeriod(t *testing.T) {
+	mp := newMempool()
+	mp.add("a", "alice")
+	mp.add("b", "bob")
+	m := watch.NewMempoolMonitor(mp, watch.MempoolOptions{RequestsPerSecond: 1000, DropGracePeriod: 50 * time.Millisecond})
+	pollMempool(t, m)
+
+	// Transactions not found on chain yet are not reported as dropped
+	// within the grace period.
+	mp.remove("a", false)
+	mp.remove("b", false)
+	if c := pollMempool(t, m); c.removed != nil {
+		t.Fatalf("got %+v", c)
+	}
+	mp.remove("a", true)
+	if c := pollMempool(t, m); !reflect.DeepEqual(c.removed, map[string]watch.RemovalReason{"a": watch.RemovedConfirmed}) {
+		t.Fatalf("got %+v", c)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if c := pollMempool(t, m); !reflect.DeepEqual(c.removed, map[string]watch.RemovalReason{"b": watch.RemovedDropped}) {
+		t.Fatalf("got %+v", c)
+	}
+	if c := pollMempool(t, m); c.removed != nil {
+		t.Fatalf("got %+v", c)
+	}
+}
+
+func TestMempoolMonitorAddresses(t *testing.T) {
+	mp := newMempool()
+	mp.add("a", "alice")
+	mp.add("b", "bob")
+	mp.add("c", "carol")
+	m := watch.NewMempoolMonitor(mp, watch.MempoolOptions{Addresses: []string{"alice", "carol"}, RequestsPerSecond: 1000})
+	if c := pollMempool(t, m); !reflect.DeepEqual(c.added, []string{"a", "c"}) {
+		t.Fatalf("got %+v", c)
+	}
+}
+
+func TestMempoolMonitorRateLimited(t *testing.T) {
+	mp := newMempool()
+	for _, hash := range []string{"a", "b", "c", "d"} {
+		mp.add(hash, "alice")
+	}
+	mp.limited["a"] = true
+	m := watch.NewMempoolMonitor(mp, watch.MempoolOptions{RequestsPerSecond: 1000, Concurrency: 1})
+
+	c := &changes{}
+	if err := m.Poll(context.Background(), c); !errors.Is(err, errRateLimited) {
+		t.Fatalf("got %v, want rate limit error", err)
+	}
+	if len(c.added) != 0 || mp.calls["MempoolTx"] != 1 {
+		t.Fatalf("got %+v after %d requests", c, mp.calls["MempoolTx"])
+	}
+
+	// The transactions are looked up again at the next poll.
+	if c := pollMempool(t, m); !reflect.DeepEqual(c.added, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("got %+v", c)
+	}
+}
@@ -0,0 +1,118 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/blockfrost/blockfrost-go/internal/atomicfile"
+)
+
+// Checkpoint is the progress of a subscription.
+type Checkpoint struct {
+	// Height is the block height the next poll starts from.
+	Height int `json:"height"`
+
+	// Seen are the transactions of the block at Height already delivered.
+	Seen []string `json:"seen,omitempty"`
+}
+
+// Store saves the checkpoints of subscriptions, so that they resume after
+// a restart.
+type Store interface {
+	// Load returns the checkpoint of the subscription id, and whether
+	// one was saved.
+	Load(ctx context.Context, id string) (Checkpoint, bool, error)
+
+	// Save replaces the checkpoint of the subscription id.
+	Save(ctx context.Context, id string, cp Checkpoint) error
+}
+
+// MemoryStore is a Store keeping checkpoints in memory, such as for a
+// Watcher restarted within a process.
+type MemoryStore struct {
+	mu  sync.Mutex
+	cps map[string]Checkpoint
+}
+
+// Load returns the checkpoint of id.
+func (s *MemoryStore) Load(_ context.Context, id string) (Checkpoint, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, ok := s.cps[id]
+	cp.Seen = append([]string(nil), cp.Seen...)
+	return cp, ok, nil
+}
+
+// Save replaces the checkpoint of id.
+func (s *MemoryStore) Save(_ context.Context, id string, cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cps == nil {
+		s.cps = map[string]Checkpoint{}
+	}
+	cp.Seen = append([]string(nil), cp.Seen...)
+	s.cps[id] = cp
+	return nil
+}
+
+// FileStore is a Store keeping the checkpoints of all subscriptions in a
+// JSON file.
+type FileStore struct {
+	path string
+
+	mu  sync.Mutex
+	cps map[string]Checkpoint
+}
+
+// NewFileStore returns a FileStore keeping checkpoints in the file at path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// read reads the file once. A missing file holds no checkpoint.
+func (s *FileStore) read() error {
+	if s.cps != nil {
+		return nil
+	}
+	cps := map[string]Checkpoint{}
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &cps); err != nil {
+			return err
+		}
+	}
+	s.cps = cps
+	return nil
+}
+
+// Load returns the checkpoint of id.
+func (s *FileStore) Load(_ context.Context, id string) (Checkpoint, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.read(); err != nil {
+		return Checkpoint{}, false, err
+	}
+	cp, ok := s.cps[id]
+	return cp, ok, nil
+}
+
+// Save replaces the checkpoint of id, and rewrites the file.
+func (s *FileStore) Save(_ context.Context, id string, cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.read(); err != nil {
+		return err
+	}
+	s.cps[id] = cp
+	data, err := json.Marshal(s.cps)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(s.path, data)
+}
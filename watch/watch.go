@@ -0,0 +1,501 @@
+// Package watch notifies subscribers of the transactions of addresses,
+// assets and stake accounts, by polling.
+//
+// A Watcher polls each watched target once per interval, however many
+// subscriptions share it, and calls the callbacks of the subscriptions with
+// the transactions they have not seen yet. Subscriptions resume from their
+// checkpoint after a restart. This is an alternative to webhooks for
+// services Blockfrost cannot reach:
+//
+//	w := watch.New(client, watch.Options{Store: watch.NewFileStore("watch.json")})
+//	w.Subscribe("treasury", watch.Address(treasury), func(ctx context.Context, ev watch.Event) error {
+//		log.Println("treasury transaction", ev.TxHash)
+//		return nil
+//	})
+//	err := w.Run(ctx)
+//
+// A MempoolMonitor reports the transactions entering and leaving the
+// mempool, such as to show deposits as pending before they are included in
+// a block.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/internal/apierr"
+)
+
+// ErrDuplicateSubscription is returned when subscribing with the ID of an
+// existing subscription.
+var ErrDuplicateSubscription = errors.New("watch: duplicate subscription")
+
+// Kind is the kind of a watched target.
+type Kind int
+
+const (
+	// KindAddress watches the transactions receiving to or spending from
+	// an address.
+	KindAddress Kind = iota
+	// KindAsset watches the transactions moving an asset.
+	KindAsset
+	// KindStake watches the delegations of a stake account.
+	KindStake
+)
+
+var kindNames = [...]string{
+	KindAddress: "address",
+	KindAsset:   "asset",
+	KindStake:   "stake",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+// Target is what a subscription watches.
+type Target struct {
+	Kind Kind
+
+	// Value is the Bech32 address, the asset unit (policy ID and hex name)
+	// or the Bech32 stake address.
+	Value string
+}
+
+// Address returns the target watching addr.
+func Address(addr string) Target {
+	return Target{Kind: KindAddress, Value: addr}
+}
+
+// Asset returns the target watching the asset unit.
+func Asset(unit string) Target {
+	return Target{Kind: KindAsset, Value: unit}
+}
+
+// Stake returns the target watching the delegations of stakeAddr.
+func Stake(stakeAddr string) Target {
+	return Target{Kind: KindStake, Value: stakeAddr}
+}
+
+func (t Target) String() string {
+	return t.Kind.String() + ":" + t.Value
+}
+
+// Event is a transaction of a watched target.
+type Event struct {
+	// Subscription is the ID of the subscription notified.
+	Subscription string
+	Target       Target
+
+	TxHash      string
+	TxIndex     int
+	BlockHeight int
+	BlockTime   int
+
+	// Received and Spent report whether an address event pays to, or
+	// spends from, the address. They are only set when the Watcher
+	// resolves directions.
+	Received bool
+	Spent    bool
+
+	// PoolID and ActiveEpoch are the pool delegated to by a stake event,
+	// from the epoch ActiveEpoch.
+	PoolID      string
+	ActiveEpoch int32
+}
+
+// Callback is called with the events of a subscription, in chain order.
+// Returning an error stops the Watcher.
+type Callback func(ctx context.Context, ev Event) error
+
+// Options configures a Watcher.
+type Options struct {
+	// Interval between polls. Default: 20s.
+	Interval time.Duration
+
+	// FromHeight is the block height new subscriptions start from, when
+	// Store holds no checkpoint for them. Default: the blocks after the
+	// tip.
+	FromHeight int
+
+	// Directions resolves the Received and Spent fields of address events,
+	// at the cost of one request per transaction.
+	Directions bool
+
+	// Concurrency is the maximum number of concurrent requests. Default: 10.
+	Concurrency int
+
+	// Store saves the checkpoint of each subscription. Default: none.
+	Store Store
+
+	// OnError is called with the errors of requests. Targets whose
+	// requests failed are polled again at the next interval.
+	OnError func(error)
+}
+
+// Watcher polls the targets of its subscriptions.
+type Watcher struct {
+	client blockfrost.APIClient
+	opts   Options
+
+	mu   sync.Mutex
+	subs map[string]*subscription
+}
+
+type subscription struct {
+	id     string
+	target Target
+	fn     Callback
+
+	// cp is the checkpoint, loaded when the subscription is first polled.
+	cp     Checkpoint
+	loaded bool
+}
+
+// New returns a Watcher polling client.
+func New(client blockfrost.APIClient, opts Options) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = 20 * time.Second
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 10
+	}
+	return &Watcher{client: client, opts: opts, subs: map[string]*subscription{}}
+}
+
+// Subscribe calls fn with the events of target. The id identifies the
+// checkpoint of the subscription, and must be stable across restarts.
+func (w *Watcher) Subscribe(id string, target Target, fn Callback) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.subs[id]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateSubscription, id)
+	}
+	w.subs[id] = &subscription{id: id, target: target, fn: fn}
+	return nil
+}
+
+// Unsubscribe removes the subscription id. Its checkpoint is kept.
+func (w *Watcher) Unsubscribe(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.subs, id)
+}
+
+// Run polls the targets every Interval until ctx is done, or a callback
+// or the store return an error, which Run returns. Events are delivered
+// at least once: after a restart, the events delivered since the last
+// checkpoint are delivered again.
+func (w *Watcher) Run(ctx context.Context) error {
+	for {
+		err := w.Poll(ctx)
+		var fatal *fatalError
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.As(err, &fatal):
+			return fatal.err
+		case err != nil && w.opts.OnError != nil:
+			w.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(w.opts.Interval):
+		}
+	}
+}
+
+// fatalError is an error of a callback or of the store, which stops the
+// watcher.
+type fatalError struct {
+	err error
+}
+
+func (e *fatalError) Error() string { return e.err.Error() }
+
+func (e *fatalError) Unwrap() error { return e.err }
+
+// Poll polls the targets once, up to the latest block, and calls the
+// callbacks of their subscriptions. It must not be called concurrently
+// with Run.
+func (w *Watcher) Poll(ctx context.Context) error {
+	tip, err := w.client.BlockLatest(ctx)
+	if err != nil {
+		return fmt.Errorf("watch: latest block: %w", err)
+	}
+
+	w.mu.Lock()
+	subs := make([]*subscription, 0, len(w.subs))
+	for _, s := range w.subs {
+		subs = append(subs, s)
+	}
+	w.mu.Unlock()
+	sort.Slice(subs, func(i, j int) bool { return subs[i].id < subs[j].id })
+
+	// Subscriptions sharing a target share its requests, from the oldest
+	// checkpoint.
+	from := map[Target]int{}
+	for _, s := range subs {
+		if !s.loaded {
+			if err := w.load(ctx, s, tip.Height); err != nil {
+				return err
+			}
+		}
+		if h, ok := from[s.target]; !ok || s.cp.Height < h {
+			from[s.target] = s.cp.Height
+		}
+	}
+
+	events, errs := w.fetchAll(ctx, from, tip.Height)
+	for _, s := range subs {
+		evs, ok := events[s.target]
+		if !ok {
+			continue
+		}
+		if err := w.deliver(ctx, s, evs, tip.Height); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Watcher) load(ctx context.Context, s *subscription, tip int) error {
+	if w.opts.Store != nil {
+		cp, ok, err := w.opts.Store.Load(ctx, s.id)
+		if err != nil {
+			return &fatalError{fmt.Errorf("watch: load checkpoint %s: %w", s.id, err)}
+		}
+		if ok {
+			s.cp, s.loaded = cp, true
+			return nil
+		}
+	}
+	s.cp = Checkpoint{Height: tip + 1}
+	if w.opts.FromHeight > 0 {
+		s.cp.Height = w.opts.FromHeight
+	}
+	s.loaded = true
+	return nil
+}
+
+// deliver calls the callback of s with the events it has not seen, and
+// advances its checkpoint to the tip.
+func (w *Watcher) deliver(ctx context.Context, s *subscription, evs []Event, tip int) error {
+	if tip < s.cp.Height {
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, h := range s.cp.Seen {
+		seen[h] = true
+	}
+	next := Checkpoint{Height: tip}
+	if s.cp.Height == tip {
+		next.Seen = append(next.Seen, s.cp.Seen...)
+	}
+	for _, ev := range evs {
+		if ev.BlockHeight < s.cp.Height || ev.BlockHeight == s.cp.Height && seen[ev.TxHash] {
+			continue
+		}
+		ev.Subscription = s.id
+		if err := s.fn(ctx, ev); err != nil {
+			return &fatalError{err}
+		}
+		if ev.BlockHeight == tip {
+			next.Seen = append(next.Seen, ev.TxHash)
+		}
+	}
+	s.cp = next
+	if w.opts.Store == nil {
+		return nil
+	}
+	if err := w.opts.Store.Save(ctx, s.id, next); err != nil {
+		return &fatalError{fmt.Errorf("watch: save checkpoint %s: %w", s.id, err)}
+	}
+	return nil
+}
+
+// fetchAll fetches the events of the targets, from their height up to tip,
+// with bounded concurrency. Targets whose requests failed are left out.
+func (w *Watcher) fetchAll(ctx context.Context, from map[Target]int, tip int) (map[Target][]Event, []error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		errs   []error
+		events = map[Target][]Event{}
+	)
+	sem := make(chan struct{}, w.opts.Concurrency)
+	for t, h := range from {
+		if h > tip {
+			continue
+		}
+		wg.Add(1)
+		go func(t Target, h int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			evs, err := w.fetch(ctx, t, h, tip)
+			<-sem
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("watch: %s: %w", t, err))
+				return
+			}
+			events[t] = evs
+		}(t, h)
+	}
+	wg.Wait()
+	if w.opts.Directions {
+		if err := w.resolve(ctx, events, sem); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return events, errs
+}
+
+const pageSize = 100
+
+// fetch fetches the events of t in blocks from..tip, in chain order.
+func (w *Watcher) fetch(ctx context.Context, t Target, from, tip int) ([]Event, error) {
+	var evs []Event
+	switch t.Kind {
+	case KindAddress:
+		// Address transactions can be queried by block range.
+		query := blockfrost.APIQueryParams{Count: pageSize, Order: "asc", From: strconv.Itoa(from), To: strconv.Itoa(tip)}
+		for query.Page = 1; ; query.Page++ {
+			txs, err := w.client.AddressTransactions(ctx, t.Value, query)
+			if apierr.IsNotFound(err) {
+				return evs, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, tx := range txs {
+				evs = append(evs, Event{Target: t, TxHash: tx.TxHash, TxIndex: tx.TxIndex, BlockHeight: tx.BlockHeight, BlockTime: tx.BlockTime})
+			}
+			if len(txs) < pageSize {
+				return evs, nil
+			}
+		}
+	case KindAsset, KindStake:
+		// Newest first, until reaching the blocks already polled.
+		query := blockfrost.APIQueryParams{Count: pageSize, Order: "desc"}
+		for query.Page = 1; ; query.Page++ {
+			page, err := w.page(ctx, t, query)
+			if apierr.IsNotFound(err) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			done := len(page) < pageSize
+			for _, ev := range page {
+				if ev.BlockHeight < from {
+					done = true
+					break
+				}
+				if ev.BlockHeight <= tip {
+					evs = append(evs, ev)
+				}
+			}
+			if done {
+				break
+			}
+		}
+		for i, j := 0, len(evs)-1; i < j; i, j = i+1, j-1 {
+			evs[i], evs[j] = evs[j], evs[i]
+		}
+		return evs, nil
+	}
+	return nil, fmt.Errorf("unknown target kind %v", t.Kind)
+}
+
+func (w *Watcher) page(ctx context.Context, t Target, query blockfrost.APIQueryParams) ([]Event, error) {
+	var evs []Event
+	if t.Kind == KindAsset {
+		txs, err := w.client.AssetTransactions(ctx, t.Value, query)
+		for _, tx := range txs {
+			evs = append(evs, Event{Target: t, TxHash: tx.TxHash, TxIndex: tx.TxIndex, BlockHeight: tx.BlockHeight, BlockTime: tx.BlockTime})
+		}
+		return evs, err
+	}
+	hist, err := w.client.AccountDelegationHistory(ctx, t.Value, query)
+	for _, d := range hist {
+		evs = append(evs, Event{Target: t, TxHash: d.TXHash, BlockHeight: d.BlockHeight, BlockTime: d.BlockTime, PoolID: d.PoolID, ActiveEpoch: d.ActiveEpoch})
+	}
+	return evs, err
+}
+
+// resolve sets the directions of address events, fetching the UTXOs of
+// each transaction once. Targets with a transaction whose UTXOs could not
+// be fetched are left out, to be polled again.
+func (w *Watcher) resolve(ctx context.Context, events map[Target][]Event, sem chan struct{}) error {
+	utxos := map[string]*blockfrost.TransactionUTXOs{}
+	var hashes []string
+	for t, evs := range events {
+		if t.Kind != KindAddress {
+			continue
+		}
+		for _, ev := range evs {
+			if _, ok := utxos[ev.TxHash]; !ok {
+				utxos[ev.TxHash] = nil
+				hashes = append(hashes, ev.TxHash)
+			}
+		}
+	}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, hash := range hashes {
+		wg.Add(1)
+		go func(hash string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			u, err := w.client.TransactionUTXOs(ctx, hash)
+			<-sem
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("watch: utxos of %s: %w", hash, err))
+				return
+			}
+			utxos[hash] = &u
+		}(hash)
+	}
+	wg.Wait()
+
+	for t, evs := range events {
+		if t.Kind != KindAddress {
+			continue
+		}
+		for i := range evs {
+			u := utxos[evs[i].TxHash]
+			if u == nil {
+				delete(events, t)
+				break
+			}
+			for _, in := range u.Inputs {
+				if in.Address == t.Value && !in.Collateral && (in.Reference == nil || !*in.Reference) {
+					evs[i].Spent = true
+				}
+			}
+			for _, out := range u.Outputs {
+				if out.Address == t.Value && !out.Collateral {
+					evs[i].Received = true
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,358 @@
+package watch_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/blockfrost/blockfrost-go/watch"
+)
+
+var errNotFound = &blockfrost.APIError{Response: blockfrost.NotFound{StatusCode: 404, Error: "Not Found"}}
+
+type tx struct {
+	hash   string
+	height int
+	index  int
+	from   string
+	to     string
+	pool   string
+}
+
+// chain is a fake client serving the transactions of addresses, assets and
+// stake accounts.
+type chain struct {
+	blockfrost.APIClient
+
+	mu    sync.Mutex
+	tip   int
+	txs   []tx
+	calls map[string]int
+}
+
+func newChain(tip int) *chain {
+	return &chain{tip: tip, calls: map[string]int{}}
+}
+
+// add adds a transaction at the tip, moving value from one target to
+// another.
+func (c *chain) add(hash, from, to string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	index := 0
+	for _, t := range c.txs {
+		if t.height == c.tip {
+			index++
+		}
+	}
+	c.txs = append(c.txs, tx{hash: hash, height: c.tip, index: index, from: from, to: to})
+}
+
+func (c *chain) delegate(hash, stake, pool string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.txs = append(c.txs, tx{hash: hash, height: c.tip, to: stake, pool: pool})
+}
+
+func (c *chain) next() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tip++
+}
+
+func (c *chain) callsOf(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
+
+// involving returns the transactions involving target, in query order.
+func (c *chain) involving(method, target string, query blockfrost.APIQueryParams) ([]tx, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[method]++
+	from, to := 0, c.tip
+	if query.From != "" {
+		from, _ = strconv.Atoi(query.From)
+	}
+	if query.To != "" {
+		to, _ = strconv.Atoi(query.To)
+	}
+	var out []tx
+	for _, t := range c.txs {
+		if (t.from == target || t.to == target) && t.height >= from && t.height <= to {
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 && from == 0 {
+		return nil, errNotFound
+	}
+	if query.Order == "desc" {
+		sort.SliceStable(out, func(i, j int) bool { return out[i].height > out[j].height })
+	}
+	start := (query.Page - 1) * query.Count
+	if start > len(out) {
+		start = len(out)
+	}
+	end := start + query.Count
+	if end > len(out) {
+		end = len(out)
+	}
+	return out[start:end], nil
+}
+
+func (c *chain) BlockLatest(ctx context.Context) (blockfrost.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return blockfrost.Block{Height: c.tip}, nil
+}
+
+func (c *chain) AddressTransactions(ctx context.Context, address string, query blockfrost.APIQueryParams) ([]blockfrost.AddressTransactions, error) {
+	txs, err := c.involving("AddressTransactions", address, query)
+	var out []blockfrost.AddressTransactions
+	for _, t := range txs {
+		out = append(out, blockfrost.AddressTransactions{TxHash: t.hash, TxIndex: t.index, BlockHeight: t.height})
+	}
+	return out, err
+}
+
+func (c *chain) AssetTransactions(ctx context.Context, asset string, query blockfrost.APIQueryParams) ([]blockfrost.AssetTransaction, error) {
+	txs, err := c.involving("AssetTransactions", asset, query)
+	var out []blockfrost.AssetTransaction
+	for _, t := range txs {
+		out = append(out, blockfrost.AssetTransaction{TxHash: t.hash, TxIndex: t.index, BlockHeight: t.height})
+	}
+	return out, err
+}
+
+func (c *chain) AccountDelegationHistory(ctx context.Context, stake string, query blockfrost.APIQueryParams) ([]blockfrost.AccountDelegationHistory, error) {
+	txs, err := c.involving("AccountDelegationHistory", stake, query)
+	var out []blockfrost.AccountDelegationHistory
+	for _, t := range txs {
+		out = append(out, blockfrost.AccountDelegationHistory{TXHash: t.hash, BlockHeight: t.height, PoolID: t.pool})
+	}
+	return out, err
+}
+
+func (c *chain) TransactionUTXOs(ctx context.Context, hash string) (blockfrost.TransactionUTXOs, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls["TransactionUTXOs"]++
+	for _, t := range c.txs {
+		if t.hash == hash {
+			return blockfrost.TransactionUTXOs{
+				Hash:    hash,
+				Inputs:  []blockfrost.TransactionInput{{Address: t.from}},
+				Outputs: []blockfrost.TransactionOutput{{Address: t.to}},
+			}, nil
+		}
+	}
+	return blockfrost.TransactionUTXOs{}, errNotFound
+}
+
+// recorder records the events of subscriptions.
+type recorder struct {
+	mu     sync.Mutex
+	events map[string][]string
+	err    error
+}
+
+func (r *recorder) callback(ctx context.Context, ev watch.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.events == nil {
+		r.events = map[string][]string{}
+	}
+	s := fmt.Sprintf("%s@%d", ev.TxHash, ev.BlockHeight)
+	switch {
+	case ev.PoolID != "":
+		s += " " + ev.PoolID
+	case ev.Received && ev.Spent:
+		s += " self"
+	case ev.Received:
+		s += " in"
+	case ev.Spent:
+		s += " out"
+	}
+	r.events[ev.Subscription] = append(r.events[ev.Subscription], s)
+	return r.err
+}
+
+func (r *recorder) take() map[string][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.events
+	r.events = nil
+	return events
+}
+
+func poll(t *testing.T, w *watch.Watcher) {
+	t.Helper()
+	if err := w.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expect(t *testing.T, r *recorder, want map[string][]string) {
+	t.Helper()
+	if got := r.take(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	c := newChain(10)
+	c.add("a", "alice", "bob")
+	r := &recorder{}
+	w := watch.New(c, watch.Options{})
+	for id, target := range map[string]watch.Target{
+		"alice":  watch.Address("alice"),
+		"bob":    watch.Address("bob"),
+		"bob2":   watch.Address("bob"),
+		"coin":   watch.Asset("coin"),
+		"staker": watch.Stake("staker"),
+	} {
+		if err := w.Subscribe(id, target, r.callback); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Subscribe("bob", watch.Address("carol"), r.callback); !errors.Is(err, watch.ErrDuplicateSubscription) {
+		t.Fatalf("got %v, want ErrDuplicateSubscription", err)
+	}
+
+	// Subscriptions start after the tip.
+	poll(t, w)
+	expect(t, r, nil)
+
+	c.next()
+	c.add("b", "alice", "bob")
+	c.add("c", "mint", "coin")
+	poll(t, w)
+	expect(t, r, map[string][]string{
+		"alice": {"b@11"},
+		"bob":   {"b@11"},
+		"bob2":  {"b@11"},
+		"coin":  {"c@11"},
+	})
+	// Subscriptions to the same target share requests.
+	if got := c.callsOf("AddressTransactions"); got != 2 {
+		t.Fatalf("got %d address requests, want 2", got)
+	}
+
+	// Transactions of the tip are not delivered again.
+	c.add("d", "bob", "carol")
+	c.delegate("e", "staker", "pool1")
+	poll(t, w)
+	expect(t, r, map[string][]string{
+		"bob":    {"d@11"},
+		"bob2":   {"d@11"},
+		"staker": {"e@11 pool1"},
+	})
+
+	w.Unsubscribe("bob2")
+	c.next()
+	c.add("f", "carol", "bob")
+	poll(t, w)
+	poll(t, w)
+	expect(t, r, map[string][]string{"bob": {"f@12"}})
+}
+
+func TestWatcherPaging(t *testing.T) {
+	c := newChain(1)
+	var want []string
+	for i := 0; i < 250; i++ {
+		c.next()
+		hash := strconv.Itoa(i)
+		c.add(hash, "mint", "coin")
+		want = append(want, fmt.Sprintf("%s@%d", hash, i+2))
+	}
+	r := &recorder{}
+	w := watch.New(c, watch.Options{FromHeight: 2})
+	w.Subscribe("coin", watch.Asset("coin"), r.callback)
+	w.Subscribe("mint", watch.Address("mint"), r.callback)
+	poll(t, w)
+	expect(t, r, map[string][]string{"coin": want, "mint": want})
+
+	// Assets are paged newest first, until the blocks already polled.
+	c.next()
+	c.add("last", "mint", "coin")
+	before := c.callsOf("AssetTransactions")
+	poll(t, w)
+	expect(t, r, map[string][]string{"coin": {"last@252"}, "mint": {"last@252"}})
+	if got := c.callsOf("AssetTransactions") - before; got != 1 {
+		t.Fatalf("got %d asset requests, want 1", got)
+	}
+}
+
+func TestWatcherDirections(t *testing.T) {
+	c := newChain(1)
+	c.add("a", "alice", "bob")
+	c.add("b", "bob", "alice")
+	c.add("c", "alice", "alice")
+	r := &recorder{}
+	w := watch.New(c, watch.Options{FromHeight: 1, Directions: true})
+	w.Subscribe("alice", watch.Address("alice"), r.callback)
+	w.Subscribe("bob", watch.Address("bob"), r.callback)
+	poll(t, w)
+	expect(t, r, map[string][]string{
+		"alice": {"a@1 out", "b@1 in", "c@1 self"},
+		"bob":   {"a@1 in", "b@1 out"},
+	})
+	if got := c.callsOf("TransactionUTXOs"); got != 3 {
+		t.Fatalf("got %d UTXO requests, want 3", got)
+	}
+}
+
+func TestWatcherResume(t *testing.T) {
+	c := newChain(1)
+	store := watch.NewFileStore(filepath.Join(t.TempDir(), "watch.json"))
+	opts := watch.Options{FromHeight: 1, Store: store}
+	c.add("a", "alice", "bob")
+
+	r := &recorder{}
+	w := watch.New(c, opts)
+	w.Subscribe("bob", watch.Address("bob"), r.callback)
+	poll(t, w)
+	expect(t, r, map[string][]string{"bob": {"a@1"}})
+
+	cp, ok, err := store.Load(context.Background(), "bob")
+	if err != nil || !ok {
+		t.Fatalf("got %v, %v", ok, err)
+	}
+	if want := (watch.Checkpoint{Height: 1, Seen: []string{"a"}}); !reflect.DeepEqual(cp, want) {
+		t.Fatalf("got checkpoint %+v, want %+v", cp, want)
+	}
+
+	// A new watcher resumes from the checkpoint, including the transactions
+	// added to its block meanwhile.
+	c.add("b", "alice", "bob")
+	c.next()
+	c.add("c", "alice", "bob")
+	w = watch.New(c, opts)
+	w.Subscribe("bob", watch.Address("bob"), r.callback)
+	poll(t, w)
+	expect(t, r, map[string][]string{"bob": {"b@1", "c@2"}})
+}
+
+func TestWatcherCallbackError(t *testing.T) {
+	c := newChain(1)
+	c.add("a", "alice", "bob")
+	stop := errors.New("stop")
+	r := &recorder{err: stop}
+	store := &watch.MemoryStore{}
+	w := watch.New(c, watch.Options{FromHeight: 1, Store: store})
+	w.Subscribe("bob", watch.Address("bob"), r.callback)
+	if err := w.Run(context.Background()); !errors.Is(err, stop) {
+		t.Fatalf("got %v, want %v", err, stop)
+	}
+	if _, ok, _ := store.Load(context.Background(), "bob"); ok {
+		t.Fatal("checkpoint saved despite the callback error")
+	}
+}
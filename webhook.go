@@ -176,22 +176,26 @@ func verifyWebhookSignature(payload []byte, sigHeader string, secret string, tol
 		return nil, fmt.Errorf("Failed to parse specific webhook event json: %s", err)
 	}
 
+	return &event, checkWebhookSignature(payload, sigHeader, secret, tolerance, enforceTolerance)
+}
+
+func checkWebhookSignature(payload []byte, sigHeader string, secret string, tolerance time.Duration, enforceTolerance bool) error {
 	header, err := parseSignatureHeader(sigHeader)
 	if err != nil {
-		return &event, err
+		return err
 	}
 
 	expectedSignature := computeSignature(header.timestamp, payload, secret)
 	expiredTimestamp := time.Since(header.timestamp) > tolerance
 	if enforceTolerance && expiredTimestamp {
-		return &event, ErrTooOld
+		return ErrTooOld
 	}
 
 	for _, sig := range header.signatures {
 		if hmac.Equal(expectedSignature, sig) {
-			return &event, nil
+			return nil
 		}
 	}
 
-	return &event, ErrNoValidSignature
+	return ErrNoValidSignature
 }
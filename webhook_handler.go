@@ -0,0 +1,159 @@
+package blockfrost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultWebhookMaxBodyBytes is the default limit of the size of webhook
+// requests accepted by NewWebhookHandler.
+const DefaultWebhookMaxBodyBytes int64 = 512 * 1024
+
+// WebhookHandlerOptions configures the handler returned by
+// NewWebhookHandler. The callbacks of the event types not handled may be
+// nil, and their events are acknowledged without being dispatched.
+type WebhookHandlerOptions struct {
+	// MaxBodyBytes limits the size of request bodies. Default:
+	// DefaultWebhookMaxBodyBytes.
+	MaxBodyBytes int64
+
+	// Tolerance is the maximum age of signatures. Default:
+	// DefaultTolerance. Negative values accept signatures of any age.
+	Tolerance time.Duration
+
+	OnBlock       func(ctx context.Context, event WebhookEventBlock) error
+	OnTransaction func(ctx context.Context, event WebhookEventTransaction) error
+	OnEpoch       func(ctx context.Context, event WebhookEventEpoch) error
+	OnDelegation  func(ctx context.Context, event WebhookEventDelegation) error
+
+	// OnUnknown is called with the events of other types, along with their
+	// payload.
+	OnUnknown func(ctx context.Context, event WebhookEvent, payload json.RawMessage) error
+
+	// OnError is called with the errors of rejected requests and of
+	// callbacks.
+	OnError func(error)
+}
+
+type webhookHandler struct {
+	secret string
+	opts   WebhookHandlerOptions
+}
+
+// NewWebhookHandler returns an http.Handler receiving the webhook events
+// signed with secret, and dispatching them to the callbacks of opts.
+//
+// Requests that are too large, not signed with secret or not valid events
+// are rejected with a 4xx status. Callback errors are answered with a 500
+// status, so that Blockfrost retries the event later.
+func NewWebhookHandler(secret string, opts WebhookHandlerOptions) http.Handler {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultWebhookMaxBodyBytes
+	}
+	if opts.Tolerance == 0 {
+		opts.Tolerance = DefaultTolerance
+	}
+	return &webhookHandler{secret: secret, opts: opts}
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, http.StatusMethodNotAllowed, fmt.Errorf("webhook: method %s not allowed", req.Method))
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, req.Body, h.opts.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.fail(w, http.StatusRequestEntityTooLarge, fmt.Errorf("webhook: body larger than %d bytes", tooLarge.Limit))
+			return
+		}
+		h.fail(w, http.StatusBadRequest, fmt.Errorf("webhook: read body: %w", err))
+		return
+	}
+
+	sig := req.Header.Get("Blockfrost-Signature")
+	if err := checkWebhookSignature(payload, sig, h.secret, h.opts.Tolerance, h.opts.Tolerance > 0); err != nil {
+		h.fail(w, http.StatusBadRequest, fmt.Errorf("webhook: %w", err))
+		return
+	}
+
+	status, err := h.dispatch(req.Context(), payload)
+	if err != nil {
+		h.fail(w, status, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// dispatch decodes the event in payload and calls its callback. It returns
+// the status of the response when it fails.
+func (h *webhookHandler) dispatch(ctx context.Context, payload []byte) (int, error) {
+	var envelope struct {
+		WebhookEventCommon
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("webhook: invalid event: %w", err)
+	}
+	common := envelope.WebhookEventCommon
+
+	var (
+		decodeErr error
+		call      func() error
+	)
+	switch WebhookEventType(common.Type) {
+	case WebhookEventTypeBlock:
+		event := WebhookEventBlock{WebhookEventCommon: common}
+		decodeErr = json.Unmarshal(envelope.Payload, &event.Payload)
+		if h.opts.OnBlock != nil {
+			call = func() error { return h.opts.OnBlock(ctx, event) }
+		}
+	case WebhookEventTypeTransaction:
+		event := WebhookEventTransaction{WebhookEventCommon: common}
+		decodeErr = json.Unmarshal(envelope.Payload, &event.Payload)
+		if h.opts.OnTransaction != nil {
+			call = func() error { return h.opts.OnTransaction(ctx, event) }
+		}
+	case WebhookEventTypeEpoch:
+		event := WebhookEventEpoch{WebhookEventCommon: common}
+		decodeErr = json.Unmarshal(envelope.Payload, &event.Payload)
+		if h.opts.OnEpoch != nil {
+			call = func() error { return h.opts.OnEpoch(ctx, event) }
+		}
+	case WebhookEventTypeDelegation:
+		event := WebhookEventDelegation{WebhookEventCommon: common}
+		decodeErr = json.Unmarshal(envelope.Payload, &event.Payload)
+		if h.opts.OnDelegation != nil {
+			call = func() error { return h.opts.OnDelegation(ctx, event) }
+		}
+	default:
+		if h.opts.OnUnknown != nil {
+			call = func() error { return h.opts.OnUnknown(ctx, WebhookEvent{common}, envelope.Payload) }
+		}
+	}
+	if decodeErr != nil {
+		return http.StatusBadRequest, fmt.Errorf("webhook: invalid %s event %s: %w", common.Type, common.ID, decodeErr)
+	}
+	if call == nil {
+		return http.StatusOK, nil
+	}
+	if err := call(); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("webhook: %s event %s: %w", common.Type, common.ID, err)
+	}
+	return http.StatusOK, nil
+}
+
+func (h *webhookHandler) fail(w http.ResponseWriter, status int, err error) {
+	if h.opts.OnError != nil {
+		h.opts.OnError(err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
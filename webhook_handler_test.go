@@ -0,0 +1,127 @@
+package blockfrost_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+)
+
+const webhookSecret = "59a1eb46-96f4-4f0b-8a03-b4d26e70593a"
+
+func signWebhook(payload string, t time.Time) string {
+	mac := hmac.New(sha256.New, []byte(webhookSecret))
+	fmt.Fprintf(mac, "%d.%s", t.Unix(), payload)
+	return fmt.Sprintf("t=%d,v1=%s", t.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
+
+func postWebhook(h http.Handler, payload, signature string) int {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	if signature != "" {
+		req.Header.Set("Blockfrost-Signature", signature)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var (
+		blocks  []blockfrost.WebhookEventBlock
+		unknown []string
+		errs    []error
+	)
+	h := blockfrost.NewWebhookHandler(webhookSecret, blockfrost.WebhookHandlerOptions{
+		OnBlock: func(ctx context.Context, event blockfrost.WebhookEventBlock) error {
+			blocks = append(blocks, event)
+			return nil
+		},
+		OnUnknown: func(ctx context.Context, event blockfrost.WebhookEvent, payload json.RawMessage) error {
+			unknown = append(unknown, event.Type+" "+string(payload))
+			return nil
+		},
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+	now := time.Now()
+
+	if code := postWebhook(h, validPayload, signWebhook(validPayload, now)); code != http.StatusOK {
+		t.Fatalf("got status %d", code)
+	}
+	if len(blocks) != 1 || blocks[0].ID != "47668401-c3a4-42d4-bac1-ad46515924a3" || blocks[0].Payload.Height != 7126256 {
+		t.Fatalf("got %+v", blocks)
+	}
+
+	// Events without callback are acknowledged.
+	epoch := `{"id":"e","type":"epoch","payload":{"previous_epoch":{"epoch":343},"current_epoch":{"epoch":344}}}`
+	if code := postWebhook(h, epoch, signWebhook(epoch, now)); code != http.StatusOK {
+		t.Fatalf("got status %d", code)
+	}
+	other := `{"id":"o","type":"governance","payload":{"a":1}}`
+	if code := postWebhook(h, other, signWebhook(other, now)); code != http.StatusOK {
+		t.Fatalf("got status %d", code)
+	}
+	if len(unknown) != 1 || unknown[0] != `governance {"a":1}` {
+		t.Fatalf("got %v", unknown)
+	}
+
+	tests := []struct {
+		name      string
+		payload   string
+		signature string
+		want      int
+	}{
+		{"unsigned", validPayload, "", http.StatusBadRequest},
+		{"wrong signature", validPayload, signWebhook(epoch, now), http.StatusBadRequest},
+		{"too old", validPayload, signWebhook(validPayload, now.Add(-time.Hour)), http.StatusBadRequest},
+		{"invalid json", "{", signWebhook("{", now), http.StatusBadRequest},
+		{"invalid payload", `{"type":"block","payload":[]}`, signWebhook(`{"type":"block","payload":[]}`, now), http.StatusBadRequest},
+		{"too large", strings.Repeat(" ", 600*1024), "t=1,v1=00", http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := postWebhook(h, tt.payload, tt.signature); code != tt.want {
+				t.Fatalf("got status %d, want %d", code, tt.want)
+			}
+		})
+	}
+	if len(errs) != len(tests) || len(blocks) != 1 {
+		t.Fatalf("got %d errors and %d blocks", len(errs), len(blocks))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/webhook", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got status %d", rec.Code)
+	}
+}
+
+func TestWebhookHandlerCallbackError(t *testing.T) {
+	failed := errors.New("database unavailable")
+	var errs []error
+	h := blockfrost.NewWebhookHandler(webhookSecret, blockfrost.WebhookHandlerOptions{
+		OnBlock: func(ctx context.Context, event blockfrost.WebhookEventBlock) error {
+			return failed
+		},
+		OnError: func(err error) { errs = append(errs, err) },
+		// The signature of validPayload is from 2022.
+		Tolerance: -1,
+	})
+	signature := "t=1650013856,v1=f4c3bb2a8b0c8e21fa7d5fdada2ee87c9c6f6b0b159cc22e483146917e195c3e"
+	if code := postWebhook(h, validPayload, signature); code != http.StatusInternalServerError {
+		t.Fatalf("got status %d, want 500 so that the event is retried", code)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], failed) {
+		t.Fatalf("got %v", errs)
+	}
+}